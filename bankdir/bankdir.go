@@ -0,0 +1,132 @@
+// Package bankdir resolves the bank name and BIC behind an IBAN from an
+// offline directory keyed by country and national bank code.
+package bankdir
+
+import (
+	_ "embed"
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"sync"
+
+	"github.com/tapsilat/iban.im/iso13616"
+)
+
+// Bank is a single directory entry.
+type Bank struct {
+	Country  string
+	BankCode string
+	Name     string
+	BIC      string
+}
+
+//go:embed banks.csv
+var embedded string
+
+var (
+	mu    sync.RWMutex
+	banks map[string]Bank
+)
+
+func init() {
+	dir, err := parse(strings.NewReader(embedded))
+	if err != nil {
+		panic(fmt.Sprintf("bankdir: embedded directory: %v", err))
+	}
+	banks = dir
+}
+
+// Lookup returns the bank registered for the country and bank code.
+func Lookup(country, bankCode string) (Bank, bool) {
+	mu.RLock()
+	defer mu.RUnlock()
+	bank, ok := banks[key(country, bankCode)]
+	return bank, ok
+}
+
+// LookupIBAN decomposes iban and looks up its bank code.
+func LookupIBAN(iban string) (Bank, bool) {
+	parts, err := iso13616.Decompose(iban)
+	if err != nil || parts.BankCode == "" {
+		return Bank{}, false
+	}
+	return Lookup(parts.CountryCode, parts.BankCode)
+}
+
+// Load replaces the directory with the CSV read from r. The first row is a
+// header naming the country, bank_code, name and bic columns.
+func Load(r io.Reader) error {
+	dir, err := parse(r)
+	if err != nil {
+		return err
+	}
+	mu.Lock()
+	banks = dir
+	mu.Unlock()
+	return nil
+}
+
+// LoadFile replaces the directory with the CSV file at path.
+func LoadFile(path string) error {
+	f, err := os.Open(path)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+	return Load(f)
+}
+
+// Len returns the number of banks in the directory.
+func Len() int {
+	mu.RLock()
+	defer mu.RUnlock()
+	return len(banks)
+}
+
+func parse(r io.Reader) (map[string]Bank, error) {
+	reader := csv.NewReader(r)
+	reader.TrimLeadingSpace = true
+
+	header, err := reader.Read()
+	if err != nil {
+		return nil, fmt.Errorf("read header: %w", err)
+	}
+	columns := map[string]int{}
+	for i, name := range header {
+		columns[strings.ToLower(strings.TrimSpace(name))] = i
+	}
+	for _, name := range []string{"country", "bank_code", "name", "bic"} {
+		if _, ok := columns[name]; !ok {
+			return nil, fmt.Errorf("missing column %q", name)
+		}
+	}
+
+	dir := map[string]Bank{}
+	for {
+		record, err := reader.Read()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return nil, err
+		}
+		bank := Bank{
+			Country:  strings.ToUpper(strings.TrimSpace(record[columns["country"]])),
+			BankCode: strings.ToUpper(strings.TrimSpace(record[columns["bank_code"]])),
+			Name:     strings.TrimSpace(record[columns["name"]]),
+			BIC:      strings.ToUpper(strings.TrimSpace(record[columns["bic"]])),
+		}
+		if bank.Country == "" || bank.BankCode == "" {
+			continue
+		}
+		dir[key(bank.Country, bank.BankCode)] = bank
+	}
+	return dir, nil
+}
+
+func key(country, bankCode string) string {
+	return strings.ToUpper(country) + ":" + strings.ToUpper(bankCode)
+}
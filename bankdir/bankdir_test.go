@@ -0,0 +1,92 @@
+package bankdir
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestLookupIBAN(t *testing.T) {
+	tests := []struct {
+		name     string
+		iban     string
+		expected Bank
+		found    bool
+	}{
+		{
+			name:     "TR bank code",
+			iban:     "TR320010009999901234567890",
+			expected: Bank{Country: "TR", BankCode: "00100", Name: "Adabank", BIC: "ADABTRISXXX"},
+			found:    true,
+		},
+		{
+			name:     "DE bank code",
+			iban:     "DE89 3704 0044 0532 0130 00",
+			expected: Bank{Country: "DE", BankCode: "37040044", Name: "Commerzbank", BIC: "COBADEFFXXX"},
+			found:    true,
+		},
+		{
+			name:     "NL alphabetic bank code",
+			iban:     "NL91ABNA0417164300",
+			expected: Bank{Country: "NL", BankCode: "ABNA", Name: "ABN AMRO Bank", BIC: "ABNANL2AXXX"},
+			found:    true,
+		},
+		{
+			name:  "Unknown bank",
+			iban:  "GB82WEST12345698765432",
+			found: false,
+		},
+		{
+			name:  "Invalid IBAN",
+			iban:  "TR420010009999901234567891",
+			found: false,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			bank, ok := LookupIBAN(tt.iban)
+			if ok != tt.found {
+				t.Fatalf("LookupIBAN(%q) found = %v, want %v", tt.iban, ok, tt.found)
+			}
+			if ok && bank != tt.expected {
+				t.Errorf("LookupIBAN(%q) = %+v, want %+v", tt.iban, bank, tt.expected)
+			}
+		})
+	}
+}
+
+func TestLoadFile(t *testing.T) {
+	original := Len()
+	defer Load(strings.NewReader(embedded))
+
+	path := filepath.Join(t.TempDir(), "banks.csv")
+	data := "bic,name,country,bank_code\nWESTGB22,Westminster Test Bank,gb,west\n"
+	if err := os.WriteFile(path, []byte(data), 0o600); err != nil {
+		t.Fatalf("Failed to write directory: %v", err)
+	}
+
+	if err := LoadFile(path); err != nil {
+		t.Fatalf("LoadFile() returned unexpected error: %v", err)
+	}
+	if Len() != 1 {
+		t.Errorf("Len() = %d, want 1 (was %d)", Len(), original)
+	}
+	bank, ok := LookupIBAN("GB82WEST12345698765432")
+	if !ok || bank.Name != "Westminster Test Bank" || bank.BIC != "WESTGB22" {
+		t.Errorf("LookupIBAN() = %+v, %v", bank, ok)
+	}
+}
+
+func TestLoadMissingColumn(t *testing.T) {
+	defer Load(strings.NewReader(embedded))
+
+	err := Load(strings.NewReader("country,bank_code,name\nTR,00010,Ziraat\n"))
+	if err == nil || err.Error() != `missing column "bic"` {
+		t.Errorf("Load() error = %v, want missing column", err)
+	}
+	if _, ok := Lookup("TR", "00010"); !ok {
+		t.Error("failed Load should keep the previous directory")
+	}
+}
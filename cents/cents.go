@@ -0,0 +1,39 @@
+// Package cents reads decimal money amounts such as "12.50" or "12,5" into
+// cents, the unit amounts are kept in throughout iban.im.
+package cents
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// MaxDigits is the most digits an amount may have before the decimal point.
+const MaxDigits = 15
+
+// ErrAmount is returned by Parse for anything but a non-negative decimal
+// amount with at most two significant decimals.
+var ErrAmount = errors.New("amount must be a decimal number with at most two decimals, e.g. 12.50")
+
+// Parse converts a decimal amount into cents. The decimal separator may be a
+// point or a comma; decimals beyond cents must be zero, e.g. "1.50000".
+func Parse(s string) (int64, error) {
+	whole, frac, _ := strings.Cut(strings.Replace(strings.TrimSpace(s), ",", ".", 1), ".")
+	if whole == "" || len(whole) > MaxDigits || strings.Trim(whole+frac, "0123456789") != "" {
+		return 0, ErrAmount
+	}
+	if strings.Trim(frac[min(len(frac), 2):], "0") != "" {
+		return 0, ErrAmount
+	}
+	frac = (frac + "00")[:2]
+	units, _ := strconv.ParseInt(whole, 10, 64)
+	fraction, _ := strconv.ParseInt(frac, 10, 64)
+	return units*100 + fraction, nil
+}
+
+// Format renders cents as decimal amount such as "12.50", the reverse of
+// Parse.
+func Format(cents int64) string {
+	return fmt.Sprintf("%d.%02d", cents/100, cents%100)
+}
@@ -0,0 +1,43 @@
+package cents
+
+import "testing"
+
+func TestParse(t *testing.T) {
+	tests := []struct {
+		amount  string
+		want    int64
+		wantErr bool
+	}{
+		{amount: "12.50", want: 1250},
+		{amount: "12,5", want: 1250},
+		{amount: " 7 ", want: 700},
+		{amount: "0.01", want: 1},
+		{amount: "0", want: 0},
+		{amount: "1.50000000", want: 150},
+		{amount: "999999999999999.99", want: 99999999999999999},
+		{amount: "1000000000000000", wantErr: true},
+		{amount: "1.005", wantErr: true},
+		{amount: "1.2.3", wantErr: true},
+		{amount: "-5", wantErr: true},
+		{amount: "+5", wantErr: true},
+		{amount: ".5", wantErr: true},
+		{amount: "abc", wantErr: true},
+		{amount: "", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		got, err := Parse(tt.amount)
+		if tt.wantErr {
+			if err == nil {
+				t.Errorf("Parse(%q) = %d, want error", tt.amount, got)
+			}
+			continue
+		}
+		if err != nil || got != tt.want {
+			t.Errorf("Parse(%q) = %d, %v, want %d", tt.amount, got, err, tt.want)
+		}
+		if back, _ := Parse(Format(got)); back != got {
+			t.Errorf("Format(%d) = %s does not parse back", got, Format(got))
+		}
+	}
+}
@@ -33,6 +33,12 @@ type App struct {
 	MaxRefresh uint   `env:"APP_MAX_REFRESH" envDefault:"5"`
 	Key        string `env:"APP_KEY"`
 	Realm      string `env:"APP_REALM"`
+	// BankDirectory is an optional CSV replacing the embedded bank directory
+	BankDirectory string `env:"APP_BANK_DIRECTORY"`
+	// IbanRegistry is an optional rules file written by tools/registry
+	IbanRegistry string `env:"APP_IBAN_REGISTRY"`
+	// ValidateRowLimit caps the rows of one batch validation request
+	ValidateRowLimit int `env:"APP_VALIDATE_ROW_LIMIT" envDefault:"10000"`
 }
 
 type Config struct {
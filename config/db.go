@@ -71,5 +71,21 @@ func InitDB(cfg *Config) {
 	sqlDB.SetMaxOpenConns(30)
 	sqlDB.SetConnMaxLifetime(time.Second * 60)
 
-	DB.AutoMigrate(&model.User{}, &model.Iban{}, &model.Group{})
+	DB.AutoMigrate(&model.User{}, &model.Iban{}, &model.Group{}, &model.RetiredGroupHandle{}, &model.GroupMember{}, &model.IbanChange{}, &model.PaymentRequest{}, &model.StatementEntry{})
+	if err := model.BackfillMethods(DB); err != nil {
+		log.Printf("Failed to backfill payment methods: %v", err)
+	}
+	if err := model.BackfillOwnerTypes(DB); err != nil {
+		log.Printf("Failed to backfill IBAN owner types: %v", err)
+	}
+	if err := model.BackfillGroupOwners(DB); err != nil {
+		log.Printf("Failed to backfill group owners: %v", err)
+	}
+	if users, err := model.UsersWithReservedHandles(DB); err != nil {
+		log.Printf("Failed to check for reserved user handles: %v", err)
+	} else {
+		for _, user := range users {
+			log.Printf("User %d has the reserved handle %q, their public pages are unreachable until they change it", user.UserID, user.Handle)
+		}
+	}
 }
@@ -0,0 +1,206 @@
+// Package domainverify checks that the owner of a domain published a token,
+// either as DNS TXT record on the domain, e.g.
+//
+//	acme.example. TXT "iban-im-verification=3f9c1d0e8b7a6f5e"
+//
+// or as the file https://acme.example/.well-known/iban-im.txt holding the
+// token or the same line.
+package domainverify
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/netip"
+	"strings"
+	"syscall"
+	"time"
+)
+
+// RecordPrefix starts the TXT record and file line carrying the token.
+const RecordPrefix = "iban-im-verification="
+
+// WellKnownPath is where the token file is looked up on the domain.
+const WellKnownPath = "/.well-known/iban-im.txt"
+
+// maxFile is the longest token file read.
+const maxFile = 4096
+
+// Methods by which a domain is verified.
+const (
+	MethodDNS  = "dns"
+	MethodHTTP = "http"
+)
+
+// ErrNotFound is returned by Verify when neither check finds the token.
+var ErrNotFound = errors.New("verification token not found in DNS or at " + WellKnownPath)
+
+// ErrNotPublic is returned when a domain points to a loopback, private or
+// otherwise non-public address.
+var ErrNotPublic = errors.New("not a public address")
+
+// TXTResolver looks up the TXT records of a domain; *net.Resolver is one.
+type TXTResolver interface {
+	LookupTXT(ctx context.Context, name string) ([]string, error)
+}
+
+// Fetcher returns the body of an https address.
+type Fetcher interface {
+	Fetch(ctx context.Context, url string) ([]byte, error)
+}
+
+// Verifier checks domains through DNS and HTTP.
+type Verifier struct {
+	DNS  TXTResolver
+	HTTP Fetcher
+}
+
+// Default verifies domains over the network.
+var Default = Verifier{
+	DNS:  net.DefaultResolver,
+	HTTP: HTTPFetcher{Client: PublicClient(10 * time.Second)},
+}
+
+// nonPublic are the special-purpose ranges that IsPublic rejects beyond
+// loopback, link-local, multicast and private addresses.
+var nonPublic = []netip.Prefix{
+	netip.MustParsePrefix("0.0.0.0/8"),
+	netip.MustParsePrefix("100.64.0.0/10"),
+	netip.MustParsePrefix("192.0.0.0/24"),
+	netip.MustParsePrefix("198.18.0.0/15"),
+	netip.MustParsePrefix("240.0.0.0/4"),
+	netip.MustParsePrefix("64:ff9b::/96"),
+	netip.MustParsePrefix("2001:db8::/32"),
+}
+
+// IsPublic reports whether ip is reachable on the internet, so that fetching
+// from it cannot reach the server itself, its network or cloud metadata.
+func IsPublic(ip netip.Addr) bool {
+	ip = ip.Unmap()
+	if !ip.IsGlobalUnicast() || ip.IsPrivate() {
+		return false
+	}
+	for _, prefix := range nonPublic {
+		if prefix.Contains(ip) {
+			return false
+		}
+	}
+	return true
+}
+
+// PublicClient returns an HTTP client that only connects to public
+// addresses. The check runs on the resolved address of every connection,
+// so neither DNS names pointing inwards nor redirects get around it.
+func PublicClient(timeout time.Duration) *http.Client {
+	dialer := &net.Dialer{Timeout: timeout, Control: dialPublic}
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	// A proxy would be dialed instead of the domain
+	transport.Proxy = nil
+	transport.DialContext = dialer.DialContext
+	return &http.Client{
+		Timeout:   timeout,
+		Transport: transport,
+		CheckRedirect: func(next *http.Request, via []*http.Request) error {
+			host := next.URL.Hostname()
+			if ip, err := netip.ParseAddr(host); (err == nil && !IsPublic(ip)) || strings.EqualFold(host, "localhost") {
+				return fmt.Errorf("redirect to %s: %w", host, ErrNotPublic)
+			}
+			return nil
+		},
+	}
+}
+
+// dialPublic is the net.Dialer Control of PublicClient.
+func dialPublic(network, address string, _ syscall.RawConn) error {
+	host, _, err := net.SplitHostPort(address)
+	if err != nil {
+		return err
+	}
+	ip, err := netip.ParseAddr(host)
+	if err != nil || !IsPublic(ip) {
+		return fmt.Errorf("%s: %w", host, ErrNotPublic)
+	}
+	return nil
+}
+
+// Record is the TXT record or file line that proves ownership with token.
+func Record(token string) string {
+	return RecordPrefix + token
+}
+
+// FileURL is the address of the token file of domain.
+func FileURL(domain string) string {
+	return "https://" + domain + WellKnownPath
+}
+
+// Verify looks for token in the TXT records of domain and then in its token
+// file, and returns the method that found it.
+func (v Verifier) Verify(ctx context.Context, domain, token string) (string, error) {
+	if domain == "" || token == "" {
+		return "", ErrNotFound
+	}
+	if v.DNS != nil {
+		records, err := v.DNS.LookupTXT(ctx, domain)
+		if err == nil && contains(records, token) {
+			return MethodDNS, nil
+		}
+	}
+	if v.HTTP != nil {
+		body, err := v.HTTP.Fetch(ctx, FileURL(domain))
+		if err == nil && contains(strings.Split(string(body), "\n"), token) {
+			return MethodHTTP, nil
+		}
+	}
+	return "", ErrNotFound
+}
+
+// contains reports whether one of lines is token or its record.
+func contains(lines []string, token string) bool {
+	for _, line := range lines {
+		line = strings.TrimSpace(line)
+		if line == token || line == Record(token) {
+			return true
+		}
+	}
+	return false
+}
+
+// HTTPFetcher fetches token files with Client and does not follow redirects
+// to other hosts, so the file must be served by the domain itself. The
+// CheckRedirect of Client, if any, is asked as well.
+type HTTPFetcher struct {
+	Client *http.Client
+}
+
+// Fetch returns the body of url if it answers 200 OK.
+func (f HTTPFetcher) Fetch(ctx context.Context, url string) ([]byte, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return nil, err
+	}
+	client := *f.Client
+	client.CheckRedirect = func(next *http.Request, via []*http.Request) error {
+		if next.URL.Host != via[0].URL.Host || next.URL.Scheme != "https" {
+			return fmt.Errorf("redirect to %s", next.URL)
+		}
+		if len(via) >= 5 {
+			return errors.New("too many redirects")
+		}
+		if f.Client.CheckRedirect != nil {
+			return f.Client.CheckRedirect(next, via)
+		}
+		return nil
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("%s: %s", url, resp.Status)
+	}
+	return io.ReadAll(io.LimitReader(resp.Body, maxFile))
+}
@@ -0,0 +1,170 @@
+package domainverify
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"net/netip"
+	"testing"
+	"time"
+)
+
+type fakeDNS map[string][]string
+
+func (f fakeDNS) LookupTXT(ctx context.Context, name string) ([]string, error) {
+	records, ok := f[name]
+	if !ok {
+		return nil, errors.New("no such host")
+	}
+	return records, nil
+}
+
+type fakeHTTP map[string]string
+
+func (f fakeHTTP) Fetch(ctx context.Context, url string) ([]byte, error) {
+	body, ok := f[url]
+	if !ok {
+		return nil, errors.New("404 Not Found")
+	}
+	return []byte(body), nil
+}
+
+func TestVerify(t *testing.T) {
+	v := Verifier{
+		DNS: fakeDNS{
+			"acme.example":  {"v=spf1 -all", "iban-im-verification=abc123"},
+			"other.example": {"iban-im-verification=abc1234"},
+		},
+		HTTP: fakeHTTP{
+			"https://files.example/.well-known/iban-im.txt":  "abc123\n",
+			"https://record.example/.well-known/iban-im.txt": "# iban.im\r\niban-im-verification=abc123\r\n",
+		},
+	}
+
+	tests := []struct {
+		name   string
+		domain string
+		token  string
+		want   string
+	}{
+		{name: "TXT record", domain: "acme.example", token: "abc123", want: MethodDNS},
+		{name: "Token file", domain: "files.example", token: "abc123", want: MethodHTTP},
+		{name: "Record in token file", domain: "record.example", token: "abc123", want: MethodHTTP},
+		{name: "Other token", domain: "other.example", token: "abc123"},
+		{name: "Unknown domain", domain: "unknown.example", token: "abc123"},
+		{name: "No token", domain: "files.example", token: ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := v.Verify(context.Background(), tt.domain, tt.token)
+			if got != tt.want {
+				t.Errorf("Verify() = %q, want %q", got, tt.want)
+			}
+			if tt.want == "" && !errors.Is(err, ErrNotFound) {
+				t.Errorf("Verify() error = %v, want ErrNotFound", err)
+			}
+		})
+	}
+}
+
+func TestHTTPFetcher(t *testing.T) {
+	server := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case WellKnownPath:
+			w.Write([]byte("abc123\n"))
+		case "/moved":
+			http.Redirect(w, r, WellKnownPath, http.StatusFound)
+		case "/elsewhere":
+			http.Redirect(w, r, "https://evil.example"+WellKnownPath, http.StatusFound)
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	defer server.Close()
+	f := HTTPFetcher{Client: server.Client()}
+
+	tests := []struct {
+		name    string
+		path    string
+		want    string
+		wantErr bool
+	}{
+		{name: "Token file", path: WellKnownPath, want: "abc123\n"},
+		{name: "Redirect on the same host", path: "/moved", want: "abc123\n"},
+		{name: "Redirect to another host", path: "/elsewhere", wantErr: true},
+		{name: "Missing file", path: "/missing", wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			body, err := f.Fetch(context.Background(), server.URL+tt.path)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("Fetch() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if string(body) != tt.want {
+				t.Errorf("Fetch() = %q, want %q", body, tt.want)
+			}
+		})
+	}
+}
+
+func TestIsPublic(t *testing.T) {
+	tests := []struct {
+		ip   string
+		want bool
+	}{
+		{ip: "93.184.215.14", want: true},
+		{ip: "2606:2800:21f:cb07:6820:80da:af6b:8b2c", want: true},
+		{ip: "127.0.0.1"},
+		{ip: "10.1.2.3"},
+		{ip: "172.16.0.1"},
+		{ip: "192.168.1.1"},
+		{ip: "169.254.169.254"},
+		{ip: "100.64.0.1"},
+		{ip: "0.0.0.0"},
+		{ip: "224.0.0.1"},
+		{ip: "::1"},
+		{ip: "fd00:ec2::254"},
+		{ip: "fe80::1"},
+		{ip: "::ffff:127.0.0.1"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.ip, func(t *testing.T) {
+			if got := IsPublic(netip.MustParseAddr(tt.ip)); got != tt.want {
+				t.Errorf("IsPublic(%s) = %v, want %v", tt.ip, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestPublicClient(t *testing.T) {
+	server := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte("abc123\n"))
+	}))
+	defer server.Close()
+	client := PublicClient(time.Second)
+
+	f := HTTPFetcher{Client: client}
+	if _, err := f.Fetch(context.Background(), server.URL+WellKnownPath); !errors.Is(err, ErrNotPublic) {
+		t.Errorf("Fetch() from loopback error = %v, want %v", err, ErrNotPublic)
+	}
+
+	tests := []struct {
+		name    string
+		url     string
+		wantErr bool
+	}{
+		{name: "Public host", url: "https://acme.example" + WellKnownPath},
+		{name: "Cloud metadata", url: "http://169.254.169.254/latest/meta-data/", wantErr: true},
+		{name: "Loopback", url: "https://[::1]" + WellKnownPath, wantErr: true},
+		{name: "Localhost", url: "https://localhost" + WellKnownPath, wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			next := httptest.NewRequest(http.MethodGet, tt.url, nil)
+			if err := client.CheckRedirect(next, nil); (err != nil) != tt.wantErr {
+				t.Errorf("CheckRedirect() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
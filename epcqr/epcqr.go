@@ -0,0 +1,96 @@
+// Package epcqr builds the payload of the European Payments Council QR code
+// for SEPA credit transfers (EPC069-12, known as "GiroCode").
+package epcqr
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/tapsilat/iban.im/iso13616"
+	"github.com/tapsilat/iban.im/iso9362"
+)
+
+// Errors returned by Payload.
+var (
+	ErrNotSEPA = errors.New("EPC QR codes are only available for SEPA IBANs")
+	ErrAmount  = errors.New("amount must be between 0.01 and 999999999.99 EUR")
+	ErrField   = errors.New("EPC QR field too long")
+)
+
+// MaxAmount is the largest transfer an EPC QR code can carry, in cents.
+const MaxAmount = 99999999999
+
+// Payment holds the credit transfer details encoded in the QR code.
+type Payment struct {
+	BIC  string
+	Name string
+	IBAN string
+	// Amount in euro cents, 0 leaves the amount to the payer.
+	Amount int64
+	// Purpose is an optional ISO 20022 purpose code such as "CHAR".
+	Purpose string
+	// Reference is a structured creditor reference; it cannot be combined
+	// with Text.
+	Reference string
+	// Text is the unstructured remittance information.
+	Text string
+	// Info is shown to the payer but not passed on with the transfer.
+	Info string
+}
+
+// Payload renders p in version 002 of the format with UTF-8 text, which
+// makes the BIC optional.
+func (p Payment) Payload() (string, error) {
+	iban := iso13616.Normalize(p.IBAN)
+	if err := iso13616.Validate(iban); err != nil {
+		return "", err
+	}
+	if !iso13616.IsSEPA(iban) {
+		return "", ErrNotSEPA
+	}
+	bic := iso9362.Normalize(p.BIC)
+	if bic != "" {
+		if err := iso9362.Validate(bic); err != nil {
+			return "", err
+		}
+	}
+	if p.Amount < 0 || p.Amount > MaxAmount {
+		return "", ErrAmount
+	}
+	if p.Reference != "" && p.Text != "" {
+		return "", errors.New("EPC QR codes carry either a reference or a remittance text")
+	}
+
+	name := strings.TrimSpace(p.Name)
+	if name == "" {
+		return "", errors.New("EPC QR codes need the beneficiary name")
+	}
+	fields := []struct {
+		label, value string
+		max          int
+	}{
+		{"name", name, 70},
+		{"purpose", p.Purpose, 4},
+		{"reference", p.Reference, 35},
+		{"remittance text", p.Text, 140},
+		{"information", p.Info, 70},
+	}
+	for _, f := range fields {
+		if utf8.RuneCountInString(f.value) > f.max {
+			return "", fmt.Errorf("%w: %s must be at most %d characters", ErrField, f.label, f.max)
+		}
+	}
+
+	amount := ""
+	if p.Amount > 0 {
+		amount = fmt.Sprintf("EUR%d.%02d", p.Amount/100, p.Amount%100)
+	}
+	lines := []string{"BCD", "002", "1", "SCT", bic, name, iban, amount, p.Purpose, p.Reference, p.Text, p.Info}
+	// Trailing empty elements may be left out
+	for lines[len(lines)-1] == "" {
+		lines = lines[:len(lines)-1]
+	}
+	return strings.Join(lines, "\n"), nil
+}
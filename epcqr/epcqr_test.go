@@ -0,0 +1,83 @@
+package epcqr
+
+import (
+	"errors"
+	"strings"
+	"testing"
+)
+
+func TestPayload(t *testing.T) {
+	tests := []struct {
+		name    string
+		payment Payment
+		want    string
+		wantErr error
+	}{
+		{
+			name:    "Minimal",
+			payment: Payment{Name: "Franz Mustermann", IBAN: "DE89 3704 0044 0532 0130 00"},
+			want:    "BCD\n002\n1\nSCT\n\nFranz Mustermann\nDE89370400440532013000",
+		},
+		{
+			name:    "With BIC, amount and text",
+			payment: Payment{BIC: "cobadeff", Name: "Franz Mustermann", IBAN: "DE89370400440532013000", Amount: 1250, Text: "Dinner"},
+			want:    "BCD\n002\n1\nSCT\nCOBADEFF\nFranz Mustermann\nDE89370400440532013000\nEUR12.50\n\n\nDinner",
+		},
+		{
+			name:    "With reference",
+			payment: Payment{Name: "Franz Mustermann", IBAN: "DE89370400440532013000", Amount: 5, Reference: "RF18539007547034"},
+			want:    "BCD\n002\n1\nSCT\n\nFranz Mustermann\nDE89370400440532013000\nEUR0.05\n\nRF18539007547034",
+		},
+		{
+			name:    "Not SEPA",
+			payment: Payment{Name: "Ali Veli", IBAN: "TR330006100519786457841326"},
+			wantErr: ErrNotSEPA,
+		},
+		{
+			name:    "Amount too large",
+			payment: Payment{Name: "Franz Mustermann", IBAN: "DE89370400440532013000", Amount: MaxAmount + 1},
+			wantErr: ErrAmount,
+		},
+		{
+			name:    "Name too long",
+			payment: Payment{Name: strings.Repeat("a", 71), IBAN: "DE89370400440532013000"},
+			wantErr: ErrField,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := tt.payment.Payload()
+			if tt.wantErr != nil {
+				if !errors.Is(err, tt.wantErr) {
+					t.Errorf("Payload() error = %v, want %v", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Payload() returned unexpected error: %v", err)
+			}
+			if got != tt.want {
+				t.Errorf("Payload() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestPayloadErrors(t *testing.T) {
+	tests := []struct {
+		name    string
+		payment Payment
+	}{
+		{name: "Invalid IBAN", payment: Payment{Name: "Franz Mustermann", IBAN: "DE88370400440532013000"}},
+		{name: "Invalid BIC", payment: Payment{BIC: "COBA", Name: "Franz Mustermann", IBAN: "DE89370400440532013000"}},
+		{name: "No name", payment: Payment{IBAN: "DE89370400440532013000"}},
+		{name: "Reference and text", payment: Payment{Name: "Franz Mustermann", IBAN: "DE89370400440532013000", Reference: "RF18539007547034", Text: "Dinner"}},
+	}
+
+	for _, tt := range tests {
+		if _, err := tt.payment.Payload(); err == nil {
+			t.Errorf("%s: expected error but got none", tt.name)
+		}
+	}
+}
@@ -1,12 +1,16 @@
 package handler
 
 import (
+	"html/template"
 	"net/http"
+	"net/url"
 	"strings"
 
 	"github.com/gin-gonic/gin"
-	"github.com/tapsilat/iban.im/config"
+	"github.com/tapsilat/iban.im/bankdir"
+	"github.com/tapsilat/iban.im/iso13616"
 	"github.com/tapsilat/iban.im/model"
+	"github.com/tapsilat/iban.im/payto"
 )
 
 // GetIbanByHandles retrieves an IBAN by user handle and IBAN handle
@@ -14,91 +18,149 @@ func GetIbanByHandles(c *gin.Context) {
 	userHandle := c.Param("userHandle")
 	ibanHandle := c.Param("ibanHandle")
 
-	// Find user by handle
-	var user model.User
-	if err := config.DB.Where("handle = ?", userHandle).First(&user).Error; err != nil {
-		c.JSON(http.StatusNotFound, gin.H{
-			"error": "User not found",
-		})
-		return
+	// Find IBAN by handle and owner, other payment methods have no IBAN
+	_, iban, err := findPublicIban(userHandle, ibanHandle)
+	if err == nil && !iban.IsIBAN() {
+		err = errIbanNotFound
 	}
-
-	// Find IBAN by handle and owner
-	var iban model.Iban
-	if err := config.DB.Where("owner_id = ? AND handle = ? AND is_private = false", user.UserID, ibanHandle).First(&iban).Error; err != nil {
+	if err != nil {
 		c.JSON(http.StatusNotFound, gin.H{
-			"error": "IBAN not found or is private",
+			"error": err.Error(),
 		})
 		return
 	}
 
 	// Return IBAN information
 	c.JSON(http.StatusOK, gin.H{
-		"userHandle": userHandle,
-		"ibanHandle": ibanHandle,
-		"iban":       iban.Text,
+		"userHandle":  userHandle,
+		"ibanHandle":  ibanHandle,
+		"iban":        iso13616.Normalize(iban.Text),
+		"ibanPrint":   iso13616.PrintFormat(iban.Text),
 		"description": iban.Description,
 	})
 }
 
-// RenderIbanPage renders a simple HTML page displaying the IBAN or returns JSON based on Accept header
+// RenderIbanPage renders a simple HTML page displaying the IBAN or returns JSON based on Accept header.
+// It serves both /:userHandle/:ibanHandle and the IBANs of groups at /g/:groupHandle/:ibanHandle.
+// With ?format=qrbill CH and LI IBANs are served as a Swiss QR-bill PDF instead.
+// The JSON carries the payto URI, with the optional amount, currency and remittance query parameters.
 func RenderIbanPage(c *gin.Context) {
-	userHandle := c.Param("userHandle")
-	ibanHandle := c.Param("ibanHandle")
-
-	// Find user by handle
-	var user model.User
-	if err := config.DB.Where("handle = ?", userHandle).First(&user).Error; err != nil {
+	// Find IBAN by handle and owner
+	owner, iban, err := findOwnerIban(c)
+	if err != nil {
 		// Check if client wants JSON
 		if c.GetHeader("Accept") == "application/json" || c.Query("format") == "json" {
 			c.JSON(http.StatusNotFound, gin.H{
-				"error": "User not found",
+				"error": err.Error(),
 			})
 		} else {
 			c.HTML(http.StatusNotFound, "error.tmpl.html", gin.H{
-				"error": "User not found",
+				"error": err.Error(),
 			})
 		}
 		return
 	}
 
-	// Find IBAN by handle and owner
-	var iban model.Iban
-	if err := config.DB.Where("owner_id = ? AND handle = ? AND is_private = false", user.UserID, ibanHandle).First(&iban).Error; err != nil {
-		// Check if client wants JSON
-		if c.GetHeader("Accept") == "application/json" || c.Query("format") == "json" {
-			c.JSON(http.StatusNotFound, gin.H{
-				"error": "IBAN not found or is private",
-			})
-		} else {
-			c.HTML(http.StatusNotFound, "error.tmpl.html", gin.H{
-				"error": "IBAN not found or is private",
-			})
-		}
+	if !iban.IsIBAN() {
+		renderMethodPage(c, owner, iban)
 		return
 	}
 
+	if c.Query("format") == formatQRBill {
+		renderQRBill(c, owner, iban)
+		return
+	}
+
+	uri, err := ibanPayto(c, owner, iban)
+
 	// Check if client wants JSON response
 	if c.GetHeader("Accept") == "application/json" || c.Query("format") == "json" {
-		c.JSON(http.StatusOK, gin.H{
-			"userHandle":  userHandle,
-			"ibanHandle":  ibanHandle,
-			"iban":        iban.Text,
-			"description": iban.Description,
-			"firstName":   user.FirstName,
-			"lastName":    user.LastName,
-		})
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{
+				"error": err.Error(),
+			})
+			return
+		}
+		// Components are left empty for IBANs stored before validation existed
+		parts, _ := iso13616.Decompose(iban.Text)
+		bank, _ := bankdir.LookupIBAN(iban.Text)
+		c.JSON(http.StatusOK, owner.fields(gin.H{
+			"ibanHandle":          iban.Handle,
+			"iban":                iso13616.Normalize(iban.Text),
+			"ibanPrint":           iso13616.PrintFormat(iban.Text),
+			"description":         iban.Description,
+			"countryCode":         parts.CountryCode,
+			"bban":                parts.BBAN,
+			"bankCode":            parts.BankCode,
+			"branchCode":          parts.BranchCode,
+			"accountNumber":       parts.AccountNumber,
+			"nationalCheckDigits": parts.NationalCheckDigits,
+			"bankName":            bank.Name,
+			"bic":                 ibanBIC(iban),
+			"holderName":          iban.Holder(owner),
+			"accountType":         iban.AccountType,
+			"currency":            iban.Currency,
+			"presetAmounts":       PresetAmounts(iban),
+			"payto":               uri,
+		}))
 		return
 	}
 
 	// Render the IBAN page
-	c.HTML(http.StatusOK, "iban.tmpl.html", gin.H{
-		"userHandle":  userHandle,
-		"ibanHandle":  ibanHandle,
-		"iban":        iban.Text,
+	page := ibanPage(owner, iban)
+	// payto is not among the URL schemes html/template trusts
+	page["payto"] = template.URL(uri)
+	page["presets"] = ibanPresets(owner, iban, page["qrFormat"].(string))
+	c.HTML(http.StatusOK, "iban.tmpl.html", page)
+}
+
+// PresetAmounts formats the suggested amounts of an IBAN, e.g. "5.00"
+func PresetAmounts(iban model.Iban) []string {
+	amounts := []string{}
+	for _, cents := range iban.PresetAmounts() {
+		amounts = append(amounts, FormatAmount(cents))
+	}
+	return amounts
+}
+
+// ibanPresets are the buttons of the suggested amounts, each with the query
+// of the QR code and the payto URI for that amount
+func ibanPresets(owner ibanOwner, iban model.Iban, format string) []gin.H {
+	presets := []gin.H{}
+	for _, cents := range iban.PresetAmounts() {
+		query := url.Values{}
+		setQRAmount(query, format, cents, iban.Currency)
+		uri := payto.URI{
+			IBAN:         iban.Text,
+			BIC:          ibanBIC(iban),
+			ReceiverName: iban.Holder(owner),
+			Currency:     iban.Currency,
+			Amount:       cents,
+		}
+		presets = append(presets, gin.H{
+			"amount": FormatAmount(cents),
+			// Already encoded, html/template would escape it once more
+			"qrQuery": template.URL(query.Encode()),
+			"payto":   template.URL(uri.String()),
+		})
+	}
+	return presets
+}
+
+// ibanPage is what iban.tmpl.html shows of a public IBAN
+func ibanPage(owner ibanOwner, iban model.Iban) gin.H {
+	bank, _ := bankdir.LookupIBAN(iban.Text)
+	return owner.fields(gin.H{
+		"qrFormat":    defaultQRFormat(owner, iban.Text),
+		"ibanHandle":  iban.Handle,
+		"iban":        iso13616.Normalize(iban.Text),
+		"ibanPrint":   iso13616.PrintFormat(iban.Text),
 		"description": iban.Description,
-		"firstName":   user.FirstName,
-		"lastName":    user.LastName,
+		"bankName":    bank.Name,
+		"bic":         ibanBIC(iban),
+		"holderName":  iban.Holder(owner),
+		"accountType": iban.AccountType,
+		"currency":    iban.Currency,
 	})
 }
 
@@ -1,8 +1,10 @@
 package handler
 
 import (
+	"encoding/json"
 	"net/http"
 	"net/http/httptest"
+	"strings"
 	"testing"
 
 	"github.com/gin-gonic/gin"
@@ -20,7 +22,7 @@ func setupTestDB(t *testing.T) *gorm.DB {
 	}
 
 	// Auto-migrate all models
-	if err := db.AutoMigrate(&model.User{}, &model.Iban{}, &model.Group{}); err != nil {
+	if err := db.AutoMigrate(&model.User{}, &model.Iban{}, &model.Group{}, &model.RetiredGroupHandle{}, &model.GroupMember{}, &model.PaymentRequest{}); err != nil {
 		t.Fatalf("Failed to auto-migrate: %v", err)
 	}
 
@@ -256,6 +258,77 @@ func TestRenderIbanPage(t *testing.T) {
 	}
 }
 
+func TestRenderIbanPageFormats(t *testing.T) {
+	db := setupTestDB(t)
+	originalDB := config.DB
+	config.DB = db
+	defer func() {
+		config.DB = originalDB
+		sqlDB, _ := db.DB()
+		if sqlDB != nil {
+			sqlDB.Close()
+		}
+	}()
+
+	user := createTestUser(t, db, "test@example.com", "password123", "testuser", "Test", "User")
+	createTestIban(t, db, user.UserID, "tr32 0010 0099 9990 1234 5678 90", "spaced", "", false)
+
+	gin.SetMode(gin.TestMode)
+	w := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(w)
+	c.Params = gin.Params{
+		{Key: "userHandle", Value: "testuser"},
+		{Key: "ibanHandle", Value: "spaced"},
+	}
+	c.Request, _ = http.NewRequest("GET", "/?format=json", nil)
+
+	RenderIbanPage(c)
+
+	var body map[string]interface{}
+	if err := json.Unmarshal(w.Body.Bytes(), &body); err != nil {
+		t.Fatalf("Failed to decode response: %v", err)
+	}
+	if body["iban"] != "TR320010009999901234567890" {
+		t.Errorf("iban = %v, want TR320010009999901234567890", body["iban"])
+	}
+	if body["ibanPrint"] != "TR32 0010 0099 9990 1234 5678 90" {
+		t.Errorf("ibanPrint = %v, want TR32 0010 0099 9990 1234 5678 90", body["ibanPrint"])
+	}
+	if body["bankCode"] != "00100" {
+		t.Errorf("bankCode = %v, want 00100", body["bankCode"])
+	}
+	if body["accountNumber"] != "9999901234567890" {
+		t.Errorf("accountNumber = %v, want 9999901234567890", body["accountNumber"])
+	}
+	if body["bankName"] != "Adabank" {
+		t.Errorf("bankName = %v, want Adabank", body["bankName"])
+	}
+	if body["bic"] != "ADABTRISXXX" {
+		t.Errorf("bic = %v, want ADABTRISXXX", body["bic"])
+	}
+
+	// The owner's BIC is shown instead of the directory's
+	withBIC := createTestIban(t, db, user.UserID, "DE89370400440532013000", "withbic", "", false)
+	db.Model(withBIC).Update("bic", "COBADEFF370")
+	w = httptest.NewRecorder()
+	c, _ = gin.CreateTestContext(w)
+	c.Params = gin.Params{
+		{Key: "userHandle", Value: "testuser"},
+		{Key: "ibanHandle", Value: "withbic"},
+	}
+	c.Request, _ = http.NewRequest("GET", "/?format=json", nil)
+
+	RenderIbanPage(c)
+
+	body = map[string]interface{}{}
+	if err := json.Unmarshal(w.Body.Bytes(), &body); err != nil {
+		t.Fatalf("Failed to decode response: %v", err)
+	}
+	if body["bic"] != "COBADEFF370" {
+		t.Errorf("bic = %v, want COBADEFF370", body["bic"])
+	}
+}
+
 func TestIsValidRoute(t *testing.T) {
 	tests := []struct {
 		name     string
@@ -308,3 +381,221 @@ func TestIsValidRoute(t *testing.T) {
 		})
 	}
 }
+
+func TestRenderIbanPagePayto(t *testing.T) {
+	db := setupTestDB(t)
+	originalDB := config.DB
+	config.DB = db
+	defer func() {
+		config.DB = originalDB
+		sqlDB, _ := db.DB()
+		if sqlDB != nil {
+			sqlDB.Close()
+		}
+	}()
+
+	user := createTestUser(t, db, "test@example.com", "password123", "testuser", "Test", "User")
+	createTestIban(t, db, user.UserID, "DE89370400440532013000", "euro", "", false)
+
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	router.LoadHTMLGlob("../templates/*.tmpl.html")
+	router.GET("/:userHandle/:ibanHandle", RenderIbanPage)
+
+	tests := []struct {
+		name   string
+		query  string
+		status int
+		want   string
+	}{
+		{name: "Receiver only", query: "format=json", status: http.StatusOK, want: "payto://iban/COBADEFFXXX/DE89370400440532013000?receiver-name=Test%20User"},
+		{name: "Amount and message", query: "format=json&amount=12.50&currency=eur&remittance=Dinner", status: http.StatusOK, want: "payto://iban/COBADEFFXXX/DE89370400440532013000?amount=EUR:12.50&receiver-name=Test%20User&message=Dinner"},
+		{name: "Creditor reference", query: "format=json&reference=RF18539007547034", status: http.StatusOK, want: "payto://iban/COBADEFFXXX/DE89370400440532013000?receiver-name=Test%20User&message=RF18539007547034"},
+		{name: "Invalid creditor reference", query: "format=json&reference=RF19539007547034", status: http.StatusBadRequest},
+		{name: "Amount without currency", query: "format=json&amount=12.50", status: http.StatusBadRequest},
+		{name: "Invalid amount", query: "format=json&amount=abc&currency=EUR", status: http.StatusBadRequest},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := httptest.NewRecorder()
+			req, _ := http.NewRequest("GET", "/testuser/euro?"+tt.query, nil)
+			router.ServeHTTP(w, req)
+
+			if w.Code != tt.status {
+				t.Fatalf("Expected status %d, got %d: %s", tt.status, w.Code, w.Body.String())
+			}
+			if tt.want == "" {
+				return
+			}
+			var body map[string]interface{}
+			if err := json.Unmarshal(w.Body.Bytes(), &body); err != nil {
+				t.Fatalf("Failed to decode response: %v", err)
+			}
+			if body["payto"] != tt.want {
+				t.Errorf("payto = %v, want %s", body["payto"], tt.want)
+			}
+		})
+	}
+
+	// The page links the URI for banking apps
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest("GET", "/testuser/euro", nil)
+	router.ServeHTTP(w, req)
+	if !strings.Contains(w.Body.String(), `href="payto://iban/COBADEFFXXX/DE89370400440532013000?receiver-name=Test%20User"`) {
+		t.Errorf("Expected a payto link on the page: %s", w.Body.String())
+	}
+}
+
+func TestRenderIbanPagePresets(t *testing.T) {
+	db := setupTestDB(t)
+	originalDB := config.DB
+	config.DB = db
+	defer func() {
+		config.DB = originalDB
+		sqlDB, _ := db.DB()
+		if sqlDB != nil {
+			sqlDB.Close()
+		}
+	}()
+
+	user := createTestUser(t, db, "test@example.com", "password123", "testuser", "Test", "User")
+	for _, iban := range []model.Iban{
+		{Text: "DE89370400440532013000", Handle: "tips", Currency: "EUR", Presets: "1000,500", OwnerID: user.UserID},
+		{Text: "DE89370400440532013000", Handle: "francs", Currency: "CHF", Presets: "500", OwnerID: user.UserID},
+		{Text: "DE89370400440532013000", Handle: "plain", OwnerID: user.UserID},
+	} {
+		if err := db.Create(&iban).Error; err != nil {
+			t.Fatalf("Failed to create test IBAN: %v", err)
+		}
+	}
+
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	router.LoadHTMLGlob("../templates/*.tmpl.html")
+	router.GET("/:userHandle/:ibanHandle", RenderIbanPage)
+
+	tests := []struct {
+		name    string
+		path    string
+		want    []string
+		notWant []string
+	}{
+		{
+			name: "Euro presets",
+			path: "/testuser/tips",
+			want: []string{
+				`data-amount="5.00" data-qr-query="amount=5.00" data-payto="payto://iban/COBADEFFXXX/DE89370400440532013000?amount=EUR:5.00&amp;receiver-name=Test%20User"`,
+				`>10.00 EUR</button>`,
+				`id="copyAmount"`,
+			},
+		},
+		{
+			name:    "Currency the QR code cannot carry",
+			path:    "/testuser/francs",
+			want:    []string{`data-amount="5.00" data-qr-query="" data-payto="payto://iban/COBADEFFXXX/DE89370400440532013000?amount=CHF:5.00`},
+			notWant: []string{`data-qr-query="amount=`},
+		},
+		{
+			name:    "No presets",
+			path:    "/testuser/plain",
+			notWant: []string{"choosePreset(this)", `id="copyAmount"`},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := httptest.NewRecorder()
+			req, _ := http.NewRequest("GET", tt.path, nil)
+			router.ServeHTTP(w, req)
+
+			if w.Code != http.StatusOK {
+				t.Fatalf("Expected status 200, got %d: %s", w.Code, w.Body.String())
+			}
+			for _, want := range tt.want {
+				if !strings.Contains(w.Body.String(), want) {
+					t.Errorf("Page lacks %s", want)
+				}
+			}
+			for _, notWant := range tt.notWant {
+				if strings.Contains(w.Body.String(), notWant) {
+					t.Errorf("Page has %s", notWant)
+				}
+			}
+		})
+	}
+
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest("GET", "/testuser/tips?format=json", nil)
+	router.ServeHTTP(w, req)
+	var body struct {
+		Currency      string   `json:"currency"`
+		PresetAmounts []string `json:"presetAmounts"`
+	}
+	if err := json.Unmarshal(w.Body.Bytes(), &body); err != nil {
+		t.Fatalf("Failed to decode response: %v", err)
+	}
+	if body.Currency != "EUR" || strings.Join(body.PresetAmounts, ",") != "5.00,10.00" {
+		t.Errorf("JSON = %+v, want EUR 5.00,10.00", body)
+	}
+}
+
+func TestRenderIbanPageAccount(t *testing.T) {
+	db := setupTestDB(t)
+	originalDB := config.DB
+	config.DB = db
+	defer func() {
+		config.DB = originalDB
+		sqlDB, _ := db.DB()
+		if sqlDB != nil {
+			sqlDB.Close()
+		}
+	}()
+
+	user := createTestUser(t, db, "test@example.com", "password123", "testuser", "Test", "User")
+	iban := model.Iban{Text: "DE89370400440532013000", Handle: "company", HolderName: "Acme GmbH", Currency: "USD", AccountType: model.AccountBusiness, OwnerID: user.UserID}
+	if err := db.Create(&iban).Error; err != nil {
+		t.Fatalf("Failed to create test IBAN: %v", err)
+	}
+
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	router.LoadHTMLGlob("../templates/*.tmpl.html")
+	router.GET("/:userHandle/:ibanHandle", RenderIbanPage)
+
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest("GET", "/testuser/company", nil)
+	router.ServeHTTP(w, req)
+	for _, want := range []string{"Account holder", "Acme GmbH", `<span class="font-mono">USD</span> · business account`, "receiver-name=Acme%20GmbH"} {
+		if !strings.Contains(w.Body.String(), want) {
+			t.Errorf("Page lacks %s", want)
+		}
+	}
+
+	// The amount is in the account's currency unless another is given
+	w = httptest.NewRecorder()
+	req, _ = http.NewRequest("GET", "/testuser/company?format=json&amount=12.50", nil)
+	router.ServeHTTP(w, req)
+	var body struct {
+		HolderName  string `json:"holderName"`
+		AccountType string `json:"accountType"`
+		Currency    string `json:"currency"`
+		Payto       string `json:"payto"`
+	}
+	if err := json.Unmarshal(w.Body.Bytes(), &body); err != nil {
+		t.Fatalf("Failed to decode response: %v", err)
+	}
+	want := "payto://iban/COBADEFFXXX/DE89370400440532013000?amount=USD:12.50&receiver-name=Acme%20GmbH"
+	if body.HolderName != "Acme GmbH" || body.AccountType != "business" || body.Currency != "USD" || body.Payto != want {
+		t.Errorf("JSON = %+v, want Acme GmbH business USD %s", body, want)
+	}
+
+	// Without a holder name the page shows the owner as holder, like the JSON
+	createTestIban(t, db, user.UserID, "DE02120300000000202051", "personal", "", false)
+	w = httptest.NewRecorder()
+	req, _ = http.NewRequest("GET", "/testuser/personal", nil)
+	router.ServeHTTP(w, req)
+	if !strings.Contains(w.Body.String(), `<p class="text-lg">Test User</p>`) {
+		t.Errorf("Page lacks the owner as account holder")
+	}
+}
@@ -0,0 +1,91 @@
+package handler
+
+import (
+	"errors"
+
+	"github.com/gin-gonic/gin"
+	"github.com/tapsilat/iban.im/config"
+	"github.com/tapsilat/iban.im/model"
+)
+
+var errGroupNotFound = errors.New("Group not found")
+
+// ibanOwner is the user or group that publishes an IBAN. Groups have no
+// postal address, so their CH and LI IBANs get EPC codes instead of QR-bills.
+type ibanOwner struct {
+	model.User
+	Group *model.Group
+}
+
+// DisplayName is the name payers see as holder when the IBAN has none
+func (owner ibanOwner) DisplayName() string {
+	if owner.Group != nil {
+		return owner.Group.DisplayName()
+	}
+	return owner.User.DisplayName()
+}
+
+// path is where the public pages of the owner's IBANs start, /fakturk for
+// users and /g/acme for groups
+func (owner ibanOwner) path() string {
+	if owner.Group != nil {
+		return "/g/" + owner.Group.Handle
+	}
+	return "/" + owner.Handle
+}
+
+// fields adds the owner to the data of a page or JSON response
+func (owner ibanOwner) fields(h gin.H) gin.H {
+	h["ownerPath"] = owner.path()
+	if owner.Group != nil {
+		h["groupHandle"] = owner.Group.Handle
+		h["groupName"] = owner.Group.GroupName
+		h["groupVerified"] = owner.Group.Verified
+		if owner.Group.Verified {
+			h["groupDomain"] = owner.Group.Domain()
+		}
+		return h
+	}
+	h["userHandle"] = owner.Handle
+	h["firstName"] = owner.FirstName
+	h["lastName"] = owner.LastName
+	return h
+}
+
+// findPublicIban loads a user and one of their public IBANs by handle
+func findPublicIban(userHandle, ibanHandle string) (ibanOwner, model.Iban, error) {
+	var owner ibanOwner
+	if err := config.DB.Where("handle = ?", userHandle).First(&owner.User).Error; err != nil {
+		return owner, model.Iban{}, errUserNotFound
+	}
+	iban, err := findOwnedIban(owner.UserID, model.OwnerUser, ibanHandle)
+	return owner, iban, err
+}
+
+// findGroupIban loads a group and one of its public IBANs by handle
+func findGroupIban(groupHandle, ibanHandle string) (ibanOwner, model.Iban, error) {
+	var group model.Group
+	if err := config.DB.Where("handle = ?", groupHandle).First(&group).Error; err != nil {
+		return ibanOwner{}, model.Iban{}, errGroupNotFound
+	}
+	owner := ibanOwner{Group: &group}
+	iban, err := findOwnedIban(group.GroupID, model.OwnerGroup, ibanHandle)
+	return owner, iban, err
+}
+
+// findOwnerIban loads the IBAN of a /:userHandle/:ibanHandle or
+// /g/:groupHandle/:ibanHandle route
+func findOwnerIban(c *gin.Context) (ibanOwner, model.Iban, error) {
+	if groupHandle := c.Param("groupHandle"); groupHandle != "" {
+		return findGroupIban(groupHandle, c.Param("ibanHandle"))
+	}
+	return findPublicIban(c.Param("userHandle"), c.Param("ibanHandle"))
+}
+
+func findOwnedIban(ownerID uint, ownerType, ibanHandle string) (model.Iban, error) {
+	var iban model.Iban
+	if err := config.DB.Where("owner_id = ? AND owner_type = ? AND handle = ? AND is_private = false", ownerID, ownerType, ibanHandle).First(&iban).Error; err != nil {
+		return iban, errIbanNotFound
+	}
+	return iban, nil
+}
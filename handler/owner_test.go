@@ -0,0 +1,121 @@
+package handler
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/tapsilat/iban.im/config"
+	"github.com/tapsilat/iban.im/model"
+)
+
+func TestRenderGroupIban(t *testing.T) {
+	db := setupTestDB(t)
+	originalDB := config.DB
+	config.DB = db
+	defer func() {
+		config.DB = originalDB
+		sqlDB, _ := db.DB()
+		if sqlDB != nil {
+			sqlDB.Close()
+		}
+	}()
+
+	user := createTestUser(t, db, "test@example.com", "password123", "testuser", "Test", "User")
+	group := model.Group{Handle: "acme", GroupName: "Acme Ltd", GroupURL: "https://acme.example", Verified: true, OwnerID: user.UserID}
+	unverified := model.Group{Handle: "copycat", GroupName: "Acme Ltd", GroupURL: "https://acme.example", OwnerID: user.UserID}
+	for _, g := range []*model.Group{&group, &unverified} {
+		if err := db.Create(g).Error; err != nil {
+			t.Fatalf("Failed to create test group: %v", err)
+		}
+	}
+	// The group and the user share the handle of their IBANs
+	for _, iban := range []model.Iban{
+		{Text: "DE89370400440532013000", Handle: "donations", OwnerID: group.GroupID, OwnerType: model.OwnerGroup},
+		{Text: "DE02120300000000202051", Handle: "payroll", OwnerID: group.GroupID, OwnerType: model.OwnerGroup, IsPrivate: true, Password: "secret"},
+		{Text: "GB82WEST12345698765432", Handle: "donations", OwnerID: user.UserID},
+		{Text: "DE89370400440532013000", Handle: "donations", OwnerID: unverified.GroupID, OwnerType: model.OwnerGroup},
+	} {
+		if err := db.Create(&iban).Error; err != nil {
+			t.Fatalf("Failed to create test IBAN: %v", err)
+		}
+	}
+
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	router.LoadHTMLGlob("../templates/*.tmpl.html")
+	router.GET("/:userHandle/:ibanHandle", RenderIbanPage)
+	router.GET("/:userHandle/:ibanHandle/qr.svg", RenderIbanQR("svg"))
+	router.GET("/g/:groupHandle/:ibanHandle", RenderIbanPage)
+	router.GET("/g/:groupHandle/:ibanHandle/qr.svg", RenderIbanQR("svg"))
+
+	tests := []struct {
+		name    string
+		path    string
+		status  int
+		want    []string
+		notWant []string
+	}{
+		{
+			name:    "Group IBAN",
+			path:    "/g/acme/donations",
+			status:  http.StatusOK,
+			want:    []string{"Acme Ltd", "DE89 3704 0044 0532 0130 00", "/g/acme/donations/qr.svg", "Verified acme.example"},
+			notWant: []string{"GB82"},
+		},
+		{
+			name:    "Unverified group",
+			path:    "/g/copycat/donations",
+			status:  http.StatusOK,
+			want:    []string{"Acme Ltd"},
+			notWant: []string{"Verified"},
+		},
+		{
+			name:    "User IBAN with the same handle",
+			path:    "/testuser/donations",
+			status:  http.StatusOK,
+			want:    []string{"GB82 WEST 1234 5698 7654 32"},
+			notWant: []string{"Acme Ltd"},
+		},
+		{name: "QR code", path: "/g/acme/donations/qr.svg", status: http.StatusOK, want: []string{"<svg"}},
+		{name: "Private group IBAN", path: "/g/acme/payroll", status: http.StatusNotFound},
+		{name: "Unknown group", path: "/g/nobody/donations", status: http.StatusNotFound},
+		{name: "Group IBAN is not the user's", path: "/testuser/payroll", status: http.StatusNotFound},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := httptest.NewRecorder()
+			req, _ := http.NewRequest("GET", tt.path, nil)
+			router.ServeHTTP(w, req)
+
+			if w.Code != tt.status {
+				t.Fatalf("Expected status %d, got %d: %s", tt.status, w.Code, w.Body.String())
+			}
+			for _, want := range tt.want {
+				if !strings.Contains(w.Body.String(), want) {
+					t.Errorf("Page lacks %s", want)
+				}
+			}
+			for _, notWant := range tt.notWant {
+				if strings.Contains(w.Body.String(), notWant) {
+					t.Errorf("Page has %s", notWant)
+				}
+			}
+		})
+	}
+
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest("GET", "/g/acme/donations?format=json", nil)
+	router.ServeHTTP(w, req)
+	var body map[string]any
+	if err := json.Unmarshal(w.Body.Bytes(), &body); err != nil {
+		t.Fatalf("Failed to decode response: %v", err)
+	}
+	if body["groupHandle"] != "acme" || body["groupName"] != "Acme Ltd" || body["groupVerified"] != true || body["userHandle"] != nil {
+		t.Errorf("JSON = %v, want the group as owner", body)
+	}
+}
@@ -0,0 +1,150 @@
+package handler
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/tapsilat/iban.im/pain001"
+)
+
+var errExecutionDate = errors.New("execution date must be a date (2006-01-02) that is not in the past")
+
+// Pain001Batch is the batch posted to ExportPain001: the account paying and
+// the public IBANs to pay, by handle
+type Pain001Batch struct {
+	// MessageID is generated when left empty
+	MessageID string `json:"messageId"`
+	// ExecutionDate defaults to today
+	ExecutionDate string            `json:"executionDate"`
+	Debtor        Pain001Debtor     `json:"debtor"`
+	Transfers     []Pain001Transfer `json:"transfers"`
+}
+
+// Pain001Debtor is the account the batch is paid from
+type Pain001Debtor struct {
+	Name string `json:"name"`
+	IBAN string `json:"iban"`
+	BIC  string `json:"bic"`
+}
+
+// Pain001Transfer is a payment to the public IBAN at
+// /:userHandle/:ibanHandle
+type Pain001Transfer struct {
+	UserHandle string `json:"userHandle"`
+	IbanHandle string `json:"ibanHandle"`
+	Amount     string `json:"amount"`
+	// Reference is an ISO 11649 creditor reference, Remittance a free text;
+	// a transfer carries one of them
+	Reference  string `json:"reference"`
+	Remittance string `json:"remittance"`
+	EndToEndID string `json:"endToEndId"`
+}
+
+// TransferError tells why a transfer of a batch was rejected
+type TransferError struct {
+	Transfer int    `json:"transfer"`
+	Payee    string `json:"payee"`
+	Error    string `json:"error"`
+}
+
+// ExportPain001 turns a batch of payments to public IBANs into a SEPA
+// credit transfer file (pain.001.001.09) to upload to the debtor's bank.
+// Every IBAN and BIC is validated and accounts outside SEPA are rejected;
+// the errors of all transfers are reported at once.
+func ExportPain001(c *gin.Context) {
+	var request Pain001Batch
+	if err := c.ShouldBindJSON(&request); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": "expected a JSON batch with debtor and transfers: " + err.Error(),
+		})
+		return
+	}
+
+	now := time.Now()
+	batch := pain001.Batch{
+		MessageID: request.MessageID,
+		Created:   now,
+		Debtor: pain001.Party{
+			Name: request.Debtor.Name,
+			IBAN: request.Debtor.IBAN,
+			BIC:  request.Debtor.BIC,
+		},
+		ExecutionDate: now,
+	}
+	if batch.MessageID == "" {
+		batch.MessageID = "IBANIM-" + now.UTC().Format("20060102150405.000000")
+	}
+	if request.ExecutionDate != "" {
+		date, err := time.ParseInLocation(time.DateOnly, request.ExecutionDate, now.Location())
+		if err != nil || date.Format(time.DateOnly) < now.Format(time.DateOnly) {
+			c.JSON(http.StatusBadRequest, gin.H{
+				"error": errExecutionDate.Error(),
+			})
+			return
+		}
+		batch.ExecutionDate = date
+	}
+
+	var rejected []TransferError
+	for i, transfer := range request.Transfers {
+		t, err := batchTransfer(transfer)
+		if err == nil {
+			err = t.Validate()
+		}
+		if err != nil {
+			rejected = append(rejected, TransferError{
+				Transfer: i + 1,
+				Payee:    transfer.UserHandle + "/" + transfer.IbanHandle,
+				Error:    err.Error(),
+			})
+		}
+		batch.Transfers = append(batch.Transfers, t)
+	}
+	if len(rejected) > 0 {
+		c.JSON(http.StatusUnprocessableEntity, gin.H{
+			"error":     fmt.Sprintf("%d of %d transfers were rejected", len(rejected), len(request.Transfers)),
+			"transfers": rejected,
+		})
+		return
+	}
+
+	out, err := batch.XML()
+	if err != nil {
+		c.JSON(http.StatusUnprocessableEntity, gin.H{
+			"error": err.Error(),
+		})
+		return
+	}
+	c.Header("Content-Disposition", fmt.Sprintf("attachment; filename=%q", batch.MessageID+".xml"))
+	c.Data(http.StatusOK, "application/xml; charset=utf-8", out)
+}
+
+// batchTransfer looks up the payee of a transfer
+func batchTransfer(transfer Pain001Transfer) (pain001.Transfer, error) {
+	owner, iban, err := findPublicIban(transfer.UserHandle, transfer.IbanHandle)
+	if err != nil {
+		return pain001.Transfer{}, err
+	}
+	if !iban.IsIBAN() {
+		return pain001.Transfer{}, errNotIBAN
+	}
+	amount, err := ParseAmount(transfer.Amount)
+	if err != nil || amount == 0 {
+		return pain001.Transfer{}, errAmount
+	}
+	return pain001.Transfer{
+		EndToEndID: transfer.EndToEndID,
+		Creditor: pain001.Party{
+			Name: iban.Holder(owner),
+			IBAN: iban.Text,
+			BIC:  ibanBIC(iban),
+		},
+		Amount:    amount,
+		Currency:  iban.Currency,
+		Reference: transfer.Reference,
+		Text:      transfer.Remittance,
+	}, nil
+}
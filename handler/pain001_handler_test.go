@@ -0,0 +1,137 @@
+package handler
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/tapsilat/iban.im/config"
+)
+
+func TestExportPain001(t *testing.T) {
+	db := setupTestDB(t)
+	originalDB := config.DB
+	config.DB = db
+	defer func() {
+		config.DB = originalDB
+		sqlDB, _ := db.DB()
+		if sqlDB != nil {
+			sqlDB.Close()
+		}
+	}()
+
+	user := createTestUser(t, db, "test@example.com", "password123", "testuser", "Test", "User")
+	createTestIban(t, db, user.UserID, "DE89370400440532013000", "euro", "", false)
+	createTestIban(t, db, user.UserID, "AT611904300234573201", "austria", "", false)
+	createTestIban(t, db, user.UserID, "TR330006100519786457841326", "lira", "", false)
+	createTestIban(t, db, user.UserID, "DE89370400440532013000", "secret", "pass", true)
+	dollars := createTestIban(t, db, user.UserID, "DE02120300000000202051", "dollars", "", false)
+	db.Model(dollars).Update("currency", "USD")
+
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	router.POST("/api/v1/pain001", ExportPain001)
+
+	debtor := `"debtor": {"name": "Acme GmbH", "iban": "FR1420041010050500013M02606"}`
+	tests := []struct {
+		name     string
+		body     string
+		status   int
+		contains []string
+		rejected []TransferError
+	}{
+		{
+			name: "Batch of two",
+			body: `{"messageId": "BATCH-1", "executionDate": "2099-01-04", ` + debtor + `, "transfers": [
+				{"userHandle": "testuser", "ibanHandle": "euro", "amount": "12.50", "reference": "RF18539007547034", "endToEndId": "INV-1"},
+				{"userHandle": "testuser", "ibanHandle": "austria", "amount": "40", "remittance": "Rent"}]}`,
+			status: http.StatusOK,
+			contains: []string{
+				"<MsgId>BATCH-1</MsgId>", "<CtrlSum>52.50</CtrlSum>", "<Dt>2099-01-04</Dt>",
+				"<Nm>Test User</Nm>", "<BICFI>COBADEFFXXX</BICFI>", "<IBAN>AT611904300234573201</IBAN>",
+				"<Ref>RF18539007547034</Ref>", "<Ustrd>Rent</Ustrd>", "<Id>NOTPROVIDED</Id>",
+			},
+		},
+		{
+			name: "Rejected transfers",
+			body: `{` + debtor + `, "transfers": [
+				{"userHandle": "testuser", "ibanHandle": "euro", "amount": "12.50"},
+				{"userHandle": "testuser", "ibanHandle": "lira", "amount": "12.50"},
+				{"userHandle": "testuser", "ibanHandle": "secret", "amount": "12.50"},
+				{"userHandle": "testuser", "ibanHandle": "euro", "amount": "0"},
+				{"userHandle": "testuser", "ibanHandle": "euro", "amount": "1", "reference": "RF19539007547034"},
+				{"userHandle": "testuser", "ibanHandle": "dollars", "amount": "1"}]}`,
+			status: http.StatusUnprocessableEntity,
+			rejected: []TransferError{
+				{Transfer: 2, Payee: "testuser/lira", Error: "SEPA credit transfers are only available for SEPA IBANs: TR33 0006 1005 1978 6457 8413 26"},
+				{Transfer: 3, Payee: "testuser/secret", Error: "IBAN not found or is private"},
+				{Transfer: 4, Payee: "testuser/euro", Error: "amount must be a positive number with at most two decimals"},
+				{Transfer: 5, Payee: "testuser/euro", Error: "creditor reference checksum does not match"},
+				{Transfer: 6, Payee: "testuser/dollars", Error: "SEPA credit transfers are only available in EUR, the account is in USD"},
+			},
+		},
+		{
+			name:   "Debtor outside SEPA",
+			body:   `{"debtor": {"name": "Acme", "iban": "TR330006100519786457841326"}, "transfers": [{"userHandle": "testuser", "ibanHandle": "euro", "amount": "1"}]}`,
+			status: http.StatusUnprocessableEntity,
+		},
+		{
+			name:   "Debtor BIC of another country",
+			body:   `{"debtor": {"name": "Acme", "iban": "FR1420041010050500013M02606", "bic": "COBADEFF"}, "transfers": [{"userHandle": "testuser", "ibanHandle": "euro", "amount": "1"}]}`,
+			status: http.StatusUnprocessableEntity,
+		},
+		{
+			name:   "No transfers",
+			body:   `{` + debtor + `, "transfers": []}`,
+			status: http.StatusUnprocessableEntity,
+		},
+		{
+			name:   "Execution date in the past",
+			body:   `{"executionDate": "2020-01-01", ` + debtor + `, "transfers": [{"userHandle": "testuser", "ibanHandle": "euro", "amount": "1"}]}`,
+			status: http.StatusBadRequest,
+		},
+		{
+			name:   "Not JSON",
+			body:   `debtor=Acme`,
+			status: http.StatusBadRequest,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := httptest.NewRecorder()
+			req, _ := http.NewRequest("POST", "/api/v1/pain001", strings.NewReader(tt.body))
+			req.Header.Set("Content-Type", "application/json")
+			router.ServeHTTP(w, req)
+
+			if w.Code != tt.status {
+				t.Fatalf("Expected status %d, got %d: %s", tt.status, w.Code, w.Body.String())
+			}
+			for _, want := range tt.contains {
+				if !strings.Contains(w.Body.String(), want) {
+					t.Errorf("Body lacks %s:\n%s", want, w.Body.String())
+				}
+			}
+			if tt.status == http.StatusOK && w.Header().Get("Content-Disposition") != `attachment; filename="BATCH-1.xml"` {
+				t.Errorf("Content-Disposition = %s", w.Header().Get("Content-Disposition"))
+			}
+			if tt.rejected != nil {
+				var body struct {
+					Transfers []TransferError `json:"transfers"`
+				}
+				json.Unmarshal(w.Body.Bytes(), &body)
+				if len(body.Transfers) != len(tt.rejected) {
+					t.Fatalf("Rejected %+v, want %+v", body.Transfers, tt.rejected)
+				}
+				for i := range tt.rejected {
+					if body.Transfers[i] != tt.rejected[i] {
+						t.Errorf("Rejected transfer = %+v, want %+v", body.Transfers[i], tt.rejected[i])
+					}
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,158 @@
+package handler
+
+import (
+	"errors"
+	"html/template"
+	"net/http"
+	"net/url"
+	"slices"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/tapsilat/iban.im/config"
+	"github.com/tapsilat/iban.im/iso11649"
+	"github.com/tapsilat/iban.im/iso13616"
+	"github.com/tapsilat/iban.im/model"
+	"github.com/tapsilat/iban.im/payto"
+)
+
+var errRequestNotFound = errors.New("Payment request not found")
+
+// qrCurrencies are the currencies each QR code format can carry
+var qrCurrencies = map[string][]string{
+	formatEPC:     {"EUR"},
+	formatKarekod: {"TRY"},
+	formatQRBill:  {"CHF", "EUR"},
+}
+
+// requestStatus is the status shown to payers, "expired" for open requests
+// past their expiry
+func requestStatus(request model.PaymentRequest, now time.Time) string {
+	if request.Status == model.PaymentRequestOpen && request.Expired(now) {
+		return "expired"
+	}
+	return request.Status
+}
+
+// requestPayto is the payto URI of a payment request; the reference takes
+// the place of the description as message
+func requestPayto(owner ibanOwner, iban model.Iban, request model.PaymentRequest) string {
+	uri := payto.URI{
+		IBAN:         iban.Text,
+		BIC:          ibanBIC(iban),
+		ReceiverName: iban.Holder(owner),
+		Currency:     request.Currency,
+		Amount:       request.Amount,
+		Message:      request.Description,
+	}
+	if request.Reference != "" {
+		uri.Message = request.Reference
+	}
+	return uri.String()
+}
+
+// requestQRQuery passes a payment request on to the QR code and QR-bill
+// routes. The amount is left out where the format cannot carry its currency
+// or the account is kept in another one, and Karekod, which has no reference
+// field, gets the reference as text.
+func requestQRQuery(format string, iban model.Iban, request model.PaymentRequest) string {
+	query := url.Values{}
+	if iban.Currency == "" || iban.Currency == request.Currency {
+		setQRAmount(query, format, request.Amount, request.Currency)
+	}
+	switch {
+	case request.Reference != "" && format != formatKarekod:
+		query.Set("reference", request.Reference)
+	case request.Reference != "":
+		query.Set("remittance", request.Reference)
+	case request.Description != "":
+		query.Set("remittance", request.Description)
+	}
+	return query.Encode()
+}
+
+// setQRAmount adds an amount to a query of the QR code routes if the format
+// can carry its currency
+func setQRAmount(query url.Values, format string, amount int64, currency string) {
+	if amount > 0 && slices.Contains(qrCurrencies[format], currency) {
+		query.Set("amount", FormatAmount(amount))
+		if format == formatQRBill {
+			query.Set("currency", currency)
+		}
+	}
+}
+
+// RenderPaymentRequest renders a payment request to a public IBAN at
+// /:userHandle/:ibanHandle/r/:requestId with the QR code, payto link and copy
+// buttons pre-filled with its amount and reference, or returns it as JSON
+func RenderPaymentRequest(c *gin.Context) {
+	wantsJSON := c.GetHeader("Accept") == "application/json" || c.Query("format") == "json"
+	fail := func(status int, err error) {
+		if wantsJSON {
+			c.JSON(status, gin.H{
+				"error": err.Error(),
+			})
+		} else {
+			c.HTML(status, "error.tmpl.html", gin.H{
+				"error": err.Error(),
+			})
+		}
+	}
+
+	owner, iban, err := findPublicIban(c.Param("userHandle"), c.Param("ibanHandle"))
+	if err != nil {
+		fail(http.StatusNotFound, err)
+		return
+	}
+	var request model.PaymentRequest
+	if err := config.DB.Where("public_id = ? AND iban_id = ?", c.Param("requestId"), iban.IbanID).First(&request).Error; err != nil {
+		fail(http.StatusNotFound, errRequestNotFound)
+		return
+	}
+
+	now := time.Now()
+	var amount, expiresAt string
+	if request.Amount > 0 {
+		amount = FormatAmount(request.Amount)
+	}
+	if request.ExpiresAt != nil {
+		expiresAt = request.ExpiresAt.UTC().Format(time.RFC3339)
+	}
+
+	if wantsJSON {
+		c.JSON(http.StatusOK, owner.fields(gin.H{
+			"ibanHandle":  iban.Handle,
+			"requestId":   request.PublicID,
+			"iban":        iso13616.Normalize(iban.Text),
+			"ibanPrint":   iso13616.PrintFormat(iban.Text),
+			"amount":      amount,
+			"currency":    request.Currency,
+			"reference":   request.Reference,
+			"description": request.Description,
+			"expiresAt":   expiresAt,
+			"status":      requestStatus(request, now),
+			"payto":       requestPayto(owner, iban, request),
+		}))
+		return
+	}
+
+	page := ibanPage(owner, iban)
+	page["request"] = gin.H{
+		"amount":         amount,
+		"currency":       request.Currency,
+		"reference":      request.Reference,
+		"referencePrint": iso11649.PrintFormat(request.Reference),
+		"description":    request.Description,
+		"expiresAt":      expiresAt,
+		"status":         requestStatus(request, now),
+	}
+	if request.Payable(now) {
+		// Already encoded, html/template would escape it once more
+		page["qrQuery"] = template.URL(requestQRQuery(page["qrFormat"].(string), iban, request))
+		page["payto"] = template.URL(requestPayto(owner, iban, request))
+	} else {
+		// Nothing to pay anymore
+		page["qrFormat"] = ""
+	}
+	c.HTML(http.StatusOK, "iban.tmpl.html", page)
+}
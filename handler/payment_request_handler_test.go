@@ -0,0 +1,143 @@
+package handler
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/tapsilat/iban.im/config"
+	"github.com/tapsilat/iban.im/model"
+)
+
+func TestRenderPaymentRequest(t *testing.T) {
+	db := setupTestDB(t)
+	originalDB := config.DB
+	config.DB = db
+	defer func() {
+		config.DB = originalDB
+		sqlDB, _ := db.DB()
+		if sqlDB != nil {
+			sqlDB.Close()
+		}
+	}()
+
+	user := createTestUser(t, db, "test@example.com", "password123", "testuser", "Test", "User")
+	euro := createTestIban(t, db, user.UserID, "DE89370400440532013000", "euro", "", false)
+	createTestIban(t, db, user.UserID, "DE89370400440532013000", "other", "", false)
+	yesterday := time.Now().AddDate(0, 0, -1)
+	requests := map[string]*model.PaymentRequest{
+		"open":    {OwnerID: user.UserID, IbanID: euro.IbanID, Amount: 1250, Currency: "EUR", Reference: "RF18539007547034", Description: "Dinner"},
+		"paid":    {OwnerID: user.UserID, IbanID: euro.IbanID, Amount: 500, Currency: "EUR", Status: model.PaymentRequestPaid},
+		"expired": {OwnerID: user.UserID, IbanID: euro.IbanID, Description: "Gift", ExpiresAt: &yesterday},
+	}
+	for _, request := range requests {
+		if err := db.Create(request).Error; err != nil {
+			t.Fatalf("Failed to create payment request: %v", err)
+		}
+	}
+
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	router.LoadHTMLGlob("../templates/*.tmpl.html")
+	router.GET("/:userHandle/:ibanHandle/r/:requestId", RenderPaymentRequest)
+
+	tests := []struct {
+		name     string
+		path     string
+		status   int
+		contains []string
+		excludes []string
+	}{
+		{
+			name:   "Open request",
+			path:   "/testuser/euro/r/" + requests["open"].PublicID,
+			status: http.StatusOK,
+			contains: []string{
+				"12.50 EUR",
+				"RF18 5390 0754 7034",
+				`/testuser/euro/qr.svg?amount=12.50&amp;reference=RF18539007547034`,
+				`href="payto://iban/COBADEFFXXX/DE89370400440532013000?amount=EUR:12.50&amp;receiver-name=Test%20User&amp;message=RF18539007547034"`,
+				"Copy reference",
+			},
+		},
+		{
+			name:     "Paid request",
+			path:     "/testuser/euro/r/" + requests["paid"].PublicID,
+			status:   http.StatusOK,
+			contains: []string{"This request is paid."},
+			excludes: []string{"qr.svg", "payto://"},
+		},
+		{
+			name:     "Expired request",
+			path:     "/testuser/euro/r/" + requests["expired"].PublicID,
+			status:   http.StatusOK,
+			contains: []string{"This request is expired."},
+			excludes: []string{"qr.svg"},
+		},
+		{name: "Request of another IBAN", path: "/testuser/other/r/" + requests["open"].PublicID, status: http.StatusNotFound},
+		{name: "Unknown request", path: "/testuser/euro/r/0000000000000000", status: http.StatusNotFound},
+		{name: "Unknown user", path: "/nobody/euro/r/" + requests["open"].PublicID, status: http.StatusNotFound},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := httptest.NewRecorder()
+			req, _ := http.NewRequest("GET", tt.path, nil)
+			router.ServeHTTP(w, req)
+
+			if w.Code != tt.status {
+				t.Fatalf("Expected status %d, got %d: %s", tt.status, w.Code, w.Body.String())
+			}
+			for _, want := range tt.contains {
+				if !strings.Contains(w.Body.String(), want) {
+					t.Errorf("Expected page to contain %q", want)
+				}
+			}
+			for _, unwanted := range tt.excludes {
+				if strings.Contains(w.Body.String(), unwanted) {
+					t.Errorf("Expected page not to contain %q", unwanted)
+				}
+			}
+		})
+	}
+
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest("GET", "/testuser/euro/r/"+requests["open"].PublicID+"?format=json", nil)
+	router.ServeHTTP(w, req)
+	var body map[string]interface{}
+	if err := json.Unmarshal(w.Body.Bytes(), &body); err != nil {
+		t.Fatalf("Failed to decode response: %v", err)
+	}
+	if body["amount"] != "12.50" || body["currency"] != "EUR" || body["reference"] != "RF18539007547034" || body["status"] != "open" {
+		t.Errorf("Unexpected JSON: %v", body)
+	}
+}
+
+func TestRequestQRQuery(t *testing.T) {
+	request := model.PaymentRequest{Amount: 1250, Currency: "CHF", Reference: "RF18539007547034", Description: "Dinner"}
+	tests := []struct {
+		format string
+		iban   model.Iban
+		want   string
+	}{
+		{format: formatQRBill, want: "amount=12.50&currency=CHF&reference=RF18539007547034"},
+		{format: formatQRBill, iban: model.Iban{Currency: "CHF"}, want: "amount=12.50&currency=CHF&reference=RF18539007547034"},
+		{format: formatQRBill, iban: model.Iban{Currency: "EUR"}, want: "reference=RF18539007547034"},
+		{format: formatEPC, want: "reference=RF18539007547034"},
+		{format: formatKarekod, want: "remittance=RF18539007547034"},
+	}
+	for _, tt := range tests {
+		if got := requestQRQuery(tt.format, tt.iban, request); got != tt.want {
+			t.Errorf("requestQRQuery(%s) = %s, want %s", tt.format, got, tt.want)
+		}
+	}
+
+	request = model.PaymentRequest{Amount: 10000, Currency: "TRY", Description: "Kira"}
+	if got := requestQRQuery(formatKarekod, model.Iban{}, request); got != "amount=100.00&remittance=Kira" {
+		t.Errorf("requestQRQuery(karekod) = %s", got)
+	}
+}
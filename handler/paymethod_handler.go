@@ -0,0 +1,77 @@
+package handler
+
+import (
+	"errors"
+	"html/template"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/tapsilat/iban.im/model"
+)
+
+var errNotIBAN = errors.New("this payment method is not an IBAN")
+
+// renderMethodPage shows a public payment method other than an IBAN with
+// its details and, where it has one, the wallet or payment page URI as link
+// and QR code. RenderIbanPage delegates to it.
+func renderMethodPage(c *gin.Context, owner ibanOwner, iban model.Iban) {
+	method, ok := iban.PaymentMethod()
+	if !ok {
+		c.HTML(http.StatusNotFound, "error.tmpl.html", gin.H{
+			"error": "IBAN not found or is private",
+		})
+		return
+	}
+	account := iban.Account()
+	fields := []gin.H{}
+	for _, field := range method.Fields(account) {
+		fields = append(fields, gin.H{
+			"label": field.Label,
+			"value": field.Value,
+			"copy":  field.Copy,
+		})
+	}
+
+	if c.GetHeader("Accept") == "application/json" || c.Query("format") == "json" {
+		c.JSON(http.StatusOK, owner.fields(gin.H{
+			"ibanHandle":  iban.Handle,
+			"method":      iban.Method,
+			"label":       method.Label(),
+			"account":     method.Print(account),
+			"fields":      fields,
+			"uri":         method.URI(account),
+			"description": iban.Description,
+			"holderName":  iban.Holder(owner),
+			"accountType": iban.AccountType,
+			"currency":    iban.Currency,
+		}))
+		return
+	}
+
+	// bitcoin: and ethereum: are not among the URL schemes html/template
+	// trusts; links were checked against the known providers
+	uri := template.URL(method.URI(account))
+	c.HTML(http.StatusOK, "method.tmpl.html", owner.fields(gin.H{
+		"ibanHandle":  iban.Handle,
+		"label":       method.Label(),
+		"fields":      fields,
+		"uri":         uri,
+		"description": iban.Description,
+		"holderName":  iban.Holder(owner),
+		"accountType": iban.AccountType,
+		"currency":    iban.Currency,
+	}))
+}
+
+// methodPayload is the QR code payload of a payment method other than an
+// IBAN: its wallet or payment page URI
+func methodPayload(iban model.Iban) (string, error) {
+	method, ok := iban.PaymentMethod()
+	if !ok {
+		return "", errNoQRFormat
+	}
+	if uri := method.URI(iban.Account()); uri != "" {
+		return uri, nil
+	}
+	return "", errNoQRFormat
+}
@@ -0,0 +1,117 @@
+package handler
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/tapsilat/iban.im/config"
+	"github.com/tapsilat/iban.im/model"
+)
+
+func TestRenderMethodPage(t *testing.T) {
+	db := setupTestDB(t)
+	originalDB := config.DB
+	config.DB = db
+	defer func() {
+		config.DB = originalDB
+		sqlDB, _ := db.DB()
+		if sqlDB != nil {
+			sqlDB.Close()
+		}
+	}()
+
+	user := createTestUser(t, db, "test@example.com", "password123", "testuser", "Test", "User")
+	for _, iban := range []model.Iban{
+		{Method: "sortcode", Text: "3141 5926", RoutingCode: "20-00-00", Handle: "pounds", OwnerID: user.UserID},
+		{Method: "crypto", Text: "BC1QW508D6QEJXTDG4Y5R3ZARVARY0C5XW7KV8F3T4", Handle: "bitcoin", OwnerID: user.UserID},
+		{Method: "link", Text: "paypal.me/testuser", Handle: "paypal", OwnerID: user.UserID},
+	} {
+		if err := db.Create(&iban).Error; err != nil {
+			t.Fatalf("Failed to create test payment method: %v", err)
+		}
+	}
+
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	router.LoadHTMLGlob("../templates/*.tmpl.html")
+	router.GET("/:userHandle/:ibanHandle", RenderIbanPage)
+	router.GET("/:userHandle/:ibanHandle/qr.svg", RenderIbanQR("svg"))
+
+	tests := []struct {
+		name    string
+		path    string
+		status  int
+		want    []string
+		notWant []string
+	}{
+		{
+			name:    "UK account",
+			path:    "/testuser/pounds",
+			status:  http.StatusOK,
+			want:    []string{"UK bank account", "Sort code", "20-00-00", "31415926"},
+			notWant: []string{"qr.svg", "Pay now"},
+		},
+		{
+			name:   "Bitcoin address",
+			path:   "/testuser/bitcoin",
+			status: http.StatusOK,
+			want:   []string{"Bitcoin address", "bc1qw508d6qejxtdg4y5r3zarvary0c5xw7kv8f3t4", `href="bitcoin:bc1qw508d6qejxtdg4y5r3zarvary0c5xw7kv8f3t4"`, "/testuser/bitcoin/qr.svg"},
+		},
+		{
+			name:   "Payment link",
+			path:   "/testuser/paypal",
+			status: http.StatusOK,
+			want:   []string{"PayPal.me", `href="https://paypal.me/testuser"`},
+		},
+		{name: "QR code of a wallet URI", path: "/testuser/bitcoin/qr.svg", status: http.StatusOK, want: []string{"<svg"}},
+		{name: "No QR code for a UK account", path: "/testuser/pounds/qr.svg", status: http.StatusUnprocessableEntity},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := httptest.NewRecorder()
+			req, _ := http.NewRequest("GET", tt.path, nil)
+			router.ServeHTTP(w, req)
+
+			if w.Code != tt.status {
+				t.Fatalf("Expected status %d, got %d: %s", tt.status, w.Code, w.Body.String())
+			}
+			for _, want := range tt.want {
+				if !strings.Contains(w.Body.String(), want) {
+					t.Errorf("Page lacks %s", want)
+				}
+			}
+			for _, notWant := range tt.notWant {
+				if strings.Contains(w.Body.String(), notWant) {
+					t.Errorf("Page has %s", notWant)
+				}
+			}
+		})
+	}
+
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest("GET", "/testuser/pounds?format=json", nil)
+	router.ServeHTTP(w, req)
+	var body struct {
+		Method  string `json:"method"`
+		Account string `json:"account"`
+		Fields  []struct {
+			Label string `json:"label"`
+			Copy  string `json:"copy"`
+		} `json:"fields"`
+	}
+	if err := json.Unmarshal(w.Body.Bytes(), &body); err != nil {
+		t.Fatalf("Failed to decode response: %v", err)
+	}
+	if body.Method != "sortcode" || body.Account != "20-00-00 31415926" || len(body.Fields) != 2 || body.Fields[0].Copy != "200000" {
+		t.Errorf("JSON = %+v, want the UK account", body)
+	}
+
+	if _, err := batchTransfer(Pain001Transfer{UserHandle: "testuser", IbanHandle: "pounds", Amount: "10"}); err != errNotIBAN {
+		t.Errorf("batchTransfer() = %v, want %v", err, errNotIBAN)
+	}
+}
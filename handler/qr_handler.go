@@ -0,0 +1,346 @@
+package handler
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"github.com/tapsilat/iban.im/bankdir"
+	"github.com/tapsilat/iban.im/cents"
+	"github.com/tapsilat/iban.im/epcqr"
+	"github.com/tapsilat/iban.im/iso11649"
+	"github.com/tapsilat/iban.im/iso13616"
+	"github.com/tapsilat/iban.im/karekod"
+	"github.com/tapsilat/iban.im/model"
+	"github.com/tapsilat/iban.im/payto"
+	"github.com/tapsilat/iban.im/qrimage"
+	"github.com/tapsilat/iban.im/swissqr"
+)
+
+// qrSize is the width and height of PNG QR codes in pixels
+const qrSize = 300
+
+// QR payload formats selectable with ?format=
+const (
+	formatEPC     = "epc"
+	formatKarekod = "karekod"
+	formatQRBill  = "qrbill"
+)
+
+var (
+	errUserNotFound = errors.New("User not found")
+	errIbanNotFound = errors.New("IBAN not found or is private")
+	errNoQRFormat   = errors.New("no QR code format is available for this IBAN")
+	errAmount       = errors.New("amount must be a positive number with at most two decimals")
+	errCurrency     = errors.New("an amount needs a three letter ISO 4217 currency")
+	errRemittance   = errors.New("a creditor reference replaces the remittance text, give only one of them")
+	errEPCCurrency  = errors.New("EPC QR codes are in EUR only, use the payto link for amounts in other currencies")
+	errTRYCurrency  = errors.New("Karekods are in TRY only, use the payto link for amounts in other currencies")
+	errBillCurrency = errors.New("a QR-bill is in the currency of the account")
+)
+
+// defaultQRFormat picks the QR standard that banking apps of the IBAN's
+// country scan, or an empty string if there is none. Swiss QR-bills need
+// the owner's address, CH and LI IBANs fall back to EPC without one.
+func defaultQRFormat(owner ibanOwner, iban string) string {
+	switch cc := iso13616.CountryCode(iban); {
+	case (cc == "CH" || cc == "LI") && creditorAddress(owner).Town != "":
+		return formatQRBill
+	case cc == "TR":
+		return formatKarekod
+	case iso13616.IsSEPA(iban):
+		return formatEPC
+	}
+	return ""
+}
+
+// ibanBIC is the BIC given by the owner, or else the bank directory's
+func ibanBIC(iban model.Iban) string {
+	if iban.BIC != "" {
+		return iban.BIC
+	}
+	bank, _ := bankdir.LookupIBAN(iban.Text)
+	return bank.BIC
+}
+
+// creditorAddress is the owner's postal address as printed on QR-bills
+func creditorAddress(owner ibanOwner) swissqr.Address {
+	return swissqr.Address{
+		Name:           owner.DisplayName(),
+		Street:         owner.Street,
+		BuildingNumber: owner.BuildingNumber,
+		PostalCode:     owner.PostalCode,
+		Town:           owner.Town,
+		Country:        owner.Country,
+	}
+}
+
+// creditorReference reads the optional ISO 11649 RF reference that EPC codes
+// and payto URIs carry instead of the remittance text
+func creditorReference(c *gin.Context) (string, error) {
+	ref := c.Query("reference")
+	if ref == "" {
+		return "", nil
+	}
+	if err := iso11649.Validate(ref); err != nil {
+		return "", err
+	}
+	if c.Query("remittance") != "" {
+		return "", errRemittance
+	}
+	return iso11649.Normalize(ref), nil
+}
+
+// ibanPayment fills the EPC credit transfer for a public IBAN from the holder
+// and the optional amount, currency, reference and remittance query
+// parameters
+func ibanPayment(c *gin.Context, owner ibanOwner, iban model.Iban) (epcqr.Payment, error) {
+	payment := epcqr.Payment{
+		BIC:  ibanBIC(iban),
+		Name: iban.Holder(owner),
+		IBAN: iban.Text,
+		Text: c.Query("remittance"),
+	}
+	ref, err := creditorReference(c)
+	if err != nil {
+		return payment, err
+	}
+	payment.Reference = ref
+	amount, err := ParseAmount(c.Query("amount"))
+	if err != nil {
+		return payment, err
+	}
+	// EPC amounts are always read as euros, so others must not be relabelled
+	currency := strings.ToUpper(c.DefaultQuery("currency", iban.Currency))
+	if amount > 0 && currency != "" && currency != "EUR" {
+		return payment, errEPCCurrency
+	}
+	payment.Amount = amount
+	return payment, nil
+}
+
+// ibanPayto is the payto URI of a public IBAN with the holder as receiver
+// and the optional amount, currency and reference or remittance query
+// parameters; the currency defaults to the account's and payto has no
+// reference field, so it goes into the message
+func ibanPayto(c *gin.Context, owner ibanOwner, iban model.Iban) (string, error) {
+	uri := payto.URI{
+		IBAN:         iban.Text,
+		BIC:          ibanBIC(iban),
+		ReceiverName: iban.Holder(owner),
+		Currency:     strings.ToUpper(c.DefaultQuery("currency", iban.Currency)),
+		Message:      c.Query("remittance"),
+	}
+	ref, err := creditorReference(c)
+	if err != nil {
+		return "", err
+	}
+	if ref != "" {
+		uri.Message = ref
+	}
+	amount, err := ParseAmount(c.Query("amount"))
+	if err != nil {
+		return "", err
+	}
+	if amount > 0 && (len(uri.Currency) != 3 || strings.Trim(uri.Currency, "ABCDEFGHIJKLMNOPQRSTUVWXYZ") != "") {
+		return "", errCurrency
+	}
+	uri.Amount = amount
+	return uri.String(), nil
+}
+
+// karekodPayment fills the FAST transfer for a public TR IBAN the same way;
+// Karekod has no reference field, so the reference becomes the purpose
+func karekodPayment(c *gin.Context, owner ibanOwner, iban model.Iban) (karekod.Payment, error) {
+	payment := karekod.Payment{
+		Name: iban.Holder(owner),
+		IBAN: iban.Text,
+		Text: c.Query("remittance"),
+	}
+	ref, err := creditorReference(c)
+	if err != nil {
+		return payment, err
+	}
+	if ref != "" {
+		payment.Text = ref
+	}
+	amount, err := ParseAmount(c.Query("amount"))
+	if err != nil {
+		return payment, err
+	}
+	// Karekod amounts are always read as lira
+	currency := strings.ToUpper(c.DefaultQuery("currency", iban.Currency))
+	if amount > 0 && currency != "" && currency != "TRY" {
+		return payment, errTRYCurrency
+	}
+	payment.Amount = amount
+	return payment, nil
+}
+
+// swissBill fills the QR-bill for a public CH or LI IBAN from the holder,
+// the owner's address and the optional amount, currency, reference and
+// remittance query parameters
+func swissBill(c *gin.Context, owner ibanOwner, iban model.Iban) (swissqr.Bill, error) {
+	creditor := creditorAddress(owner)
+	creditor.Name = iban.Holder(owner)
+	bill := swissqr.Bill{
+		IBAN:      iban.Text,
+		Creditor:  creditor,
+		Currency:  iban.Currency,
+		Reference: c.Query("reference"),
+		Message:   c.Query("remittance"),
+	}
+	if currency := c.Query("currency"); currency != "" {
+		if iban.Currency != "" && !strings.EqualFold(currency, iban.Currency) {
+			return bill, errBillCurrency
+		}
+		bill.Currency = currency
+	}
+	amount, err := ParseAmount(c.Query("amount"))
+	bill.Amount = amount
+	return bill, err
+}
+
+// qrFormat is the format asked for with ?format= or the IBAN's default
+func qrFormat(c *gin.Context, owner ibanOwner, iban model.Iban) string {
+	return c.DefaultQuery("format", defaultQRFormat(owner, iban.Text))
+}
+
+// qrPayload builds the payload in the requested or the IBAN's default
+// format, or the URI of other payment methods
+func qrPayload(c *gin.Context, owner ibanOwner, iban model.Iban) (string, error) {
+	if !iban.IsIBAN() {
+		return methodPayload(iban)
+	}
+	switch format := qrFormat(c, owner, iban); format {
+	case formatEPC:
+		payment, err := ibanPayment(c, owner, iban)
+		if err != nil {
+			return "", err
+		}
+		return payment.Payload()
+	case formatKarekod:
+		payment, err := karekodPayment(c, owner, iban)
+		if err != nil {
+			return "", err
+		}
+		return payment.Payload()
+	case formatQRBill:
+		bill, err := swissBill(c, owner, iban)
+		if err != nil {
+			return "", err
+		}
+		return bill.Payload()
+	case "":
+		return "", errNoQRFormat
+	default:
+		return "", fmt.Errorf("unknown QR code format %q", format)
+	}
+}
+
+// FormatAmount renders cents as decimal amount such as "12.50", the
+// reverse of ParseAmount
+func FormatAmount(amount int64) string {
+	return cents.Format(amount)
+}
+
+// ParseAmount converts a decimal amount such as "12.50" or "12,5" into
+// cents, 0 for an empty string. Amounts must be positive.
+func ParseAmount(s string) (int64, error) {
+	if strings.TrimSpace(s) == "" {
+		return 0, nil
+	}
+	amount, err := cents.Parse(s)
+	if err != nil || amount == 0 {
+		return 0, errAmount
+	}
+	return amount, nil
+}
+
+// RenderIbanQR serves the payment QR code of a public IBAN as "png" or "svg"
+// image, e.g. /:userHandle/:ibanHandle/qr.png?amount=12.50&remittance=Dinner
+// or /g/:groupHandle/:ibanHandle/qr.svg.
+// SEPA IBANs get an EPC "GiroCode", TR IBANs a Karekod and CH and LI IBANs
+// of owners with an address the Swiss QR code unless another format is asked
+// for with ?format=epc, ?format=karekod or ?format=qrbill.
+func RenderIbanQR(imageType string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		owner, iban, err := findOwnerIban(c)
+		if err != nil {
+			c.JSON(http.StatusNotFound, gin.H{
+				"error": err.Error(),
+			})
+			return
+		}
+
+		payload, err := qrPayload(c, owner, iban)
+		if err != nil {
+			c.JSON(qrErrorStatus(err), gin.H{
+				"error": err.Error(),
+			})
+			return
+		}
+
+		var opts []qrimage.Option
+		if qrFormat(c, owner, iban) == formatQRBill {
+			opts = append(opts, qrimage.WithSwissCross())
+		}
+		if imageType == "svg" {
+			image, err := qrimage.SVG(payload, opts...)
+			if err != nil {
+				c.JSON(http.StatusInternalServerError, gin.H{
+					"error": err.Error(),
+				})
+				return
+			}
+			c.Data(http.StatusOK, "image/svg+xml", image)
+			return
+		}
+		image, err := qrimage.PNG(payload, qrSize, opts...)
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{
+				"error": err.Error(),
+			})
+			return
+		}
+		c.Data(http.StatusOK, "image/png", image)
+	}
+}
+
+// qrErrorStatus tells details the IBAN or its owner cannot satisfy from bad
+// query parameters
+func qrErrorStatus(err error) int {
+	switch {
+	case errors.Is(err, errNoQRFormat),
+		errors.Is(err, errEPCCurrency),
+		errors.Is(err, errTRYCurrency),
+		errors.Is(err, errBillCurrency),
+		errors.Is(err, epcqr.ErrNotSEPA),
+		errors.Is(err, karekod.ErrNotTR),
+		errors.Is(err, swissqr.ErrCountry),
+		errors.Is(err, swissqr.ErrAddress):
+		return http.StatusUnprocessableEntity
+	}
+	return http.StatusBadRequest
+}
+
+// renderQRBill serves the printable payment part with receipt of a CH or LI
+// IBAN as PDF, for RenderIbanPage with ?format=qrbill
+func renderQRBill(c *gin.Context, owner ibanOwner, iban model.Iban) {
+	bill, err := swissBill(c, owner, iban)
+	var pdf bytes.Buffer
+	if err == nil {
+		err = bill.PDF(&pdf)
+	}
+	if err != nil {
+		c.JSON(qrErrorStatus(err), gin.H{
+			"error": err.Error(),
+		})
+		return
+	}
+	c.Header("Content-Disposition", fmt.Sprintf("inline; filename=%q", iban.Handle+"-qrbill.pdf"))
+	c.Data(http.StatusOK, "application/pdf", pdf.Bytes())
+}
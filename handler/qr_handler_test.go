@@ -0,0 +1,311 @@
+package handler
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/tapsilat/iban.im/config"
+	"github.com/tapsilat/iban.im/model"
+)
+
+func TestRenderIbanQR(t *testing.T) {
+	db := setupTestDB(t)
+	originalDB := config.DB
+	config.DB = db
+	defer func() {
+		config.DB = originalDB
+		sqlDB, _ := db.DB()
+		if sqlDB != nil {
+			sqlDB.Close()
+		}
+	}()
+
+	user := createTestUser(t, db, "test@example.com", "password123", "testuser", "Test", "User")
+	createTestIban(t, db, user.UserID, "DE89370400440532013000", "euro", "", false)
+	createTestIban(t, db, user.UserID, "TR330006100519786457841326", "lira", "", false)
+	createTestIban(t, db, user.UserID, "DE89370400440532013000", "secret", "pass", true)
+	createTestIban(t, db, user.UserID, "BR1800360305000010009795493C1", "other", "", false)
+	dollars := createTestIban(t, db, user.UserID, "DE02120300000000202051", "dollars", "", false)
+	db.Model(dollars).Update("currency", "USD")
+	liraDollars := createTestIban(t, db, user.UserID, "TR320010009999901234567890", "tldollars", "", false)
+	db.Model(liraDollars).Update("currency", "USD")
+
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	router.GET("/:userHandle/:ibanHandle", RenderIbanPage)
+	router.GET("/:userHandle/:ibanHandle/qr.png", RenderIbanQR("png"))
+	router.GET("/:userHandle/:ibanHandle/qr.svg", RenderIbanQR("svg"))
+
+	tests := []struct {
+		name        string
+		path        string
+		status      int
+		contentType string
+	}{
+		{name: "PNG", path: "/testuser/euro/qr.png", status: http.StatusOK, contentType: "image/png"},
+		{name: "SVG with amount", path: "/testuser/euro/qr.svg?amount=12.50&remittance=Dinner", status: http.StatusOK, contentType: "image/svg+xml"},
+		{name: "Invalid amount", path: "/testuser/euro/qr.png?amount=-1", status: http.StatusBadRequest},
+		{name: "EPC amount in another currency", path: "/testuser/euro/qr.png?amount=10&currency=usd", status: http.StatusUnprocessableEntity},
+		{name: "EPC amount for an account in another currency", path: "/testuser/dollars/qr.png?amount=10", status: http.StatusUnprocessableEntity},
+		{name: "EPC without amount for an account in another currency", path: "/testuser/dollars/qr.png", status: http.StatusOK, contentType: "image/png"},
+		{name: "EPC amount in EUR for an account in another currency", path: "/testuser/dollars/qr.png?amount=10&currency=EUR", status: http.StatusOK, contentType: "image/png"},
+		{name: "Karekod for TR IBAN", path: "/testuser/lira/qr.png?amount=100", status: http.StatusOK, contentType: "image/png"},
+		{name: "Karekod amount for an account in another currency", path: "/testuser/tldollars/qr.png?amount=100", status: http.StatusUnprocessableEntity},
+		{name: "Karekod amount in another currency", path: "/testuser/lira/qr.png?amount=100&currency=EUR", status: http.StatusUnprocessableEntity},
+		{name: "Karekod without amount for an account in another currency", path: "/testuser/tldollars/qr.png", status: http.StatusOK, contentType: "image/png"},
+		{name: "EPC asked for TR IBAN", path: "/testuser/lira/qr.png?format=epc", status: http.StatusUnprocessableEntity},
+		{name: "Karekod asked for DE IBAN", path: "/testuser/euro/qr.svg?format=karekod", status: http.StatusUnprocessableEntity},
+		{name: "Unknown format", path: "/testuser/euro/qr.svg?format=bezahlcode", status: http.StatusBadRequest},
+		{name: "No format for the country", path: "/testuser/other/qr.png", status: http.StatusUnprocessableEntity},
+		{name: "Private IBAN", path: "/testuser/secret/qr.png", status: http.StatusNotFound},
+		{name: "Unknown user", path: "/nobody/euro/qr.svg", status: http.StatusNotFound},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := httptest.NewRecorder()
+			req, _ := http.NewRequest("GET", tt.path, nil)
+			router.ServeHTTP(w, req)
+
+			if w.Code != tt.status {
+				t.Fatalf("Expected status %d, got %d: %s", tt.status, w.Code, w.Body.String())
+			}
+			if tt.contentType != "" && w.Header().Get("Content-Type") != tt.contentType {
+				t.Errorf("Content-Type = %s, want %s", w.Header().Get("Content-Type"), tt.contentType)
+			}
+		})
+	}
+}
+
+func TestRenderQRBill(t *testing.T) {
+	db := setupTestDB(t)
+	originalDB := config.DB
+	config.DB = db
+	defer func() {
+		config.DB = originalDB
+		sqlDB, _ := db.DB()
+		if sqlDB != nil {
+			sqlDB.Close()
+		}
+	}()
+
+	user := createTestUser(t, db, "robert@example.com", "password123", "robert", "Robert", "Schneider")
+	user.Street, user.BuildingNumber, user.PostalCode, user.Town, user.Country = "Rue du Lac", "1268", "2501", "Biel", "CH"
+	if err := db.Save(user).Error; err != nil {
+		t.Fatalf("Failed to save address: %v", err)
+	}
+	createTestIban(t, db, user.UserID, "CH4431999123000889012", "qr", "", false)
+	createTestIban(t, db, user.UserID, "CH9300762011623852957", "franc", "", false)
+	createTestIban(t, db, user.UserID, "DE89370400440532013000", "euro", "", false)
+	euroFranc := createTestIban(t, db, user.UserID, "CH5604835012345678009", "eurofranc", "", false)
+	db.Model(euroFranc).Update("currency", "EUR")
+	other := createTestUser(t, db, "test@example.com", "password123", "testuser", "Test", "User")
+	createTestIban(t, db, other.UserID, "CH9300762011623852957", "franc", "", false)
+
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	router.GET("/:userHandle/:ibanHandle", RenderIbanPage)
+	router.GET("/:userHandle/:ibanHandle/qr.svg", RenderIbanQR("svg"))
+
+	tests := []struct {
+		name        string
+		path        string
+		status      int
+		contentType string
+	}{
+		{name: "PDF with QR reference", path: "/robert/qr?format=qrbill&amount=1949.75&reference=210000000003139471430009017", status: http.StatusOK, contentType: "application/pdf"},
+		{name: "PDF with creditor reference", path: "/robert/franc?format=qrbill&reference=RF18539007547034&currency=EUR", status: http.StatusOK, contentType: "application/pdf"},
+		{name: "PDF without reference", path: "/robert/franc?format=qrbill", status: http.StatusOK, contentType: "application/pdf"},
+		{name: "QR-IBAN without reference", path: "/robert/qr?format=qrbill", status: http.StatusBadRequest},
+		{name: "Unsupported currency", path: "/robert/franc?format=qrbill&currency=USD", status: http.StatusBadRequest},
+		{name: "Account currency", path: "/robert/eurofranc?format=qrbill&amount=10", status: http.StatusOK, contentType: "application/pdf"},
+		{name: "Account currency given", path: "/robert/eurofranc?format=qrbill&amount=10&currency=eur", status: http.StatusOK, contentType: "application/pdf"},
+		{name: "Other than the account currency", path: "/robert/eurofranc?format=qrbill&amount=10&currency=CHF", status: http.StatusUnprocessableEntity},
+		{name: "Not a Swiss IBAN", path: "/robert/euro?format=qrbill", status: http.StatusUnprocessableEntity},
+		{name: "Owner without address", path: "/testuser/franc?format=qrbill", status: http.StatusUnprocessableEntity},
+		{name: "Swiss QR code", path: "/robert/qr/qr.svg?reference=210000000003139471430009017", status: http.StatusOK, contentType: "image/svg+xml"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := httptest.NewRecorder()
+			req, _ := http.NewRequest("GET", tt.path, nil)
+			router.ServeHTTP(w, req)
+
+			if w.Code != tt.status {
+				t.Fatalf("Expected status %d, got %d: %s", tt.status, w.Code, w.Body.String())
+			}
+			if tt.contentType != "" && w.Header().Get("Content-Type") != tt.contentType {
+				t.Errorf("Content-Type = %s, want %s", w.Header().Get("Content-Type"), tt.contentType)
+			}
+			if tt.contentType == "application/pdf" && !strings.HasPrefix(w.Body.String(), "%PDF-") {
+				t.Error("Expected a PDF document")
+			}
+		})
+	}
+}
+
+func TestIbanPayment(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	c, _ := gin.CreateTestContext(httptest.NewRecorder())
+	c.Request, _ = http.NewRequest("GET", "/?amount=5&remittance=Rent", nil)
+
+	user := ibanOwner{User: model.User{FirstName: "Franz", LastName: "Mustermann", Handle: "franz"}}
+	iban := model.Iban{Text: "DE89370400440532013000"}
+	payment, err := ibanPayment(c, user, iban)
+	if err != nil {
+		t.Fatalf("ibanPayment() returned unexpected error: %v", err)
+	}
+	payload, err := payment.Payload()
+	if err != nil {
+		t.Fatalf("Payload() returned unexpected error: %v", err)
+	}
+	want := "BCD\n002\n1\nSCT\nCOBADEFFXXX\nFranz Mustermann\nDE89370400440532013000\nEUR5.00\n\n\nRent"
+	if payload != want {
+		t.Errorf("Payload() = %q, want %q", payload, want)
+	}
+
+	// A creditor reference takes the place of the remittance text
+	c, _ = gin.CreateTestContext(httptest.NewRecorder())
+	c.Request, _ = http.NewRequest("GET", "/?reference=rf18+5390+0754+7034", nil)
+	if payment, err = ibanPayment(c, user, iban); err != nil {
+		t.Fatalf("ibanPayment() returned unexpected error: %v", err)
+	}
+	if payment.Reference != "RF18539007547034" || payment.Text != "" {
+		t.Errorf("ibanPayment() reference = %q, text = %q", payment.Reference, payment.Text)
+	}
+	for _, query := range []string{"/?reference=RF19539007547034", "/?reference=RF18539007547034&remittance=Rent"} {
+		c, _ = gin.CreateTestContext(httptest.NewRecorder())
+		c.Request, _ = http.NewRequest("GET", query, nil)
+		if _, err := ibanPayment(c, user, iban); err == nil {
+			t.Errorf("ibanPayment() with %s should fail", query)
+		}
+	}
+}
+
+func TestSwissBillCurrency(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	user := ibanOwner{User: model.User{FirstName: "Robert", LastName: "Schneider"}}
+
+	tests := []struct {
+		name     string
+		currency string
+		query    string
+		want     string
+		wantErr  bool
+	}{
+		{name: "Account without currency", query: "?currency=EUR", want: "EUR"},
+		{name: "Account currency", currency: "EUR", want: "EUR"},
+		{name: "Account currency given", currency: "EUR", query: "?currency=eur", want: "eur"},
+		{name: "Other currency given", currency: "EUR", query: "?currency=CHF", wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c, _ := gin.CreateTestContext(httptest.NewRecorder())
+			c.Request, _ = http.NewRequest("GET", "/"+tt.query, nil)
+			bill, err := swissBill(c, user, model.Iban{Text: "CH9300762011623852957", Currency: tt.currency})
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("swissBill() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if bill.Currency != tt.want && !tt.wantErr {
+				t.Errorf("Currency = %q, want %q", bill.Currency, tt.want)
+			}
+		})
+	}
+}
+
+func TestQRPayloadDefaultFormat(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	c, _ := gin.CreateTestContext(httptest.NewRecorder())
+	c.Request, _ = http.NewRequest("GET", "/", nil)
+	user := ibanOwner{User: model.User{FirstName: "Ali", LastName: "Veli"}}
+
+	payload, err := qrPayload(c, user, model.Iban{Text: "TR330006100519786457841326"})
+	if err != nil || !strings.HasPrefix(payload, "000201") {
+		t.Errorf("Expected a Karekod payload for TR, got %q, %v", payload, err)
+	}
+	payload, err = qrPayload(c, user, model.Iban{Text: "DE89370400440532013000"})
+	if err != nil || !strings.HasPrefix(payload, "BCD\n") {
+		t.Errorf("Expected an EPC payload for DE, got %q, %v", payload, err)
+	}
+	payload, err = qrPayload(c, user, model.Iban{Text: "CH9300762011623852957"})
+	if err != nil || !strings.HasPrefix(payload, "BCD\n") {
+		t.Errorf("Expected an EPC payload for CH without address, got %q, %v", payload, err)
+	}
+	user.PostalCode, user.Town, user.Country = "2501", "Biel", "CH"
+	payload, err = qrPayload(c, user, model.Iban{Text: "CH9300762011623852957"})
+	if err != nil || !strings.HasPrefix(payload, "SPC\n") {
+		t.Errorf("Expected a QR-bill payload for CH, got %q, %v", payload, err)
+	}
+}
+
+func TestKarekodReference(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	user := ibanOwner{User: model.User{FirstName: "Ali", LastName: "Veli"}}
+	iban := model.Iban{Text: "TR330006100519786457841326"}
+
+	tests := []struct {
+		name    string
+		query   string
+		want    string
+		wantErr bool
+	}{
+		{name: "Reference as purpose", query: "?reference=rf18+5390+0754+7034", want: "RF18539007547034"},
+		{name: "Remittance as purpose", query: "?remittance=Kira", want: "Kira"},
+		{name: "Invalid reference", query: "?reference=RF00123", wantErr: true},
+		{name: "Reference and remittance", query: "?reference=RF18539007547034&remittance=Kira", wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c, _ := gin.CreateTestContext(httptest.NewRecorder())
+			c.Request, _ = http.NewRequest("GET", "/"+tt.query, nil)
+			payment, err := karekodPayment(c, user, iban)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("karekodPayment() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if payment.Text != tt.want && !tt.wantErr {
+				t.Errorf("Text = %q, want %q", payment.Text, tt.want)
+			}
+		})
+	}
+}
+
+func TestParseAmount(t *testing.T) {
+	tests := []struct {
+		amount  string
+		want    int64
+		wantErr bool
+	}{
+		{amount: "", want: 0},
+		{amount: "12.50", want: 1250},
+		{amount: "12,5", want: 1250},
+		{amount: "7", want: 700},
+		{amount: " 0.01 ", want: 1},
+		{amount: "0", wantErr: true},
+		{amount: "-5", wantErr: true},
+		{amount: "1.234", wantErr: true},
+		{amount: "abc", wantErr: true},
+		{amount: ".5", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		got, err := ParseAmount(tt.amount)
+		if tt.wantErr {
+			if err == nil {
+				t.Errorf("ParseAmount(%q) = %d, want error", tt.amount, got)
+			}
+			continue
+		}
+		if err != nil || got != tt.want {
+			t.Errorf("ParseAmount(%q) = %d, %v, want %d", tt.amount, got, err, tt.want)
+		}
+		if got > 0 {
+			if back, _ := ParseAmount(FormatAmount(got)); back != got {
+				t.Errorf("FormatAmount(%d) = %s does not parse back", got, FormatAmount(got))
+			}
+		}
+	}
+}
@@ -0,0 +1,181 @@
+package handler
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"github.com/tapsilat/iban.im/bankdir"
+	"github.com/tapsilat/iban.im/iso13616"
+)
+
+// ValidationResult is the outcome for one row of a batch validation
+type ValidationResult struct {
+	Row        int    `json:"row"`
+	Input      string `json:"input"`
+	Valid      bool   `json:"valid"`
+	Reason     string `json:"reason,omitempty"`
+	Normalized string `json:"normalized"`
+	Bank       string `json:"bank,omitempty"`
+	BIC        string `json:"bic,omitempty"`
+	SEPA       bool   `json:"sepa"`
+}
+
+// ValidateIbans checks a batch of IBANs posted as a JSON array (strings or
+// objects with an "iban" field) or as CSV, either as the request body or as
+// the "file" field of a multipart upload. Results are streamed back as
+// newline delimited JSON, one line per row, so large files do not have to
+// be held in memory. At most rowLimit rows are checked, 0 means no limit.
+func ValidateIbans(rowLimit int) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		next, err := ibanRows(c)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{
+				"error": err.Error(),
+			})
+			return
+		}
+
+		c.Header("Content-Type", "application/x-ndjson")
+		c.Status(http.StatusOK)
+		encoder := json.NewEncoder(c.Writer)
+		for row := 1; ; row++ {
+			text, err := next()
+			if errors.Is(err, io.EOF) {
+				return
+			}
+			if err == nil && rowLimit > 0 && row > rowLimit {
+				err = fmt.Errorf("row limit of %d exceeded", rowLimit)
+			}
+			if err != nil {
+				encoder.Encode(gin.H{"row": row, "error": err.Error()})
+				return
+			}
+			if err := encoder.Encode(validateIban(row, text)); err != nil {
+				// The client went away
+				return
+			}
+			c.Writer.Flush()
+		}
+	}
+}
+
+// validateIban runs the checks ibanNew applies to a single IBAN
+func validateIban(row int, text string) ValidationResult {
+	result := ValidationResult{Row: row, Input: text, Normalized: iso13616.Normalize(text)}
+	if err := iso13616.Validate(text); err != nil {
+		result.Reason = err.Error()
+		return result
+	}
+	result.Valid = true
+	result.SEPA = iso13616.IsSEPA(text)
+	if bank, ok := bankdir.LookupIBAN(text); ok {
+		result.Bank = bank.Name
+		result.BIC = bank.BIC
+	}
+	return result
+}
+
+// ibanRows returns a reader yielding the IBANs of the request one at a time
+// and io.EOF after the last one
+func ibanRows(c *gin.Context) (func() (string, error), error) {
+	body := io.Reader(c.Request.Body)
+	isJSON := c.ContentType() == "application/json"
+	if c.ContentType() == "multipart/form-data" {
+		header, err := c.FormFile("file")
+		if err != nil {
+			return nil, fmt.Errorf("expected the IBAN list in the \"file\" field")
+		}
+		file, err := header.Open()
+		if err != nil {
+			return nil, err
+		}
+		body = file
+		isJSON = strings.HasSuffix(strings.ToLower(header.Filename), ".json")
+	}
+
+	if isJSON {
+		return jsonRows(body)
+	}
+	return csvRows(body)
+}
+
+// jsonRows decodes the array element by element
+func jsonRows(body io.Reader) (func() (string, error), error) {
+	decoder := json.NewDecoder(body)
+	if token, err := decoder.Token(); err != nil || token != json.Delim('[') {
+		return nil, fmt.Errorf("expected a JSON array of IBANs")
+	}
+	return func() (string, error) {
+		if !decoder.More() {
+			return "", io.EOF
+		}
+		var element json.RawMessage
+		if err := decoder.Decode(&element); err != nil {
+			return "", err
+		}
+		var text string
+		if err := json.Unmarshal(element, &text); err == nil {
+			return text, nil
+		}
+		var object struct {
+			IBAN string `json:"iban"`
+		}
+		if err := json.Unmarshal(element, &object); err != nil {
+			return "", fmt.Errorf("expected an IBAN string or an object with an \"iban\" field")
+		}
+		return object.IBAN, nil
+	}, nil
+}
+
+// csvRows reads the "iban" column when the first line is a header naming
+// one, and the first column otherwise. Semicolon separated files as saved
+// by spreadsheets in many locales are accepted as well.
+func csvRows(body io.Reader) (func() (string, error), error) {
+	buffered := bufio.NewReader(body)
+	peek, _ := buffered.Peek(4096)
+	reader := csv.NewReader(buffered)
+	reader.FieldsPerRecord = -1
+	reader.TrimLeadingSpace = true
+	if line, _, _ := bytes.Cut(peek, []byte("\n")); bytes.Count(line, []byte(";")) > bytes.Count(line, []byte(",")) {
+		reader.Comma = ';'
+	}
+
+	column := 0
+	var pending []string
+	first, err := reader.Read()
+	switch {
+	case errors.Is(err, io.EOF):
+	case err != nil:
+		return nil, err
+	default:
+		pending = first
+		for i, cell := range first {
+			if strings.EqualFold(strings.TrimSpace(strings.TrimPrefix(cell, "\ufeff")), "iban") {
+				column, pending = i, nil
+			}
+		}
+	}
+
+	return func() (string, error) {
+		record := pending
+		pending = nil
+		if record == nil {
+			var err error
+			if record, err = reader.Read(); err != nil {
+				return "", err
+			}
+		}
+		if column >= len(record) {
+			return "", nil
+		}
+		return strings.TrimSpace(record[column]), nil
+	}, nil
+}
@@ -0,0 +1,154 @@
+package handler
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"mime/multipart"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+)
+
+func TestValidateIbans(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	tests := []struct {
+		name        string
+		contentType string
+		body        string
+		limit       int
+		want        []ValidationResult
+		wantError   string
+	}{
+		{
+			name:        "JSON array of strings and objects",
+			contentType: "application/json",
+			body:        `["DE89 3704 0044 0532 0130 00", {"iban": "TR330006100519786457841326"}, "DE88370400440532013000"]`,
+			want: []ValidationResult{
+				{Row: 1, Input: "DE89 3704 0044 0532 0130 00", Valid: true, Normalized: "DE89370400440532013000", Bank: "Commerzbank", BIC: "COBADEFFXXX", SEPA: true},
+				{Row: 2, Input: "TR330006100519786457841326", Valid: true, Normalized: "TR330006100519786457841326"},
+				{Row: 3, Input: "DE88370400440532013000", Reason: "IBAN checksum does not match", Normalized: "DE88370400440532013000"},
+			},
+		},
+		{
+			name:        "CSV with iban column",
+			contentType: "text/csv",
+			body:        "name;IBAN\nAlice;GB82 WEST 1234 5698 7654 32\nBob;\n",
+			want: []ValidationResult{
+				{Row: 1, Input: "GB82 WEST 1234 5698 7654 32", Valid: true, Normalized: "GB82WEST12345698765432", SEPA: true},
+				{Row: 2, Reason: "you have to provide IBAN"},
+			},
+		},
+		{
+			name:        "CSV without header",
+			contentType: "text/csv",
+			body:        "DE89370400440532013000,first\nXX89370400440532013000,second\n",
+			want: []ValidationResult{
+				{Row: 1, Input: "DE89370400440532013000", Valid: true, Normalized: "DE89370400440532013000", Bank: "Commerzbank", BIC: "COBADEFFXXX", SEPA: true},
+				{Row: 2, Input: "XX89370400440532013000", Reason: "unknown IBAN country code: XX", Normalized: "XX89370400440532013000"},
+			},
+		},
+		{
+			name:        "Row limit",
+			contentType: "application/json",
+			body:        `["DE89370400440532013000", "DE89370400440532013000"]`,
+			limit:       1,
+			want: []ValidationResult{
+				{Row: 1, Input: "DE89370400440532013000", Valid: true, Normalized: "DE89370400440532013000", Bank: "Commerzbank", BIC: "COBADEFFXXX", SEPA: true},
+			},
+			wantError: "row limit of 1 exceeded",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			router := gin.New()
+			router.POST("/api/v1/iban/validate", ValidateIbans(tt.limit))
+
+			req, _ := http.NewRequest("POST", "/api/v1/iban/validate", strings.NewReader(tt.body))
+			req.Header.Set("Content-Type", tt.contentType)
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, req)
+
+			if w.Code != http.StatusOK {
+				t.Fatalf("Expected status 200, got %d: %s", w.Code, w.Body.String())
+			}
+			results, lastError := decodeResults(t, w.Body.Bytes())
+			if len(results) != len(tt.want) {
+				t.Fatalf("Expected %d results, got %d: %s", len(tt.want), len(results), w.Body.String())
+			}
+			for i, want := range tt.want {
+				if results[i] != want {
+					t.Errorf("Row %d = %+v, want %+v", i+1, results[i], want)
+				}
+			}
+			if lastError != tt.wantError {
+				t.Errorf("Error = %q, want %q", lastError, tt.wantError)
+			}
+		})
+	}
+}
+
+func TestValidateIbansUpload(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	router.POST("/api/v1/iban/validate", ValidateIbans(0))
+
+	var body bytes.Buffer
+	writer := multipart.NewWriter(&body)
+	part, _ := writer.CreateFormFile("file", "payees.json")
+	part.Write([]byte(`["DE89370400440532013000"]`))
+	writer.Close()
+
+	req, _ := http.NewRequest("POST", "/api/v1/iban/validate", &body)
+	req.Header.Set("Content-Type", writer.FormDataContentType())
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	results, _ := decodeResults(t, w.Body.Bytes())
+	if len(results) != 1 || !results[0].Valid {
+		t.Errorf("Expected one valid row, got %s", w.Body.String())
+	}
+}
+
+func TestValidateIbansBadRequest(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	router.POST("/api/v1/iban/validate", ValidateIbans(0))
+
+	req, _ := http.NewRequest("POST", "/api/v1/iban/validate", strings.NewReader(`{"iban": "DE89370400440532013000"}`))
+	req.Header.Set("Content-Type", "application/json")
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	if w.Code != http.StatusBadRequest {
+		t.Errorf("Expected status 400, got %d", w.Code)
+	}
+}
+
+// decodeResults splits the streamed lines into results and the trailing
+// error line, if any
+func decodeResults(t *testing.T, body []byte) ([]ValidationResult, string) {
+	var results []ValidationResult
+	var lastError string
+	scanner := bufio.NewScanner(bytes.NewReader(body))
+	for scanner.Scan() {
+		var line struct {
+			ValidationResult
+			Error string `json:"error"`
+		}
+		if err := json.Unmarshal(scanner.Bytes(), &line); err != nil {
+			t.Fatalf("Failed to parse line %q: %v", scanner.Text(), err)
+		}
+		if line.Error != "" {
+			lastError = line.Error
+			continue
+		}
+		results = append(results, line.ValidationResult)
+	}
+	return results, lastError
+}
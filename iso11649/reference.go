@@ -0,0 +1,115 @@
+// Package iso11649 creates and validates structured creditor references
+// (RF references) as defined by ISO 11649.
+package iso11649
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"strings"
+	"unicode"
+
+	"github.com/tapsilat/iban.im/iso13616"
+)
+
+// MaxLength is the longest reference Generate accepts, without the "RF"
+// prefix and check digits.
+const MaxLength = 21
+
+// Validation errors. Generate and Validate wrap them with the offending detail, so use
+// errors.Is to tell them apart.
+var (
+	ErrFormat   = errors.New("invalid creditor reference format")
+	ErrChecksum = errors.New("creditor reference checksum does not match")
+)
+
+// Validate checks the "RF" prefix, the two check digits and the reference
+// of up to 21 letters and digits. Spaces and letter case are ignored.
+func Validate(s string) error {
+	ref := Normalize(s)
+	if len(ref) < 5 || len(ref) > 25 || !strings.HasPrefix(ref, "RF") {
+		return fmt.Errorf("%w: expected RF, two check digits and up to 21 characters", ErrFormat)
+	}
+	for i := 2; i < len(ref); i++ {
+		c := ref[i]
+		if i < 4 && !(c >= '0' && c <= '9') {
+			return fmt.Errorf("%w: check digits must be numeric", ErrFormat)
+		}
+		if !(c >= '0' && c <= '9') && !(c >= 'A' && c <= 'Z') {
+			return fmt.Errorf("%w: %s may only contain letters and digits", ErrFormat, ref)
+		}
+	}
+	if iso13616.Mod97(ref[4:]+ref[:4]) != 1 {
+		return ErrChecksum
+	}
+	return nil
+}
+
+// Generate turns the creditor's own reference of up to 21 letters and
+// digits, such as an invoice number, into an RF reference by prefixing
+// "RF" and the check digits. Spaces and letter case are ignored.
+func Generate(s string) (string, error) {
+	ref := Normalize(s)
+	if len(ref) == 0 || len(ref) > MaxLength {
+		return "", fmt.Errorf("%w: expected 1 to %d characters, got %d", ErrFormat, MaxLength, len(ref))
+	}
+	for i := 0; i < len(ref); i++ {
+		if c := ref[i]; !(c >= '0' && c <= '9') && !(c >= 'A' && c <= 'Z') {
+			return "", fmt.Errorf("%w: %s may only contain letters and digits", ErrFormat, ref)
+		}
+	}
+	return fmt.Sprintf("RF%02d%s", 98-iso13616.Mod97(ref+"RF00"), ref), nil
+}
+
+// candidate is a possible reference in free text, in electronic or print
+// format, possibly followed by other words.
+var candidate = regexp.MustCompile(`\bRF[0-9]{2}[0-9A-Z]*(?: [0-9A-Z]+)*`)
+
+// Find returns the first valid creditor reference in free text such as the
+// remittance information of a bank statement, or "" if there is none. As
+// references may be printed in groups of four, the longest run of words
+// forming a valid reference wins.
+func Find(text string) string {
+	for _, match := range candidate.FindAllString(strings.ToUpper(text), -1) {
+		words := strings.Fields(match)
+		for start := range words {
+			if !candidate.MatchString(words[start]) {
+				continue
+			}
+			for end := len(words); end > start; end-- {
+				if ref := strings.Join(words[start:end], ""); len(ref) <= 25 && IsValid(ref) {
+					return ref
+				}
+			}
+		}
+	}
+	return ""
+}
+
+// IsValid reports whether s is a valid creditor reference.
+func IsValid(s string) bool {
+	return Validate(s) == nil
+}
+
+// Normalize converts s to the electronic format: upper-case without spaces.
+func Normalize(s string) string {
+	return strings.Map(func(r rune) rune {
+		if unicode.IsSpace(r) {
+			return -1
+		}
+		return unicode.ToUpper(r)
+	}, s)
+}
+
+// PrintFormat splits the reference into groups of four characters.
+func PrintFormat(s string) string {
+	ref := Normalize(s)
+	var b strings.Builder
+	for i := 0; i < len(ref); i += 4 {
+		if i > 0 {
+			b.WriteByte(' ')
+		}
+		b.WriteString(ref[i:min(i+4, len(ref))])
+	}
+	return b.String()
+}
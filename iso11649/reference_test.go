@@ -0,0 +1,94 @@
+package iso11649
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestValidate(t *testing.T) {
+	tests := []struct {
+		name    string
+		ref     string
+		wantErr error
+	}{
+		{name: "Valid", ref: "RF18539007547034"},
+		{name: "Print format", ref: "RF18 5390 0754 7034"},
+		{name: "Lower case letters", ref: "rf712348231"},
+		{name: "Wrong check digits", ref: "RF19539007547034", wantErr: ErrChecksum},
+		{name: "Missing prefix", ref: "XX18539007547034", wantErr: ErrFormat},
+		{name: "Too long", ref: "RF180000000000000000000000", wantErr: ErrFormat},
+		{name: "Too short", ref: "RF18", wantErr: ErrFormat},
+		{name: "Letters as check digits", ref: "RFAB539007547034", wantErr: ErrFormat},
+		{name: "Punctuation", ref: "RF18-539007547034", wantErr: ErrFormat},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := Validate(tt.ref)
+			if tt.wantErr == nil && err != nil {
+				t.Errorf("Validate(%q) returned unexpected error: %v", tt.ref, err)
+			}
+			if tt.wantErr != nil && !errors.Is(err, tt.wantErr) {
+				t.Errorf("Validate(%q) = %v, want %v", tt.ref, err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestPrintFormat(t *testing.T) {
+	if got := PrintFormat("rf18539007547034"); got != "RF18 5390 0754 7034" {
+		t.Errorf("PrintFormat() = %s, want RF18 5390 0754 7034", got)
+	}
+}
+
+func TestGenerate(t *testing.T) {
+	tests := []struct {
+		ref     string
+		want    string
+		wantErr error
+	}{
+		{ref: "539007547034", want: "RF18539007547034"},
+		{ref: "2348231", want: "RF712348231"},
+		{ref: "inv 2024 0001", want: "RF17INV20240001"},
+		{ref: "1", want: "RF741"},
+		{ref: "", wantErr: ErrFormat},
+		{ref: "1234567890123456789012", wantErr: ErrFormat},
+		{ref: "INV-1", wantErr: ErrFormat},
+	}
+
+	for _, tt := range tests {
+		got, err := Generate(tt.ref)
+		if tt.wantErr != nil {
+			if !errors.Is(err, tt.wantErr) {
+				t.Errorf("Generate(%q) = %s, %v, want %v", tt.ref, got, err, tt.wantErr)
+			}
+			continue
+		}
+		if err != nil || got != tt.want {
+			t.Errorf("Generate(%q) = %s, %v, want %s", tt.ref, got, err, tt.want)
+		}
+		if !IsValid(got) {
+			t.Errorf("Generate(%q) = %s is not valid", tt.ref, got)
+		}
+	}
+}
+
+func TestFind(t *testing.T) {
+	tests := []struct {
+		text string
+		want string
+	}{
+		{text: "RF18539007547034", want: "RF18539007547034"},
+		{text: "Invoice rf18 5390 0754 7034 Dinner", want: "RF18539007547034"},
+		{text: "EREF+NOTPROVIDED SVWZ+RF17INV20240001 Rent", want: "RF17INV20240001"},
+		{text: "RF19539007547034 RF741", want: "RF741"},
+		{text: "PERF18539007547034", want: ""},
+		{text: "Dinner", want: ""},
+	}
+
+	for _, tt := range tests {
+		if got := Find(tt.text); got != tt.want {
+			t.Errorf("Find(%q) = %q, want %q", tt.text, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,48 @@
+package iso13616
+
+// Parts is an IBAN split into its components. Fields that the country's
+// BBAN layout does not define are left empty.
+type Parts struct {
+	CountryCode         string
+	CheckDigits         string
+	BBAN                string
+	BankCode            string
+	BranchCode          string
+	AccountNumber       string
+	NationalCheckDigits string
+}
+
+// Decompose validates s and splits it into bank code, branch code, account
+// number and national check digits following the country's BBAN layout.
+func Decompose(s string) (Parts, error) {
+	if err := Validate(s); err != nil {
+		return Parts{}, err
+	}
+	code := Normalize(s)
+	country, _ := Lookup(code[:2])
+
+	parts := Parts{
+		CountryCode: code[:2],
+		CheckDigits: code[2:4],
+		BBAN:        code[4:],
+	}
+	pos := 0
+	for _, seg := range parseStructure(country.Layout) {
+		if pos+seg.length > len(parts.BBAN) {
+			break
+		}
+		value := parts.BBAN[pos : pos+seg.length]
+		switch seg.kind {
+		case 'b':
+			parts.BankCode += value
+		case 's':
+			parts.BranchCode += value
+		case 'a':
+			parts.AccountNumber += value
+		case 'k':
+			parts.NationalCheckDigits += value
+		}
+		pos += seg.length
+	}
+	return parts, nil
+}
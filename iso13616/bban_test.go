@@ -0,0 +1,75 @@
+package iso13616
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestDecompose(t *testing.T) {
+	tests := []struct {
+		name string
+		iban string
+		want Parts
+	}{
+		{
+			name: "TR with reserved digit",
+			iban: "TR330006100519786457841326",
+			want: Parts{CountryCode: "TR", CheckDigits: "33", BBAN: "0006100519786457841326", BankCode: "00061", AccountNumber: "0519786457841326"},
+		},
+		{
+			name: "DE bank code and account",
+			iban: "DE89 3704 0044 0532 0130 00",
+			want: Parts{CountryCode: "DE", CheckDigits: "89", BBAN: "370400440532013000", BankCode: "37040044", AccountNumber: "0532013000"},
+		},
+		{
+			name: "GB sort code as branch",
+			iban: "GB82WEST12345698765432",
+			want: Parts{CountryCode: "GB", CheckDigits: "82", BBAN: "WEST12345698765432", BankCode: "WEST", BranchCode: "123456", AccountNumber: "98765432"},
+		},
+		{
+			name: "FR with RIB key",
+			iban: "FR1420041010050500013M02606",
+			want: Parts{CountryCode: "FR", CheckDigits: "14", BBAN: "20041010050500013M02606", BankCode: "20041", BranchCode: "01005", AccountNumber: "0500013M026", NationalCheckDigits: "06"},
+		},
+		{
+			name: "IT with leading CIN",
+			iban: "IT60X0542811101000000123456",
+			want: Parts{CountryCode: "IT", CheckDigits: "60", BBAN: "X0542811101000000123456", BankCode: "05428", BranchCode: "11101", AccountNumber: "000000123456", NationalCheckDigits: "X"},
+		},
+		{
+			name: "ES with two check digits",
+			iban: "ES9121000418450200051332",
+			want: Parts{CountryCode: "ES", CheckDigits: "91", BBAN: "21000418450200051332", BankCode: "2100", BranchCode: "0418", AccountNumber: "0200051332", NationalCheckDigits: "45"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := Decompose(tt.iban)
+			if err != nil {
+				t.Fatalf("Decompose(%q) returned unexpected error: %v", tt.iban, err)
+			}
+			if got != tt.want {
+				t.Errorf("Decompose(%q) = %+v, want %+v", tt.iban, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestDecomposeInvalid(t *testing.T) {
+	if _, err := Decompose("TR340006100519786457841326"); !errors.Is(err, ErrChecksum) {
+		t.Errorf("Decompose() error = %v, want %v", err, ErrChecksum)
+	}
+}
+
+func TestCountryLayoutMatchesStructure(t *testing.T) {
+	for code, c := range countries {
+		total := 0
+		for _, seg := range parseStructure(c.Layout) {
+			total += seg.length
+		}
+		if total != c.Length-4 {
+			t.Errorf("%s: layout %s covers %d characters, want %d", code, c.Layout, total, c.Length-4)
+		}
+	}
+}
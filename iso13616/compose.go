@@ -0,0 +1,111 @@
+package iso13616
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// ErrNationalCheck is returned by Compose for countries whose national
+// check digit algorithm is not implemented.
+var ErrNationalCheck = errors.New("cannot compute national check digits")
+
+// Compose builds the IBAN for national account details. Numeric parts that
+// are shorter than the country's layout are padded with leading zeros, and
+// national check digits are calculated where the BBAN carries them. For
+// layouts with extra fields (account type, reserved digits) the account may
+// include them; otherwise they are zero filled.
+func Compose(countryCode, bankCode, branchCode, account string) (string, error) {
+	cc := Normalize(countryCode)
+	country, ok := Lookup(cc)
+	if !ok {
+		return "", fmt.Errorf("%w: %s", ErrCountry, cc)
+	}
+	bankCode, branchCode, account = Normalize(bankCode), Normalize(branchCode), Normalize(account)
+
+	lengths := map[byte]int{}
+	segs := parseStructure(country.Layout)
+	for _, seg := range segs {
+		lengths[seg.kind] += seg.length
+	}
+
+	bank, err := fill("bank code", bankCode, lengths['b'], cc)
+	if err != nil {
+		return "", err
+	}
+	branch, err := fill("branch code", branchCode, lengths['s'], cc)
+	if err != nil {
+		return "", err
+	}
+	// The account either covers the extra fields as well or only the
+	// account positions, in which case the extra fields become zeros
+	extra := strings.Repeat("0", lengths['x'])
+	withExtra := len(account) == lengths['a']+lengths['x'] && lengths['x'] > 0
+	if !withExtra {
+		if account, err = fill("account number", account, lengths['a'], cc); err != nil {
+			return "", err
+		}
+	}
+
+	var bban strings.Builder
+	for _, seg := range segs {
+		var value string
+		switch seg.kind {
+		case 'b':
+			value, bank = bank[:seg.length], bank[seg.length:]
+		case 's':
+			value, branch = branch[:seg.length], branch[seg.length:]
+		case 'a':
+			value, account = account[:seg.length], account[seg.length:]
+		case 'x':
+			if withExtra {
+				value, account = account[:seg.length], account[seg.length:]
+			} else {
+				value, extra = extra[:seg.length], extra[seg.length:]
+			}
+		case 'k':
+			value = strings.Repeat("0", seg.length)
+		}
+		bban.WriteString(value)
+	}
+
+	result := bban.String()
+	for i := 0; i < len(result); i++ {
+		if !isAlnum(result[i]) {
+			return "", ErrCharacters
+		}
+	}
+	if lengths['k'] > 0 {
+		if result, err = nationalCheck(country, result); err != nil {
+			return "", err
+		}
+	}
+	if err := country.checkBBAN(result); err != nil {
+		return "", err
+	}
+
+	iban := cc + CheckDigits(cc, result) + result
+	if err := Validate(iban); err != nil {
+		return "", err
+	}
+	return iban, nil
+}
+
+// CheckDigits computes the two IBAN check digits for a country and BBAN.
+func CheckDigits(countryCode, bban string) string {
+	return fmt.Sprintf("%02d", 98-Mod97(Normalize(bban)+Normalize(countryCode)+"00"))
+}
+
+// fill left pads value with zeros to length, rejecting values that are too
+// long or given for a part the country does not have.
+func fill(name, value string, length int, cc string) (string, error) {
+	switch {
+	case length == 0 && value != "":
+		return "", fmt.Errorf("IBANs of %s have no %s", cc, name)
+	case length > 0 && value == "":
+		return "", fmt.Errorf("%s is required for %s", name, cc)
+	case len(value) > length:
+		return "", fmt.Errorf("%s for %s must be at most %d characters, got %d", name, cc, length, len(value))
+	}
+	return strings.Repeat("0", length-len(value)) + value, nil
+}
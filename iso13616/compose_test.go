@@ -0,0 +1,93 @@
+package iso13616
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestCompose(t *testing.T) {
+	tests := []struct {
+		name       string
+		country    string
+		bankCode   string
+		branchCode string
+		account    string
+		want       string
+	}{
+		{name: "TR with reserved digit", country: "TR", bankCode: "00061", account: "0519786457841326", want: "TR330006100519786457841326"},
+		{name: "TR bank code without leading zeros", country: "tr", bankCode: "61", account: "0519786457841326", want: "TR330006100519786457841326"},
+		{name: "DE padded account", country: "DE", bankCode: "37040044", account: "532013000", want: "DE89370400440532013000"},
+		{name: "GB with sort code", country: "GB", bankCode: "WEST", branchCode: "12-34-56", account: "98765432", want: "GB82WEST12345698765432"},
+		{name: "NL", country: "NL", bankCode: "ABNA", account: "417164300", want: "NL91ABNA0417164300"},
+		{name: "FR RIB key", country: "FR", bankCode: "20041", branchCode: "01005", account: "0500013M026", want: "FR1420041010050500013M02606"},
+		{name: "MC RIB key", country: "MC", bankCode: "11222", branchCode: "00001", account: "01234567890", want: "MC5811222000010123456789030"},
+		{name: "ES control digits", country: "ES", bankCode: "2100", branchCode: "0418", account: "0200051332", want: "ES9121000418450200051332"},
+		{name: "IT CIN", country: "IT", bankCode: "05428", branchCode: "11101", account: "123456", want: "IT60X0542811101000000123456"},
+		{name: "SM CIN", country: "SM", bankCode: "03225", branchCode: "09800", account: "270100", want: "SM86U0322509800000000270100"},
+		{name: "BE", country: "BE", bankCode: "539", account: "0075470", want: "BE68539007547034"},
+		{name: "NO", country: "NO", bankCode: "8601", account: "111794", want: "NO9386011117947"},
+		{name: "PL", country: "PL", bankCode: "109", branchCode: "0101", account: "0000071219812874", want: "PL61109010140000071219812874"},
+		{name: "PT", country: "PT", bankCode: "0002", branchCode: "0123", account: "12345678901", want: "PT50000201231234567890154"},
+		{name: "SI", country: "SI", bankCode: "26330", account: "00120390", want: "SI56263300012039086"},
+		{name: "HU", country: "HU", bankCode: "117", branchCode: "7301", account: "111110180000000", want: "HU42117730161111101800000000"},
+		{name: "FI", country: "FI", bankCode: "123", account: "4560000078", want: "FI2112345600000785"},
+		{name: "BA", country: "BA", bankCode: "129", branchCode: "007", account: "94010284", want: "BA391290079401028494"},
+		{name: "CH", country: "CH", bankCode: "00762", account: "011623852957", want: "CH9300762011623852957"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := Compose(tt.country, tt.bankCode, tt.branchCode, tt.account)
+			if err != nil {
+				t.Fatalf("Compose() returned unexpected error: %v", err)
+			}
+			if got != tt.want {
+				t.Errorf("Compose() = %s, want %s", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestComposeErrors(t *testing.T) {
+	tests := []struct {
+		name       string
+		country    string
+		bankCode   string
+		branchCode string
+		account    string
+		errorMsg   string
+		errorIs    error
+	}{
+		{name: "Unknown country", country: "XX", bankCode: "1", account: "1", errorMsg: "unknown IBAN country code: XX", errorIs: ErrCountry},
+		{name: "Missing bank code", country: "DE", account: "532013000", errorMsg: "bank code is required for DE"},
+		{name: "Branch code for country without branches", country: "DE", bankCode: "37040044", branchCode: "1", account: "532013000", errorMsg: "IBANs of DE have no branch code"},
+		{name: "Account too long", country: "DE", bankCode: "37040044", account: "12345678901", errorMsg: "account number for DE must be at most 10 characters, got 11"},
+		{name: "Letters in numeric account", country: "DE", bankCode: "37040044", account: "53201300A", errorMsg: "invalid BBAN format for DE: expected 8!n10!n", errorIs: ErrFormat},
+		{name: "Unsupported national check", country: "AL", bankCode: "212", branchCode: "1100", account: "0000000235698741", errorMsg: "cannot compute national check digits for AL", errorIs: ErrNationalCheck},
+		{name: "Invalid characters", country: "DE", bankCode: "37040044", account: "5320/3000", errorMsg: "IBAN may only contain letters and digits", errorIs: ErrCharacters},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := Compose(tt.country, tt.bankCode, tt.branchCode, tt.account)
+			if err == nil {
+				t.Fatal("Expected error but got none")
+			}
+			if err.Error() != tt.errorMsg {
+				t.Errorf("Error() = %s, want %s", err.Error(), tt.errorMsg)
+			}
+			if tt.errorIs != nil && !errors.Is(err, tt.errorIs) {
+				t.Errorf("Expected %v, got %v", tt.errorIs, err)
+			}
+		})
+	}
+}
+
+func TestCheckDigits(t *testing.T) {
+	if got := CheckDigits("TR", "0010009999901234567891"); got != "05" {
+		t.Errorf("CheckDigits() = %s, want 05", got)
+	}
+	if got := CheckDigits("gb", "WEST12345698765432"); got != "82" {
+		t.Errorf("CheckDigits() = %s, want 82", got)
+	}
+}
@@ -0,0 +1,99 @@
+package iso13616
+
+import (
+	"fmt"
+	"strconv"
+)
+
+// Country holds the IBAN rules of a single registry country.
+type Country struct {
+	Code   string `json:"code"`
+	Name   string `json:"name"`
+	Length int    `json:"length"`
+	// BBAN is the SWIFT registry structure of the basic bank account number,
+	// e.g. "5!n1!n16!c" (n = digits, a = upper-case letters, c = alphanumeric).
+	BBAN string `json:"bban"`
+	// Layout assigns a role to every BBAN position in the same notation,
+	// e.g. "5b1x16a": b = bank code, s = branch code, a = account number,
+	// k = national check digits, x = other (reserved, account type, ...).
+	Layout string `json:"layout"`
+	// SEPA reports whether the country takes part in the Single Euro
+	// Payments Area schemes.
+	SEPA bool `json:"sepa"`
+}
+
+// Lookup returns the IBAN rules for the given country code.
+func Lookup(code string) (Country, bool) {
+	mu.RLock()
+	defer mu.RUnlock()
+	c, ok := countries[code]
+	return c, ok
+}
+
+// IsSEPA reports whether the country of the IBAN s takes part in SEPA.
+func IsSEPA(s string) bool {
+	c, ok := Lookup(CountryCode(s))
+	return ok && c.SEPA
+}
+
+// checkBBAN matches bban against the registry structure of the country.
+func (c Country) checkBBAN(bban string) error {
+	pos := 0
+	for _, seg := range parseStructure(c.BBAN) {
+		if pos+seg.length > len(bban) {
+			return fmt.Errorf("%w for %s: expected %s", ErrFormat, c.Code, c.BBAN)
+		}
+		for _, ch := range []byte(bban[pos : pos+seg.length]) {
+			if !seg.accepts(ch) {
+				return fmt.Errorf("%w for %s: expected %s", ErrFormat, c.Code, c.BBAN)
+			}
+		}
+		pos += seg.length
+	}
+	if pos != len(bban) {
+		return fmt.Errorf("%w for %s: expected %s", ErrFormat, c.Code, c.BBAN)
+	}
+	return nil
+}
+
+// segment is one "<length>!<type>" element of a registry structure.
+type segment struct {
+	length int
+	kind   byte
+}
+
+func (s segment) accepts(ch byte) bool {
+	switch s.kind {
+	case 'n':
+		return isDigit(ch)
+	case 'a':
+		return isAlpha(ch)
+	default:
+		return isAlnum(ch)
+	}
+}
+
+// parseStructure splits a registry structure such as "4!a6!n8!n" into its
+// segments. Malformed input yields the segments parsed so far.
+func parseStructure(structure string) []segment {
+	var segs []segment
+	for i := 0; i < len(structure); {
+		j := i
+		for j < len(structure) && isDigit(structure[j]) {
+			j++
+		}
+		n, err := strconv.Atoi(structure[i:j])
+		if err != nil {
+			return segs
+		}
+		if j < len(structure) && structure[j] == '!' {
+			j++
+		}
+		if j >= len(structure) {
+			return segs
+		}
+		segs = append(segs, segment{length: n, kind: structure[j]})
+		i = j + 1
+	}
+	return segs
+}
@@ -0,0 +1,128 @@
+// Package iso13616 validates International Bank Account Numbers as defined by
+// ISO 13616 and the SWIFT IBAN registry.
+package iso13616
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"unicode"
+)
+
+// Validation errors. Validate wraps them with the offending detail, so use
+// errors.Is to tell them apart.
+var (
+	ErrEmpty       = errors.New("you have to provide IBAN")
+	ErrCharacters  = errors.New("IBAN may only contain letters and digits")
+	ErrCountry     = errors.New("unknown IBAN country code")
+	ErrLength      = errors.New("invalid IBAN length")
+	ErrFormat      = errors.New("invalid BBAN format")
+	ErrCheckDigits = errors.New("invalid IBAN check digits")
+	ErrChecksum    = errors.New("IBAN checksum does not match")
+)
+
+// Validate checks the country code, length, BBAN structure and mod-97
+// checksum of s. It validates the Normalize form of s, so spaces and
+// separators are ignored and letters may be in any case.
+func Validate(s string) error {
+	code := Normalize(s)
+	if code == "" {
+		return ErrEmpty
+	}
+	for i := 0; i < len(code); i++ {
+		if !isAlnum(code[i]) {
+			return ErrCharacters
+		}
+	}
+	if len(code) < 4 {
+		return fmt.Errorf("%w: got %d characters", ErrLength, len(code))
+	}
+
+	cc := code[:2]
+	country, ok := Lookup(cc)
+	if !ok {
+		return fmt.Errorf("%w: %s", ErrCountry, cc)
+	}
+	if len(code) != country.Length {
+		return fmt.Errorf("%w for %s: expected %d characters, got %d", ErrLength, cc, country.Length, len(code))
+	}
+	if !isDigit(code[2]) || !isDigit(code[3]) || code[2:4] == "00" || code[2:4] == "01" || code[2:4] == "99" {
+		return fmt.Errorf("%w: %s", ErrCheckDigits, code[2:4])
+	}
+	if err := country.checkBBAN(code[4:]); err != nil {
+		return err
+	}
+	if Mod97(code[4:]+code[:4]) != 1 {
+		return ErrChecksum
+	}
+	return nil
+}
+
+// IsValid reports whether s is a valid IBAN.
+func IsValid(s string) bool {
+	return Validate(s) == nil
+}
+
+// Normalize converts s to the electronic format: upper-case without spaces
+// or the hyphen and dot separators people tend to type.
+func Normalize(s string) string {
+	return strings.Map(func(r rune) rune {
+		if unicode.IsSpace(r) || r == '-' || r == '.' {
+			return -1
+		}
+		return unicode.ToUpper(r)
+	}, s)
+}
+
+// PrintFormat renders s in the paper format: the electronic format split
+// into groups of four characters separated by a space.
+func PrintFormat(s string) string {
+	code := Normalize(s)
+	var b strings.Builder
+	for i := 0; i < len(code); i += 4 {
+		if i > 0 {
+			b.WriteByte(' ')
+		}
+		b.WriteString(code[i:min(i+4, len(code))])
+	}
+	return b.String()
+}
+
+// CountryCode returns the upper-cased country prefix of s, or an empty string
+// if s is too short to carry one.
+func CountryCode(s string) string {
+	code := Normalize(s)
+	if len(code) < 2 {
+		return ""
+	}
+	return code[:2]
+}
+
+// Mod97 computes the ISO 7064 MOD 97-10 remainder of s, expanding letters
+// to two digit numbers (A = 10 ... Z = 35). Creditor references (ISO 11649)
+// use the same scheme.
+func Mod97(s string) int {
+	rem := 0
+	for i := 0; i < len(s); i++ {
+		c := s[i]
+		switch {
+		case isDigit(c):
+			rem = (rem*10 + int(c-'0')) % 97
+		case c >= 'A' && c <= 'Z':
+			rem = (rem*100 + int(c-'A'+10)) % 97
+		}
+	}
+	return rem
+}
+
+func isDigit(c byte) bool {
+	return c >= '0' && c <= '9'
+}
+
+func isAlpha(c byte) bool {
+	return c >= 'A' && c <= 'Z'
+}
+
+func isAlnum(c byte) bool {
+	return isDigit(c) || isAlpha(c)
+}
@@ -0,0 +1,163 @@
+package iso13616
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestValidate(t *testing.T) {
+	tests := []struct {
+		name        string
+		iban        string
+		expectError error
+		errorMsg    string
+	}{
+		{name: "Valid TR IBAN", iban: "TR330006100519786457841326"},
+		{name: "Valid DE IBAN", iban: "DE89370400440532013000"},
+		{name: "Valid GB IBAN", iban: "GB82WEST12345698765432"},
+		{name: "Valid NL IBAN", iban: "NL91ABNA0417164300"},
+		{name: "Valid FR IBAN", iban: "FR1420041010050500013M02606"},
+		{name: "Valid CH IBAN", iban: "CH9300762011623852957"},
+		{name: "Valid BE IBAN", iban: "BE68539007547034"},
+		{name: "Valid NO IBAN", iban: "NO9386011117947"},
+		{name: "Valid IT IBAN", iban: "IT60X0542811101000000123456"},
+		{name: "Valid MT IBAN", iban: "MT84MALT011000012345MTLCAST001S"},
+		{name: "Print format with spaces", iban: "TR33 0006 1005 1978 6457 8413 26"},
+		{name: "Lower case", iban: "gb82west12345698765432"},
+		{
+			name:        "Empty",
+			iban:        "   ",
+			expectError: ErrEmpty,
+			errorMsg:    "you have to provide IBAN",
+		},
+		{name: "Hyphen separators", iban: "TR33-0006-1005-1978-6457-8413-26"},
+		{
+			name:        "Invalid characters",
+			iban:        "TR33/0006/1005/1978/6457/8413/26",
+			expectError: ErrCharacters,
+			errorMsg:    "IBAN may only contain letters and digits",
+		},
+		{
+			name:        "Unknown country",
+			iban:        "XX330006100519786457841326",
+			expectError: ErrCountry,
+			errorMsg:    "unknown IBAN country code: XX",
+		},
+		{
+			name:        "Wrong length for TR",
+			iban:        "TR3300061005197864578413",
+			expectError: ErrLength,
+			errorMsg:    "invalid IBAN length for TR: expected 26 characters, got 24",
+		},
+		{
+			name:        "Too short",
+			iban:        "TR",
+			expectError: ErrLength,
+			errorMsg:    "invalid IBAN length: got 2 characters",
+		},
+		{
+			name:        "Letters in check digits",
+			iban:        "TRAB0006100519786457841326",
+			expectError: ErrCheckDigits,
+			errorMsg:    "invalid IBAN check digits: AB",
+		},
+		{
+			name:        "Letters in numeric BBAN part",
+			iban:        "DE89370400A40532013000",
+			expectError: ErrFormat,
+			errorMsg:    "invalid BBAN format for DE: expected 8!n10!n",
+		},
+		{
+			name:        "Bad check digit",
+			iban:        "TR340006100519786457841326",
+			expectError: ErrChecksum,
+			errorMsg:    "IBAN checksum does not match",
+		},
+		{
+			name:        "Single typo",
+			iban:        "DE89370400440532013001",
+			expectError: ErrChecksum,
+			errorMsg:    "IBAN checksum does not match",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := Validate(tt.iban)
+			if tt.expectError == nil {
+				if err != nil {
+					t.Errorf("Validate(%q) returned unexpected error: %v", tt.iban, err)
+				}
+				return
+			}
+			if !errors.Is(err, tt.expectError) {
+				t.Fatalf("Validate(%q) = %v, want %v", tt.iban, err, tt.expectError)
+			}
+			if err.Error() != tt.errorMsg {
+				t.Errorf("Error() = %s, want %s", err.Error(), tt.errorMsg)
+			}
+		})
+	}
+}
+
+func TestCountryStructureMatchesLength(t *testing.T) {
+	for code, c := range countries {
+		if c.Code != code {
+			t.Errorf("%s: Code = %s", code, c.Code)
+		}
+		total := 4
+		for _, seg := range parseStructure(c.BBAN) {
+			total += seg.length
+		}
+		if total != c.Length {
+			t.Errorf("%s: BBAN %s adds up to %d, want %d", code, c.BBAN, total, c.Length)
+		}
+	}
+}
+
+func TestCountryCode(t *testing.T) {
+	if got := CountryCode(" tr33 0006"); got != "TR" {
+		t.Errorf("CountryCode() = %s, want TR", got)
+	}
+	if got := CountryCode("t"); got != "" {
+		t.Errorf("CountryCode() = %s, want empty", got)
+	}
+}
+
+func TestNormalize(t *testing.T) {
+	tests := []struct {
+		in   string
+		want string
+	}{
+		{in: "TR330006100519786457841326", want: "TR330006100519786457841326"},
+		{in: "tr33 0006 1005 1978 6457 8413 26", want: "TR330006100519786457841326"},
+		{in: " gb82-west-1234-5698-7654-32 ", want: "GB82WEST12345698765432"},
+		{in: "DE89.3704.0044.0532.0130.00", want: "DE89370400440532013000"},
+		{in: "NO93\t8601\u00a01117947", want: "NO9386011117947"},
+	}
+
+	for _, tt := range tests {
+		if got := Normalize(tt.in); got != tt.want {
+			t.Errorf("Normalize(%q) = %s, want %s", tt.in, got, tt.want)
+		}
+	}
+}
+
+func TestPrintFormat(t *testing.T) {
+	tests := []struct {
+		in   string
+		want string
+	}{
+		{in: "TR330006100519786457841326", want: "TR33 0006 1005 1978 6457 8413 26"},
+		{in: "gb82west12345698765432", want: "GB82 WEST 1234 5698 7654 32"},
+		{in: "NL91ABNA0417164300", want: "NL91 ABNA 0417 1643 00"},
+		{in: "BE68 5390 0754 7034", want: "BE68 5390 0754 7034"},
+		{in: "", want: ""},
+	}
+
+	for _, tt := range tests {
+		if got := PrintFormat(tt.in); got != tt.want {
+			t.Errorf("PrintFormat(%q) = %q, want %q", tt.in, got, tt.want)
+		}
+	}
+}
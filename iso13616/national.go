@@ -0,0 +1,201 @@
+package iso13616
+
+import (
+	"fmt"
+	"strings"
+)
+
+// nationalCheckFunc computes the national check digits from the bank,
+// branch and account parts of a BBAN.
+type nationalCheckFunc func(bank, branch, account string) (string, error)
+
+// nationalChecks holds the national check digit algorithms by country.
+var nationalChecks = map[string]nationalCheckFunc{
+	"BA": mod97Check,
+	"BE": belgianCheck,
+	"ES": spanishCheck,
+	"FI": finnishCheck,
+	"FR": ribKey,
+	"HU": hungarianCheck,
+	"IT": italianCIN,
+	"MC": ribKey,
+	"ME": mod97Check,
+	"MK": mod97Check,
+	"NO": norwegianCheck,
+	"PL": polishCheck,
+	"PT": mod97Check,
+	"RS": mod97Check,
+	"SI": mod97Check,
+	"SM": italianCIN,
+	"TL": mod97Check,
+}
+
+// nationalCheck fills the national check digit positions of bban.
+func nationalCheck(country Country, bban string) (string, error) {
+	check, ok := nationalChecks[country.Code]
+	if !ok {
+		return "", fmt.Errorf("%w for %s", ErrNationalCheck, country.Code)
+	}
+
+	var bank, branch, account strings.Builder
+	pos := 0
+	for _, seg := range parseStructure(country.Layout) {
+		value := bban[pos : pos+seg.length]
+		switch seg.kind {
+		case 'b':
+			bank.WriteString(value)
+		case 's':
+			branch.WriteString(value)
+		case 'a':
+			account.WriteString(value)
+		}
+		pos += seg.length
+	}
+	digits, err := check(bank.String(), branch.String(), account.String())
+	if err != nil {
+		return "", err
+	}
+
+	var out strings.Builder
+	pos = 0
+	for _, seg := range parseStructure(country.Layout) {
+		if seg.kind == 'k' {
+			out.WriteString(digits[:seg.length])
+			digits = digits[seg.length:]
+		} else {
+			out.WriteString(bban[pos : pos+seg.length])
+		}
+		pos += seg.length
+	}
+	return out.String(), nil
+}
+
+// mod97Check is the ISO 7064 MOD 97-10 scheme used by PT and the former
+// Yugoslav countries.
+func mod97Check(bank, branch, account string) (string, error) {
+	return fmt.Sprintf("%02d", 98-Mod97(bank+branch+account+"00")), nil
+}
+
+// belgianCheck is the remainder of the first ten digits divided by 97.
+func belgianCheck(bank, _, account string) (string, error) {
+	rem := Mod97(bank + account)
+	if rem == 0 {
+		rem = 97
+	}
+	return fmt.Sprintf("%02d", rem), nil
+}
+
+// ribKey is the French "clé RIB"; letters in the account are replaced by
+// digits first.
+func ribKey(bank, branch, account string) (string, error) {
+	converted := strings.Map(func(r rune) rune {
+		if r >= 'A' && r <= 'Z' {
+			return rune("12345678912345678923456789"[r-'A'])
+		}
+		return r
+	}, account)
+	sum := 89*Mod97(bank) + 15*Mod97(branch) + 3*Mod97(converted)
+	return fmt.Sprintf("%02d", 97-sum%97), nil
+}
+
+// spanishCheck computes the two "dígitos de control": one over bank and
+// branch, one over the account.
+func spanishCheck(bank, branch, account string) (string, error) {
+	weights := []int{1, 2, 4, 8, 5, 10, 9, 7, 3, 6}
+	digit := func(s string) int {
+		sum := 0
+		for i := 0; i < len(s); i++ {
+			sum += int(s[i]-'0') * weights[i]
+		}
+		d := 11 - sum%11
+		switch d {
+		case 11:
+			return 0
+		case 10:
+			return 1
+		}
+		return d
+	}
+	return fmt.Sprintf("%d%d", digit("00"+bank+branch), digit(account)), nil
+}
+
+// italianCIN computes the check letter used by IT and SM.
+func italianCIN(bank, branch, account string) (string, error) {
+	odd := []int{1, 0, 5, 7, 9, 13, 15, 17, 19, 21, 2, 4, 18, 20, 11, 3, 6, 8, 12, 14, 16, 10, 22, 25, 24, 23}
+	value := func(c byte) int {
+		if isDigit(c) {
+			return int(c - '0')
+		}
+		return int(c - 'A')
+	}
+	s := bank + branch + account
+	sum := 0
+	for i := 0; i < len(s); i++ {
+		if i%2 == 0 {
+			sum += odd[value(s[i])]
+		} else {
+			sum += value(s[i])
+		}
+	}
+	return string(rune('A' + sum%26)), nil
+}
+
+// norwegianCheck is the MOD 11 digit over bank and account.
+func norwegianCheck(bank, _, account string) (string, error) {
+	weights := []int{5, 4, 3, 2, 7, 6, 5, 4, 3, 2}
+	s := bank + account
+	sum := 0
+	for i := 0; i < len(s); i++ {
+		sum += int(s[i]-'0') * weights[i]
+	}
+	d := 11 - sum%11
+	switch d {
+	case 11:
+		return "0", nil
+	case 10:
+		return "", fmt.Errorf("%w: account number %s is not valid for NO", ErrNationalCheck, account)
+	}
+	return fmt.Sprintf("%d", d), nil
+}
+
+// polishCheck is the check digit of the eight digit bank and branch number.
+func polishCheck(bank, branch, _ string) (string, error) {
+	weights := []int{3, 9, 7, 1, 3, 9, 7}
+	s := bank + branch
+	sum := 0
+	for i := 0; i < len(s); i++ {
+		sum += int(s[i]-'0') * weights[i]
+	}
+	return fmt.Sprintf("%d", (10-sum%10)%10), nil
+}
+
+// hungarianCheck computes the 9-7-3-1 weighted digits over bank and branch
+// and over the account.
+func hungarianCheck(bank, branch, account string) (string, error) {
+	digit := func(s string) int {
+		weights := []int{9, 7, 3, 1}
+		sum := 0
+		for i := 0; i < len(s); i++ {
+			sum += int(s[i]-'0') * weights[i%4]
+		}
+		return (10 - sum%10) % 10
+	}
+	return fmt.Sprintf("%d%d", digit(bank+branch), digit(account)), nil
+}
+
+// finnishCheck is the Luhn digit over bank and account.
+func finnishCheck(bank, _, account string) (string, error) {
+	s := bank + account
+	sum := 0
+	for i := 0; i < len(s); i++ {
+		d := int(s[len(s)-1-i] - '0')
+		if i%2 == 0 {
+			d *= 2
+			if d > 9 {
+				d -= 9
+			}
+		}
+		sum += d
+	}
+	return fmt.Sprintf("%d", (10-sum%10)%10), nil
+}
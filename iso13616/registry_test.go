@@ -0,0 +1,117 @@
+package iso13616
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestEmbeddedRegistry(t *testing.T) {
+	if RegistryVersion() == "" {
+		t.Error("RegistryVersion() should not be empty")
+	}
+	list := Countries()
+	if len(list) < 80 {
+		t.Errorf("Expected the full registry, got %d countries", len(list))
+	}
+	for i := 1; i < len(list); i++ {
+		if list[i-1].Code >= list[i].Code {
+			t.Fatalf("Countries() not sorted at %s", list[i].Code)
+		}
+	}
+}
+
+func TestIsSEPA(t *testing.T) {
+	tests := []struct {
+		iban string
+		want bool
+	}{
+		{iban: "DE89370400440532013000", want: true},
+		{iban: "CH9300762011623852957", want: true},
+		{iban: "GB82WEST12345698765432", want: true},
+		{iban: "TR330006100519786457841326", want: false},
+		{iban: "XX00", want: false},
+	}
+
+	for _, tt := range tests {
+		if got := IsSEPA(tt.iban); got != tt.want {
+			t.Errorf("IsSEPA(%s) = %v, want %v", tt.iban, got, tt.want)
+		}
+	}
+}
+
+func TestParseRegistry(t *testing.T) {
+	tests := []struct {
+		name        string
+		data        string
+		expectError bool
+	}{
+		{
+			name: "Valid rules",
+			data: `{"version":"test","countries":[{"code":"DE","length":22,"bban":"8!n10!n","layout":"8b10a","sepa":true}]}`,
+		},
+		{
+			name:        "No countries",
+			data:        `{"version":"test","countries":[]}`,
+			expectError: true,
+		},
+		{
+			name:        "Length does not match structure",
+			data:        `{"version":"test","countries":[{"code":"DE","length":24,"bban":"8!n10!n"}]}`,
+			expectError: true,
+		},
+		{
+			name:        "Layout does not match structure",
+			data:        `{"version":"test","countries":[{"code":"DE","length":22,"bban":"8!n10!n","layout":"8b8a"}]}`,
+			expectError: true,
+		},
+		{
+			name:        "Invalid country code",
+			data:        `{"version":"test","countries":[{"code":"D1","length":22,"bban":"8!n10!n"}]}`,
+			expectError: true,
+		},
+		{
+			name:        "Malformed JSON",
+			data:        `{"version":`,
+			expectError: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := ParseRegistry([]byte(tt.data))
+			if tt.expectError && err == nil {
+				t.Error("Expected error but got none")
+			}
+			if !tt.expectError && err != nil {
+				t.Errorf("Unexpected error: %v", err)
+			}
+		})
+	}
+}
+
+func TestLoadRegistry(t *testing.T) {
+	embedded, _ := ParseRegistry(embeddedRegistry)
+	defer Use(embedded)
+
+	// A registry where DE accounts became one digit longer
+	path := filepath.Join(t.TempDir(), "registry.json")
+	data := `{"version":"2099-01-01","countries":[{"code":"DE","length":23,"bban":"8!n11!n","layout":"8b11a","sepa":true}]}`
+	if err := os.WriteFile(path, []byte(data), 0o600); err != nil {
+		t.Fatalf("Failed to write registry: %v", err)
+	}
+	if err := LoadRegistry(path); err != nil {
+		t.Fatalf("LoadRegistry() returned unexpected error: %v", err)
+	}
+
+	if RegistryVersion() != "2099-01-01" {
+		t.Errorf("RegistryVersion() = %s, want 2099-01-01", RegistryVersion())
+	}
+	if err := Validate("DE89370400440532013000"); !errors.Is(err, ErrLength) {
+		t.Errorf("Validate() = %v, want %v", err, ErrLength)
+	}
+	if err := Validate("TR330006100519786457841326"); !errors.Is(err, ErrCountry) {
+		t.Errorf("Validate() = %v, want %v", err, ErrCountry)
+	}
+}
@@ -0,0 +1,107 @@
+package iso13616
+
+import (
+	"errors"
+	"fmt"
+)
+
+// Suggestion is a valid IBAN that differs from a rejected one by a likely
+// typing or OCR mistake.
+type Suggestion struct {
+	IBAN   string
+	Reason string
+}
+
+// MaxSuggestions limits the number of candidates Suggest returns.
+const MaxSuggestions = 5
+
+// lookalikes are the characters OCR and people tend to mix up.
+var lookalikes = map[byte]byte{'O': '0', '0': 'O', 'I': '1', '1': 'I'}
+
+// Suggest looks for valid IBANs close to s when s has the right country and
+// length but fails the format or checksum tests. Candidates are ranked by
+// likelihood: look-alike characters first, then two swapped neighbours, then
+// a single wrong character. It returns nil when s is valid or too far off to
+// guess.
+func Suggest(s string) []Suggestion {
+	err := Validate(s)
+	if err == nil || !(errors.Is(err, ErrChecksum) || errors.Is(err, ErrFormat) || errors.Is(err, ErrCheckDigits)) {
+		return nil
+	}
+	code := []byte(Normalize(s))
+	kinds := positions(code[:2])
+
+	var out []Suggestion
+	seen := map[string]bool{}
+	add := func(candidate []byte, reason string) {
+		iban := string(candidate)
+		if len(out) < MaxSuggestions && !seen[iban] && IsValid(iban) {
+			seen[iban] = true
+			out = append(out, Suggestion{IBAN: iban, Reason: reason})
+		}
+	}
+
+	// Look-alikes in places where the registry does not allow them, fixed
+	// all at once, as OCR usually repeats the same mistake
+	fixed := append([]byte(nil), code...)
+	for i := 2; i < len(fixed); i++ {
+		if alt, ok := lookalikes[fixed[i]]; ok && !accepts(kinds[i], fixed[i]) {
+			fixed[i] = alt
+		}
+	}
+	add(fixed, "look-alike characters")
+
+	// Single letters read for digits; a digit turned into a letter is
+	// rather unlikely and left to the substitutions below
+	for i := 2; i < len(code); i++ {
+		if alt, ok := lookalikes[code[i]]; ok && isAlpha(code[i]) {
+			candidate := append([]byte(nil), code...)
+			candidate[i] = alt
+			add(candidate, fmt.Sprintf("%c read as %c at position %d", alt, code[i], i+1))
+		}
+	}
+
+	for i := 2; i+1 < len(code); i++ {
+		if code[i] == code[i+1] {
+			continue
+		}
+		candidate := append([]byte(nil), code...)
+		candidate[i], candidate[i+1] = candidate[i+1], candidate[i]
+		add(candidate, fmt.Sprintf("swapped characters at positions %d and %d", i+1, i+2))
+	}
+
+	// A digit mistyped as another digit is more likely than a letter in an
+	// alphanumeric account, so those come first
+	const alphabet = "0123456789ABCDEFGHIJKLMNOPQRSTUVWXYZ"
+	for _, sameClass := range []bool{true, false} {
+		for i := 2; i < len(code); i++ {
+			for j := 0; j < len(alphabet); j++ {
+				ch := alphabet[j]
+				if ch == code[i] || !accepts(kinds[i], ch) || (isDigit(ch) == isDigit(code[i])) != sameClass {
+					continue
+				}
+				candidate := append([]byte(nil), code...)
+				candidate[i] = ch
+				add(candidate, fmt.Sprintf("wrong character at position %d", i+1))
+			}
+		}
+	}
+	return out
+}
+
+// positions returns the registry character type (n, a or c) of every IBAN
+// position for the country.
+func positions(cc []byte) []byte {
+	kinds := []byte{'a', 'a', 'n', 'n'}
+	country, _ := Lookup(string(cc))
+	for _, seg := range parseStructure(country.BBAN) {
+		for i := 0; i < seg.length; i++ {
+			kinds = append(kinds, seg.kind)
+		}
+	}
+	return kinds
+}
+
+func accepts(kind, ch byte) bool {
+	return segment{kind: kind}.accepts(ch)
+}
@@ -0,0 +1,84 @@
+package iso13616
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestSuggest(t *testing.T) {
+	tests := []struct {
+		name   string
+		iban   string
+		want   string
+		reason string
+	}{
+		{
+			name:   "OCR letters instead of digits",
+			iban:   "DE89 37O4 OO44 O532 O13O OO",
+			want:   "DE89370400440532013000",
+			reason: "look-alike characters",
+		},
+		{
+			name:   "Single look-alike in an alphanumeric BBAN",
+			iban:   "GB82WEST1234569876543I",
+			want:   "GB82WEST12345698765432",
+			reason: "wrong character at position 22",
+		},
+		{
+			name:   "Swapped neighbours",
+			iban:   "DE89374000440532013000",
+			want:   "DE89370400440532013000",
+			reason: "swapped characters at positions 7 and 8",
+		},
+		{
+			name:   "Wrong check digit",
+			iban:   "DE88370400440532013000",
+			want:   "DE89370400440532013000",
+			reason: "wrong character at position 4",
+		},
+		{
+			name:   "Wrong account digit",
+			iban:   "TR330006100519786457841327",
+			want:   "TR330006100519786457841326",
+			reason: "wrong character at position 26",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := Suggest(tt.iban)
+			if len(got) == 0 || len(got) > MaxSuggestions {
+				t.Fatalf("Suggest(%s) returned %d suggestions", tt.iban, len(got))
+			}
+			for _, s := range got {
+				if !IsValid(s.IBAN) {
+					t.Errorf("Suggest(%s) returned invalid IBAN %s", tt.iban, s.IBAN)
+				}
+				if s.IBAN == tt.want {
+					if s.Reason != tt.reason {
+						t.Errorf("Reason = %q, want %q", s.Reason, tt.reason)
+					}
+					return
+				}
+			}
+			t.Errorf("Suggest(%s) = %+v, want %s among them", tt.iban, got, tt.want)
+		})
+	}
+}
+
+func TestSuggestRanking(t *testing.T) {
+	// Both a swap and single substitutions fix this one, the swap is the
+	// more likely mistake
+	got := Suggest("DE89374000440532013000")
+	if len(got) == 0 || !strings.HasPrefix(got[0].Reason, "swapped") {
+		t.Errorf("Suggest() = %+v, want the swap first", got)
+	}
+}
+
+func TestSuggestNothingToFix(t *testing.T) {
+	for _, iban := range []string{"DE89370400440532013000", "DE8937040044053201300", "XX89370400440532013000", ""} {
+		if got := Suggest(iban); got != nil {
+			t.Errorf("Suggest(%q) = %+v, want nil", iban, got)
+		}
+	}
+}
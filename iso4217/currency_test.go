@@ -0,0 +1,59 @@
+package iso4217
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestValidate(t *testing.T) {
+	tests := []struct {
+		name     string
+		currency string
+		wantErr  error
+	}{
+		{name: "Euro", currency: "EUR"},
+		{name: "Lower case and spaces", currency: " try "},
+		{name: "No minor units", currency: "JPY"},
+		{name: "Empty", currency: "", wantErr: ErrFormat},
+		{name: "Too long", currency: "EURO", wantErr: ErrFormat},
+		{name: "Digits", currency: "978", wantErr: ErrFormat},
+		{name: "Unknown", currency: "ABC", wantErr: ErrUnknown},
+		{name: "Withdrawn", currency: "DEM", wantErr: ErrUnknown},
+		{name: "Precious metal", currency: "XAU", wantErr: ErrUnknown},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := Validate(tt.currency)
+			if tt.wantErr == nil && err != nil {
+				t.Errorf("Validate(%q) returned unexpected error: %v", tt.currency, err)
+			}
+			if tt.wantErr != nil && !errors.Is(err, tt.wantErr) {
+				t.Errorf("Validate(%q) = %v, want %v", tt.currency, err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestLookup(t *testing.T) {
+	tests := []struct {
+		currency   string
+		wantName   string
+		wantDigits int
+		wantOK     bool
+	}{
+		{currency: "eur", wantName: "Euro", wantDigits: 2, wantOK: true},
+		{currency: "KWD", wantName: "Kuwaiti Dinar", wantDigits: 3, wantOK: true},
+		{currency: "JPY", wantName: "Yen", wantDigits: 0, wantOK: true},
+		{currency: "XYZ"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.currency, func(t *testing.T) {
+			c, ok := Lookup(tt.currency)
+			if ok != tt.wantOK || c.Name != tt.wantName || c.Digits != tt.wantDigits {
+				t.Errorf("Lookup(%q) = %+v, %v, want %s with %d digits", tt.currency, c, ok, tt.wantName, tt.wantDigits)
+			}
+		})
+	}
+}
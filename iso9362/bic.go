@@ -0,0 +1,97 @@
+// Package iso9362 validates Business Identifier Codes (BIC, also known as
+// SWIFT codes) as defined by ISO 9362.
+package iso9362
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"unicode"
+)
+
+// Validation errors. Validate and CheckCountry wrap them with the offending
+// detail, so use errors.Is to tell them apart.
+var (
+	ErrLength  = errors.New("BIC must be 8 or 11 characters")
+	ErrFormat  = errors.New("invalid BIC format")
+	ErrCountry = errors.New("BIC country does not match")
+)
+
+// territories lists the countries whose banks may carry the IBANs of
+// another country, e.g. banks in Guernsey use GB IBANs.
+var territories = map[string][]string{
+	"FR": {"GF", "GP", "MQ", "RE", "PM", "YT", "BL", "MF", "NC", "PF", "WF", "MC"},
+	"GB": {"GG", "IM", "JE"},
+	"FI": {"AX"},
+	"DK": {"FO", "GL"},
+}
+
+// Validate checks s against the ISO 9362 structure: a four character
+// business party prefix, the ISO 3166 country code, a two character
+// location and an optional three character branch. Spaces and letter case
+// are ignored.
+func Validate(s string) error {
+	code := Normalize(s)
+	if len(code) != 8 && len(code) != 11 {
+		return fmt.Errorf("%w, got %d", ErrLength, len(code))
+	}
+	for i := 0; i < len(code); i++ {
+		c := code[i]
+		alpha := c >= 'A' && c <= 'Z'
+		if i >= 4 && i < 6 && !alpha {
+			return fmt.Errorf("%w: %s has no country code", ErrFormat, code)
+		}
+		if !alpha && !(c >= '0' && c <= '9') {
+			return fmt.Errorf("%w: %s may only contain letters and digits", ErrFormat, code)
+		}
+	}
+	return nil
+}
+
+// IsValid reports whether s is a valid BIC.
+func IsValid(s string) bool {
+	return Validate(s) == nil
+}
+
+// Normalize converts s to upper-case without spaces.
+func Normalize(s string) string {
+	return strings.Map(func(r rune) rune {
+		if unicode.IsSpace(r) {
+			return -1
+		}
+		return unicode.ToUpper(r)
+	}, s)
+}
+
+// CountryCode returns the country of the BIC, or an empty string if s is too
+// short to carry one.
+func CountryCode(s string) string {
+	code := Normalize(s)
+	if len(code) < 6 {
+		return ""
+	}
+	return code[4:6]
+}
+
+// CheckCountry verifies that a bank in the BIC's country may hold accounts
+// with IBANs of ibanCountry.
+func CheckCountry(bic, ibanCountry string) error {
+	country := CountryCode(bic)
+	if country == ibanCountry {
+		return nil
+	}
+	for _, territory := range territories[ibanCountry] {
+		if country == territory {
+			return nil
+		}
+	}
+	return fmt.Errorf("%w: BIC is from %s, IBAN from %s", ErrCountry, country, ibanCountry)
+}
+
+// SameInstitution reports whether a and b identify the same institution and
+// location, regardless of branch: COBADEFF matches COBADEFFXXX and
+// COBADEFF370.
+func SameInstitution(a, b string) bool {
+	a, b = Normalize(a), Normalize(b)
+	return len(a) >= 8 && len(b) >= 8 && a[:8] == b[:8]
+}
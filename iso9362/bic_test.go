@@ -0,0 +1,78 @@
+package iso9362
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestValidate(t *testing.T) {
+	tests := []struct {
+		name    string
+		bic     string
+		wantErr error
+	}{
+		{name: "Eight characters", bic: "COBADEFF"},
+		{name: "With branch", bic: "COBADEFFXXX"},
+		{name: "Lower case and spaces", bic: "deut de ff 500"},
+		{name: "Digits in prefix", bic: "1234DEFF"},
+		{name: "Empty", bic: "", wantErr: ErrLength},
+		{name: "Too long", bic: "COBADEFFXXXX", wantErr: ErrLength},
+		{name: "Nine characters", bic: "COBADEFFX", wantErr: ErrLength},
+		{name: "Digits in country", bic: "COBA12FF", wantErr: ErrFormat},
+		{name: "Punctuation", bic: "COBADEF-", wantErr: ErrFormat},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := Validate(tt.bic)
+			if tt.wantErr == nil && err != nil {
+				t.Errorf("Validate(%q) returned unexpected error: %v", tt.bic, err)
+			}
+			if tt.wantErr != nil && !errors.Is(err, tt.wantErr) {
+				t.Errorf("Validate(%q) = %v, want %v", tt.bic, err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestCheckCountry(t *testing.T) {
+	tests := []struct {
+		bic     string
+		country string
+		wantErr bool
+	}{
+		{bic: "COBADEFFXXX", country: "DE"},
+		{bic: "COBADEFFXXX", country: "TR", wantErr: true},
+		{bic: "RBOSGGSX", country: "GB"},
+		{bic: "RBOSGGSX", country: "DE", wantErr: true},
+		{bic: "BNPAGPGP", country: "FR"},
+	}
+
+	for _, tt := range tests {
+		err := CheckCountry(tt.bic, tt.country)
+		if tt.wantErr != (err != nil) {
+			t.Errorf("CheckCountry(%s, %s) = %v", tt.bic, tt.country, err)
+		}
+		if err != nil && !errors.Is(err, ErrCountry) {
+			t.Errorf("CheckCountry(%s, %s) = %v, want %v", tt.bic, tt.country, err, ErrCountry)
+		}
+	}
+}
+
+func TestSameInstitution(t *testing.T) {
+	tests := []struct {
+		a, b string
+		want bool
+	}{
+		{a: "COBADEFF", b: "COBADEFFXXX", want: true},
+		{a: "cobadeff370", b: "COBADEFFXXX", want: true},
+		{a: "DEUTDEFF", b: "COBADEFFXXX", want: false},
+		{a: "", b: "COBADEFFXXX", want: false},
+	}
+
+	for _, tt := range tests {
+		if got := SameInstitution(tt.a, tt.b); got != tt.want {
+			t.Errorf("SameInstitution(%s, %s) = %v, want %v", tt.a, tt.b, got, tt.want)
+		}
+	}
+}
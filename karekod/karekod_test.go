@@ -0,0 +1,115 @@
+package karekod
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"testing"
+)
+
+// decode splits a payload into its top level data objects
+func decode(t *testing.T, payload string) map[string]string {
+	objects := map[string]string{}
+	for i := 0; i < len(payload); {
+		if i+4 > len(payload) {
+			t.Fatalf("Truncated data object at %d in %q", i, payload)
+		}
+		length, err := strconv.Atoi(payload[i+2 : i+4])
+		if err != nil || i+4+length > len(payload) {
+			t.Fatalf("Invalid length at %d in %q", i, payload)
+		}
+		objects[payload[i:i+2]] = payload[i+4 : i+4+length]
+		i += 4 + length
+	}
+	return objects
+}
+
+func TestCRC16(t *testing.T) {
+	if got := crc16("123456789"); got != 0x29B1 {
+		t.Errorf("crc16() = %04X, want 29B1", got)
+	}
+}
+
+func TestPayload(t *testing.T) {
+	tests := []struct {
+		name    string
+		payment Payment
+		want    map[string]string
+	}{
+		{
+			name:    "Static code",
+			payment: Payment{Name: "Ayşe Çelik", IBAN: "TR33 0006 1005 1978 6457 8413 26"},
+			want: map[string]string{
+				"00": "01",
+				"01": "11",
+				"26": "0016tr.gov.tcmb.fast0126TR330006100519786457841326",
+				"52": "0000",
+				"53": "949",
+				"58": "TR",
+				"59": "AYSE CELIK",
+				"60": "TURKIYE",
+			},
+		},
+		{
+			name:    "Dynamic code with amount and purpose",
+			payment: Payment{Name: "Ali Veli", City: "İzmir", IBAN: "TR330006100519786457841326", Amount: 12550, Text: "Kira ödemesi"},
+			want: map[string]string{
+				"01": "12",
+				"54": "125.50",
+				"59": "ALI VELI",
+				"60": "IZMIR",
+				"62": "0812KIRA ODEMESI",
+			},
+		},
+		{
+			name:    "Long name is cut",
+			payment: Payment{Name: "Mehmet Ali Abdullah Yılmazoğlu", IBAN: "TR330006100519786457841326"},
+			want:    map[string]string{"59": "MEHMET ALI ABDULLAH YILMA"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			payload, err := tt.payment.Payload()
+			if err != nil {
+				t.Fatalf("Payload() returned unexpected error: %v", err)
+			}
+			objects := decode(t, payload)
+			for tag, want := range tt.want {
+				if objects[tag] != want {
+					t.Errorf("Tag %s = %q, want %q", tag, objects[tag], want)
+				}
+			}
+			if _, ok := objects["54"]; ok != (tt.payment.Amount > 0) {
+				t.Errorf("Amount present = %v for amount %d", ok, tt.payment.Amount)
+			}
+
+			crc := payload[len(payload)-4:]
+			if want := fmt.Sprintf("%04X", crc16(payload[:len(payload)-4])); crc != want || objects["63"] != want {
+				t.Errorf("CRC = %s, want %s", crc, want)
+			}
+		})
+	}
+}
+
+func TestPayloadErrors(t *testing.T) {
+	tests := []struct {
+		name    string
+		payment Payment
+		wantErr error
+	}{
+		{name: "Not TR", payment: Payment{Name: "Franz", IBAN: "DE89370400440532013000"}, wantErr: ErrNotTR},
+		{name: "Amount too large", payment: Payment{Name: "Ali", IBAN: "TR330006100519786457841326", Amount: MaxAmount + 1}, wantErr: ErrAmount},
+		{name: "Invalid IBAN", payment: Payment{Name: "Ali", IBAN: "TR330006100519786457841327"}},
+		{name: "No name", payment: Payment{IBAN: "TR330006100519786457841326"}},
+	}
+
+	for _, tt := range tests {
+		_, err := tt.payment.Payload()
+		if err == nil {
+			t.Errorf("%s: expected error but got none", tt.name)
+		} else if tt.wantErr != nil && !errors.Is(err, tt.wantErr) {
+			t.Errorf("%s: error = %v, want %v", tt.name, err, tt.wantErr)
+		}
+	}
+}
@@ -8,8 +8,10 @@ import (
 	"net/http"
 
 	graphql "github.com/graph-gophers/graphql-go"
+	"github.com/tapsilat/iban.im/bankdir"
 	"github.com/tapsilat/iban.im/config"
 	"github.com/tapsilat/iban.im/handler"
+	"github.com/tapsilat/iban.im/iso13616"
 	_ "github.com/tapsilat/iban.im/model"
 
 	"github.com/tapsilat/iban.im/resolvers"
@@ -34,6 +36,22 @@ func main() {
 	// Initialize database and run AutoMigrate at startup
 	config.InitDB(cfg)
 
+	// Use a newer IBAN registry than the one embedded at build time
+	if cfg.App.IbanRegistry != "" {
+		if err := iso13616.LoadRegistry(cfg.App.IbanRegistry); err != nil {
+			log.Fatalf("Failed to load IBAN registry: %v", err)
+		}
+		log.Printf("Using IBAN registry %s from %s", iso13616.RegistryVersion(), cfg.App.IbanRegistry)
+	}
+
+	// Refresh the embedded bank directory from a local CSV when configured
+	if cfg.App.BankDirectory != "" {
+		if err := bankdir.LoadFile(cfg.App.BankDirectory); err != nil {
+			log.Fatalf("Failed to load bank directory: %v", err)
+		}
+		log.Printf("Loaded %d banks from %s", bankdir.Len(), cfg.App.BankDirectory)
+	}
+
 	router := gin.Default()
 	router.Use(func(c *gin.Context) {
 		c.Next()
@@ -123,8 +141,22 @@ func main() {
 		c.JSON(200, response)
 	})
 
+	// Batch validation for JSON arrays and CSV files, open to everyone
+	router.POST("/api/v1/iban/validate", handler.ValidateIbans(cfg.App.ValidateRowLimit))
+
+	// SEPA credit transfer files paying public IBANs, open to everyone
+	router.POST("/api/v1/pain001", handler.ExportPain001)
+
 	// Route for serving IBAN addresses at /:userHandle/:ibanHandle
 	router.GET("/:userHandle/:ibanHandle", handler.RenderIbanPage)
+	router.GET("/:userHandle/:ibanHandle/qr.png", handler.RenderIbanQR("png"))
+	router.GET("/:userHandle/:ibanHandle/qr.svg", handler.RenderIbanQR("svg"))
+	router.GET("/:userHandle/:ibanHandle/r/:requestId", handler.RenderPaymentRequest)
+
+	// IBANs of groups at /g/:groupHandle/:ibanHandle
+	router.GET("/g/:groupHandle/:ibanHandle", handler.RenderIbanPage)
+	router.GET("/g/:groupHandle/:ibanHandle/qr.png", handler.RenderIbanQR("png"))
+	router.GET("/g/:groupHandle/:ibanHandle/qr.svg", handler.RenderIbanQR("svg"))
 
 	// Serve the Vue.js SPA for all other routes
 	// This enables client-side routing for the frontend
@@ -1,7 +1,16 @@
 package model
 
 import (
+	"crypto/rand"
+	"encoding/hex"
+	"fmt"
+	"net/url"
+	"regexp"
+	"strings"
 	"time"
+	"unicode/utf8"
+
+	"gorm.io/gorm"
 )
 
 // Group : Model with injected fields `ID`, `CreatedAt`, `UpdatedAt`
@@ -9,12 +18,148 @@ type Group struct {
 	GroupID   uint `gorm:"primary_key"`
 	CreatedAt time.Time
 	UpdatedAt time.Time
-	DeletedAt *time.Time `sql:"index"`
-	GroupName string     `gorm:"type:varchar(100);not null"`
-	GroupURL  string     `gorm:"type:varchar(180)"`
+	// DeletedAt soft deletes groups; their handles stay taken so that nobody
+	// can pose as a deleted organisation
+	DeletedAt gorm.DeletedAt `gorm:"index"`
+	GroupName string         `gorm:"type:varchar(100);not null"`
+	GroupURL  string         `gorm:"type:varchar(180)"`
 	GroupLogo string
 	Verified  bool
 	Active    bool
-	Handle    string `gorm:"type:varchar(50);not null"`
+	Handle    string `gorm:"type:varchar(50);not null;unique"`
 	Ibans     []Iban `gorm:"polymorphic:Owner;"`
+	// OwnerID is the user who created the group; GroupMember tells who
+	// manages it
+	OwnerID uint `gorm:"index"`
+	// VerifyToken is published on the domain of GroupURL to prove that the
+	// group owns it, which sets Verified until the website changes
+	VerifyToken string `gorm:"type:varchar(32)"`
+	VerifiedAt  *time.Time
+}
+
+// RetiredGroupHandle is a former handle of a group. Like the handles of
+// deleted groups it stays taken, so that nobody can take over the old links.
+type RetiredGroupHandle struct {
+	Handle    string `gorm:"type:varchar(50);primary_key"`
+	GroupID   uint   `gorm:"not null;index"`
+	CreatedAt time.Time
+}
+
+// MaxGroupName is the longest group name
+const MaxGroupName = 100
+
+// MaxGroupURL is the longest website or logo address of a group
+const MaxGroupURL = 180
+
+var groupHandle = regexp.MustCompile(`^[a-z0-9][a-z0-9_-]{1,49}$`)
+
+// BeforeSave Callback
+func (group *Group) BeforeSave(tx *gorm.DB) (err error) {
+	group.Handle = strings.ToLower(strings.TrimSpace(group.Handle))
+	group.GroupName = strings.TrimSpace(group.GroupName)
+	group.GroupURL = NormalizeGroupURL(group.GroupURL)
+	group.GroupLogo = strings.TrimSpace(group.GroupLogo)
+	if err = group.Check(); err != nil {
+		return
+	}
+	if group.CheckHandle(tx) {
+		err = fmt.Errorf("handle already exist")
+	}
+	return
+}
+
+// Check validates the name, handle, website and logo of the group
+func (group *Group) Check() error {
+	switch {
+	case group.GroupName == "":
+		return fmt.Errorf("you have to provide the group name")
+	case utf8.RuneCountInString(group.GroupName) > MaxGroupName:
+		return fmt.Errorf("group name may be at most %d characters", MaxGroupName)
+	case group.Handle == "":
+		return fmt.Errorf("you have to provide handle")
+	case !groupHandle.MatchString(group.Handle):
+		return fmt.Errorf("handle must be 2 to 50 lower case letters, digits, - or _")
+	}
+	if err := checkGroupURL("website", group.GroupURL); err != nil {
+		return err
+	}
+	return checkGroupURL("logo", group.GroupLogo)
+}
+
+// CheckHandle reports whether another group, deleted ones included, has or
+// had the handle
+func (group *Group) CheckHandle(tx *gorm.DB) bool {
+	var count int64
+	tx.Session(&gorm.Session{NewDB: true}).Unscoped().Model(&Group{}).
+		Where("handle = ? AND group_id <> ?", group.Handle, group.GroupID).Count(&count)
+	if count > 0 {
+		return true
+	}
+	tx.Session(&gorm.Session{NewDB: true}).Model(&RetiredGroupHandle{}).
+		Where("handle = ? AND group_id <> ?", group.Handle, group.GroupID).Count(&count)
+	return count > 0
+}
+
+// RetireHandle keeps handle, which the group is giving up, taken for it
+func (group *Group) RetireHandle(tx *gorm.DB, handle string) error {
+	return tx.FirstOrCreate(&RetiredGroupHandle{}, RetiredGroupHandle{Handle: handle, GroupID: group.GroupID}).Error
+}
+
+// DisplayName : the name shown to payers as holder of the group's IBANs
+func (group *Group) DisplayName() string {
+	return group.GroupName
+}
+
+// Domain is the host of the group's website
+func (group *Group) Domain() string {
+	u, err := url.Parse(group.GroupURL)
+	if err != nil {
+		return ""
+	}
+	return strings.ToLower(u.Hostname())
+}
+
+// SetURL changes the website and withdraws the verification of the old one
+func (group *Group) SetURL(s string) {
+	s = NormalizeGroupURL(s)
+	old := group.Domain()
+	group.GroupURL = s
+	if group.Domain() != old {
+		group.Verified = false
+		group.VerifiedAt = nil
+	}
+}
+
+// NewVerifyToken gives the group a new token to publish on its domain
+func (group *Group) NewVerifyToken() error {
+	b := make([]byte, 16)
+	if _, err := rand.Read(b); err != nil {
+		return err
+	}
+	group.VerifyToken = hex.EncodeToString(b)
+	return nil
+}
+
+// NormalizeGroupURL adds the https scheme to a website given as bare domain
+func NormalizeGroupURL(s string) string {
+	s = strings.TrimSpace(s)
+	if s != "" && !strings.Contains(s, "://") {
+		s = "https://" + s
+	}
+	return s
+}
+
+// checkGroupURL validates an optional https address shown on public pages
+func checkGroupURL(field, s string) error {
+	if s == "" {
+		return nil
+	}
+	if len(s) > MaxGroupURL {
+		return fmt.Errorf("%s address may be at most %d characters", field, MaxGroupURL)
+	}
+	u, err := url.Parse(s)
+	if err != nil || u.Scheme != "https" || u.Host == "" || u.User != nil {
+		return fmt.Errorf("%s must be an https address", field)
+	}
+	return nil
 }
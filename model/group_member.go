@@ -0,0 +1,107 @@
+package model
+
+import (
+	"fmt"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// GroupMember links a user to a group with a role; invited users become
+// members once they accept
+type GroupMember struct {
+	GroupMemberID uint `gorm:"primary_key"`
+	CreatedAt     time.Time
+	UpdatedAt     time.Time
+	GroupID       uint   `gorm:"not null;uniqueIndex:idx_group_member"`
+	UserID        uint   `gorm:"not null;uniqueIndex:idx_group_member;index"`
+	Role          string `gorm:"type:varchar(10);not null;default:member"`
+	Status        string `gorm:"type:varchar(10);not null;default:invited"`
+	// InvitedBy is the user who sent the invitation
+	InvitedBy uint
+}
+
+// Group roles
+const (
+	RoleOwner  = "owner"
+	RoleAdmin  = "admin"
+	RoleMember = "member"
+)
+
+// Membership states
+const (
+	MemberInvited = "invited"
+	MemberActive  = "active"
+)
+
+// BeforeSave Callback
+func (member *GroupMember) BeforeSave(tx *gorm.DB) (err error) {
+	if member.Role == "" {
+		member.Role = RoleMember
+	}
+	if member.Status == "" {
+		member.Status = MemberInvited
+	}
+	return member.Check()
+}
+
+// Check validates role and status
+func (member *GroupMember) Check() error {
+	switch member.Role {
+	case RoleOwner, RoleAdmin, RoleMember:
+	default:
+		return fmt.Errorf("role must be one of %s, %s or %s", RoleOwner, RoleAdmin, RoleMember)
+	}
+	switch member.Status {
+	case MemberInvited, MemberActive:
+		return nil
+	}
+	return fmt.Errorf("status must be %s or %s", MemberInvited, MemberActive)
+}
+
+// Active reports whether the user accepted the invitation
+func (member *GroupMember) Active() bool {
+	return member.Status == MemberActive
+}
+
+// CanManage reports whether the member may edit the group and its IBANs
+func (member *GroupMember) CanManage() bool {
+	return member.Active() && (member.Role == RoleOwner || member.Role == RoleAdmin)
+}
+
+// FindMembership returns the membership of the user in the group
+func FindMembership(db *gorm.DB, groupID, userID uint) (GroupMember, bool) {
+	var member GroupMember
+	err := db.Where("group_id = ? AND user_id = ?", groupID, userID).First(&member).Error
+	return member, err == nil
+}
+
+// CanManageGroup reports whether the user is an active owner or admin of the
+// group, which only they may edit along with its IBANs
+func CanManageGroup(db *gorm.DB, groupID, userID uint) bool {
+	member, ok := FindMembership(db, groupID, userID)
+	return ok && member.CanManage()
+}
+
+// CountOwners counts the active owners of a group; a group keeps at least one
+func CountOwners(db *gorm.DB, groupID uint) int64 {
+	var count int64
+	db.Model(&GroupMember{}).Where("group_id = ? AND role = ? AND status = ?", groupID, RoleOwner, MemberActive).Count(&count)
+	return count
+}
+
+// BackfillGroupOwners makes the creators of groups made before memberships
+// existed their owners
+func BackfillGroupOwners(db *gorm.DB) error {
+	var groups []Group
+	if err := db.Where("owner_id <> 0 AND NOT EXISTS (SELECT 1 FROM group_members WHERE group_members.group_id = groups.group_id)").Find(&groups).Error; err != nil {
+		return err
+	}
+	for _, group := range groups {
+		owner := GroupMember{GroupID: group.GroupID, UserID: group.OwnerID, Role: RoleOwner, Status: MemberActive}
+		if err := db.Create(&owner).Error; err != nil {
+			return err
+		}
+	}
+	return nil
+}
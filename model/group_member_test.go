@@ -0,0 +1,72 @@
+package model
+
+import (
+	"testing"
+
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+)
+
+func TestGroupMemberCheck(t *testing.T) {
+	tests := []struct {
+		name       string
+		member     GroupMember
+		wantErr    string
+		wantManage bool
+	}{
+		{name: "Active owner", member: GroupMember{Role: RoleOwner, Status: MemberActive}, wantManage: true},
+		{name: "Active admin", member: GroupMember{Role: RoleAdmin, Status: MemberActive}, wantManage: true},
+		{name: "Active member", member: GroupMember{Role: RoleMember, Status: MemberActive}},
+		{name: "Invited admin", member: GroupMember{Role: RoleAdmin, Status: MemberInvited}},
+		{name: "Unknown role", member: GroupMember{Role: "treasurer", Status: MemberActive}, wantErr: "role must be one of owner, admin or member"},
+		{name: "Unknown status", member: GroupMember{Role: RoleMember, Status: "left"}, wantErr: "status must be invited or active"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.member.Check()
+			if tt.wantErr != "" {
+				if err == nil || err.Error() != tt.wantErr {
+					t.Errorf("Check() = %v, want %s", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Errorf("Check() returned unexpected error: %v", err)
+			}
+			if got := tt.member.CanManage(); got != tt.wantManage {
+				t.Errorf("CanManage() = %v, want %v", got, tt.wantManage)
+			}
+		})
+	}
+}
+
+func TestBackfillGroupOwners(t *testing.T) {
+	db, err := gorm.Open(sqlite.Open(":memory:"), &gorm.Config{})
+	if err != nil {
+		t.Fatalf("Failed to connect to test database: %v", err)
+	}
+	if err := db.AutoMigrate(&Group{}, &GroupMember{}); err != nil {
+		t.Fatalf("Failed to auto-migrate: %v", err)
+	}
+	old := Group{GroupName: "Acme Ltd", Handle: "acme", OwnerID: 7}
+	withOwner := Group{GroupName: "Chess Club", Handle: "chess", OwnerID: 7}
+	for _, group := range []*Group{&old, &withOwner} {
+		if err := db.Create(group).Error; err != nil {
+			t.Fatalf("Failed to create group: %v", err)
+		}
+	}
+	db.Create(&GroupMember{GroupID: withOwner.GroupID, UserID: 8, Role: RoleOwner, Status: MemberActive})
+
+	for i := 0; i < 2; i++ {
+		if err := BackfillGroupOwners(db); err != nil {
+			t.Fatalf("BackfillGroupOwners() returned unexpected error: %v", err)
+		}
+	}
+	if !CanManageGroup(db, old.GroupID, 7) || CountOwners(db, old.GroupID) != 1 {
+		t.Errorf("The creator of %s must become its only owner", old.Handle)
+	}
+	if CanManageGroup(db, withOwner.GroupID, 7) || CountOwners(db, withOwner.GroupID) != 1 {
+		t.Errorf("Groups with members must be left alone")
+	}
+}
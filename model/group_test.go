@@ -2,6 +2,9 @@ package model
 
 import (
 	"testing"
+
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
 )
 
 func TestGroupStructFields(t *testing.T) {
@@ -75,3 +78,63 @@ func TestGroupDefaultValues(t *testing.T) {
 		t.Error("Default Active should be false")
 	}
 }
+
+func TestGroupCheck(t *testing.T) {
+	tests := []struct {
+		name    string
+		group   Group
+		wantErr string
+	}{
+		{name: "Valid", group: Group{GroupName: "Acme Ltd", Handle: "acme_ltd", GroupURL: "https://acme.example", GroupLogo: "https://acme.example/logo.png"}},
+		{name: "Without name", group: Group{Handle: "acme"}, wantErr: "you have to provide the group name"},
+		{name: "Without handle", group: Group{GroupName: "Acme Ltd"}, wantErr: "you have to provide handle"},
+		{name: "One letter handle", group: Group{GroupName: "Acme Ltd", Handle: "a"}, wantErr: "handle must be 2 to 50 lower case letters, digits, - or _"},
+		{name: "Handle with slash", group: Group{GroupName: "Acme Ltd", Handle: "acme/ltd"}, wantErr: "handle must be 2 to 50 lower case letters, digits, - or _"},
+		{name: "Website over http", group: Group{GroupName: "Acme Ltd", Handle: "acme", GroupURL: "http://acme.example"}, wantErr: "website must be an https address"},
+		{name: "Relative logo", group: Group{GroupName: "Acme Ltd", Handle: "acme", GroupLogo: "logo.png"}, wantErr: "logo must be an https address"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.group.Check()
+			if tt.wantErr == "" {
+				if err != nil {
+					t.Errorf("Check() returned unexpected error: %v", err)
+				}
+				return
+			}
+			if err == nil || err.Error() != tt.wantErr {
+				t.Errorf("Check() = %v, want %s", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestGroupRetireHandle(t *testing.T) {
+	db, err := gorm.Open(sqlite.Open(":memory:"), &gorm.Config{})
+	if err != nil {
+		t.Fatalf("Failed to connect to test database: %v", err)
+	}
+	if err := db.AutoMigrate(&Group{}, &RetiredGroupHandle{}); err != nil {
+		t.Fatalf("Failed to auto-migrate: %v", err)
+	}
+	group := Group{GroupName: "Acme Ltd", Handle: "acme"}
+	if err := db.Create(&group).Error; err != nil {
+		t.Fatalf("Failed to create group: %v", err)
+	}
+	for _, handle := range []string{"acme-ltd", "acme", "acme-ltd"} {
+		old := group.Handle
+		group.Handle = handle
+		if err := group.RetireHandle(db, old); err != nil {
+			t.Fatalf("RetireHandle(%s) returned unexpected error: %v", old, err)
+		}
+		if err := db.Save(&group).Error; err != nil {
+			t.Fatalf("Group must take back its own handle %s: %v", handle, err)
+		}
+	}
+
+	other := Group{GroupName: "Not Acme", Handle: "acme"}
+	if err := db.Create(&other).Error; err == nil || err.Error() != "handle already exist" {
+		t.Errorf("Create() with a retired handle = %v", err)
+	}
+}
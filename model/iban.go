@@ -2,9 +2,17 @@ package model
 
 import (
 	"fmt"
+	"slices"
+	"strconv"
 	"strings"
 	"time"
+	"unicode"
+	"unicode/utf8"
 
+	"github.com/tapsilat/iban.im/iso13616"
+	"github.com/tapsilat/iban.im/iso4217"
+	"github.com/tapsilat/iban.im/iso9362"
+	"github.com/tapsilat/iban.im/paymethod"
 	"golang.org/x/crypto/bcrypt"
 	"gorm.io/gorm"
 )
@@ -16,15 +24,54 @@ type Iban struct {
 	UpdatedAt   time.Time
 	DeletedAt   *time.Time `sql:"index"`
 	Text        string     `gorm:"type:varchar(100);not null"`
+	BIC         string     `gorm:"type:varchar(11)"`
 	Description string
 	Password    string
 	Handle      string `gorm:"type:varchar(20);not null"`
 	Active      bool
 	IsPrivate   bool
 	OwnerID     uint
-	OwnerType   string
+	// OwnerType tells whether OwnerID is a user or a group
+	OwnerType string `gorm:"type:varchar(10);index"`
+	// Method is the paymethod type; Text holds the IBAN, account number,
+	// crypto address or payment link of it
+	Method string `gorm:"type:varchar(10);not null;default:iban"`
+	// RoutingCode is the UK sort code or the US ABA routing number
+	RoutingCode string `gorm:"type:varchar(9)"`
+	// Currency of the account and its preset amounts, an ISO 4217 code
+	Currency string `gorm:"type:varchar(3)"`
+	// HolderName is the name the account is held in, when it is not the
+	// owner's, e.g. a company or a joint account
+	HolderName  string `gorm:"type:varchar(70)"`
+	AccountType string `gorm:"type:varchar(10)"`
+	// Presets are suggested amounts in cents, comma separated, that the
+	// public page offers as buttons, e.g. for donations
+	Presets string `gorm:"type:varchar(100)"`
 }
 
+// Owner types of IBANs
+const (
+	OwnerUser  = "User"
+	OwnerGroup = "Group"
+)
+
+// MaxPresetAmounts is how many suggested amounts an IBAN may have
+const MaxPresetAmounts = 6
+
+// MaxHolderName is the longest holder name, the limit of SEPA credit
+// transfers
+const MaxHolderName = 70
+
+// Account types
+const (
+	AccountChecking = "checking"
+	AccountSavings  = "savings"
+	AccountBusiness = "business"
+)
+
+// AccountTypes lists the valid account types
+var AccountTypes = []string{AccountChecking, AccountSavings, AccountBusiness}
+
 // HashPassword : hashing the password
 func (iban *Iban) HashPassword() {
 	hash, err := bcrypt.GenerateFromPassword([]byte(iban.Password), bcrypt.DefaultCost)
@@ -46,7 +93,7 @@ func (iban *Iban) ComparePassword(password string) bool {
 // Check Handle before create or update = must be add as index to db
 func (iban *Iban) CheckHandle(tx *gorm.DB) (exist bool) {
 	var ibans []Iban
-	tx.Where("owner_id = ? AND handle = ?", iban.OwnerID, iban.Handle).Find(&ibans)
+	tx.Where("owner_id = ? AND owner_type = ? AND handle = ?", iban.OwnerID, iban.ownerType(), iban.Handle).Find(&ibans)
 	for _, tmp := range ibans {
 		if iban.Handle == tmp.Handle && iban.IbanID != tmp.IbanID {
 			exist = true
@@ -55,8 +102,57 @@ func (iban *Iban) CheckHandle(tx *gorm.DB) (exist bool) {
 	return
 }
 
+// ownerType is the owner type, a user for rows stored before groups could
+// own IBANs
+func (iban *Iban) ownerType() string {
+	if iban.OwnerType == "" {
+		return OwnerUser
+	}
+	return iban.OwnerType
+}
+
+// IsIBAN reports whether the payment method is an IBAN, as all are that
+// were stored before other methods existed
+func (iban *Iban) IsIBAN() bool {
+	return iban.methodType() == paymethod.IBAN
+}
+
+// Account is what the payment method pays into
+func (iban *Iban) Account() paymethod.Account {
+	return paymethod.Account{Number: iban.Text, Routing: iban.RoutingCode}
+}
+
+// PaymentMethod returns the validator and renderer of the payment method
+func (iban *Iban) PaymentMethod() (paymethod.Method, bool) {
+	return paymethod.Lookup(iban.methodType())
+}
+
+// BackfillMethods marks the rows stored before payment methods existed as
+// IBANs, for databases that did not apply the column default to them
+func BackfillMethods(db *gorm.DB) error {
+	return db.Model(&Iban{}).Where("method IS NULL OR method = ''").UpdateColumn("method", paymethod.IBAN).Error
+}
+
+// BackfillOwnerTypes marks the rows stored before groups could own IBANs as
+// owned by users
+func BackfillOwnerTypes(db *gorm.DB) error {
+	return db.Model(&Iban{}).Where("owner_type IS NULL OR owner_type = ''").UpdateColumn("owner_type", OwnerUser).Error
+}
+
 // BeforeSave Callback
 func (iban *Iban) BeforeSave(tx *gorm.DB) (err error) {
+	iban.OwnerType = iban.ownerType()
+	if iban.Method == "" {
+		iban.Method = paymethod.IBAN
+	}
+	if m, ok := iban.PaymentMethod(); ok {
+		account := m.Normalize(iban.Account())
+		iban.Text, iban.RoutingCode = account.Number, account.Routing
+	}
+	iban.BIC = iso9362.Normalize(iban.BIC)
+	iban.Currency = iso4217.Normalize(iban.Currency)
+	iban.HolderName = strings.TrimSpace(iban.HolderName)
+	iban.AccountType = strings.ToLower(strings.TrimSpace(iban.AccountType))
 	if iban.CheckHandle(tx) {
 		err = fmt.Errorf("handle already exist")
 	}
@@ -64,11 +160,125 @@ func (iban *Iban) BeforeSave(tx *gorm.DB) (err error) {
 }
 
 func (iban *Iban) Validate(db *gorm.DB) {
-	if strings.TrimSpace(iban.Text) == "" {
+	if strings.TrimSpace(iban.Text) == "" && iban.IsIBAN() {
 		db.AddError(fmt.Errorf("you have to provide IBAN"))
+	} else if strings.TrimSpace(iban.Text) == "" {
+		db.AddError(fmt.Errorf("you have to provide the account"))
+	} else if err := iban.CheckMethod(); err != nil {
+		db.AddError(err)
+	} else if err := iban.CheckBIC(); err != nil {
+		db.AddError(err)
+	} else if err := iban.CheckAccount(); err != nil {
+		db.AddError(err)
+	} else if err := iban.CheckPresets(); err != nil {
+		db.AddError(err)
 	} else if strings.TrimSpace(iban.Handle) == "" {
 		db.AddError(fmt.Errorf("you have to provide handle"))
 	} else if iban.IsPrivate && strings.TrimSpace(iban.Password) == "" {
 		db.AddError(fmt.Errorf("you have to provide password"))
 	}
 }
+
+// CheckMethod validates the account with the validator of its payment method
+func (iban *Iban) CheckMethod() error {
+	_, err := paymethod.Validate(iban.methodType(), iban.Account())
+	return err
+}
+
+// methodType is the payment method, IBAN for rows stored before methods
+func (iban *Iban) methodType() string {
+	if iban.Method == "" {
+		return paymethod.IBAN
+	}
+	return iban.Method
+}
+
+// CheckBIC validates the optional BIC and that a bank of its country can hold
+// the IBAN
+func (iban *Iban) CheckBIC() error {
+	if strings.TrimSpace(iban.BIC) == "" {
+		return nil
+	}
+	if !iban.IsIBAN() {
+		return fmt.Errorf("a BIC can only be given for an IBAN")
+	}
+	if err := iso9362.Validate(iban.BIC); err != nil {
+		return err
+	}
+	return iso9362.CheckCountry(iban.BIC, iso13616.CountryCode(iban.Text))
+}
+
+// CheckAccount validates the optional holder name and account type
+func (iban *Iban) CheckAccount() error {
+	holder := strings.TrimSpace(iban.HolderName)
+	if utf8.RuneCountInString(holder) > MaxHolderName {
+		return fmt.Errorf("holder name must be at most %d characters", MaxHolderName)
+	}
+	if strings.IndexFunc(holder, unicode.IsControl) >= 0 {
+		return fmt.Errorf("holder name must not contain control characters")
+	}
+	accountType := strings.ToLower(strings.TrimSpace(iban.AccountType))
+	if accountType != "" && !slices.Contains(AccountTypes, accountType) {
+		return fmt.Errorf("account type must be one of %s", strings.Join(AccountTypes, ", "))
+	}
+	return nil
+}
+
+// Owner is the user or group an IBAN belongs to
+type Owner interface {
+	DisplayName() string
+}
+
+// Holder is the name the account is held in, the owner's unless a holder
+// name is given
+func (iban *Iban) Holder(owner Owner) string {
+	if holder := strings.TrimSpace(iban.HolderName); holder != "" {
+		return holder
+	}
+	return owner.DisplayName()
+}
+
+// PresetAmounts returns the suggested amounts in cents, smallest first
+func (iban *Iban) PresetAmounts() []int64 {
+	amounts := []int64{}
+	for _, field := range strings.Split(iban.Presets, ",") {
+		if cents, err := strconv.ParseInt(strings.TrimSpace(field), 10, 64); err == nil && cents > 0 {
+			amounts = append(amounts, cents)
+		}
+	}
+	slices.Sort(amounts)
+	return amounts
+}
+
+// SetPresetAmounts stores the suggested amounts in cents, sorted and without
+// duplicates
+func (iban *Iban) SetPresetAmounts(amounts []int64) {
+	amounts = slices.Clone(amounts)
+	slices.Sort(amounts)
+	fields := []string{}
+	for _, cents := range slices.Compact(amounts) {
+		fields = append(fields, strconv.FormatInt(cents, 10))
+	}
+	iban.Presets = strings.Join(fields, ",")
+}
+
+// CheckPresets validates the currency and the suggested amounts in it
+func (iban *Iban) CheckPresets() error {
+	currency := iso4217.Normalize(iban.Currency)
+	amounts := strings.Count(iban.Presets, ",") + 1
+	switch {
+	case iban.Presets == "":
+		amounts = 0
+	case len(iban.PresetAmounts()) != amounts:
+		return fmt.Errorf("preset amounts must be positive")
+	}
+	switch {
+	case amounts > MaxPresetAmounts:
+		return fmt.Errorf("at most %d preset amounts are allowed", MaxPresetAmounts)
+	case amounts > 0 && currency == "":
+		return fmt.Errorf("you have to provide the currency of the preset amounts")
+	case currency != "":
+		return iso4217.Validate(currency)
+	}
+	return nil
+}
@@ -0,0 +1,126 @@
+package model
+
+import (
+	"encoding/json"
+	"fmt"
+	"time"
+	"unicode/utf8"
+
+	"gorm.io/gorm"
+)
+
+// IbanChange is an update or deletion of a group IBAN waiting for a second
+// owner or admin to approve it. Decided changes are kept as audit trail.
+type IbanChange struct {
+	IbanChangeID uint `gorm:"primary_key"`
+	CreatedAt    time.Time
+	UpdatedAt    time.Time
+	IbanID       uint   `gorm:"not null;index"`
+	GroupID      uint   `gorm:"not null;index"`
+	Action       string `gorm:"type:varchar(10);not null"`
+	Status       string `gorm:"type:varchar(10);not null;default:pending"`
+	// Snapshot is the IBAN as JSON, as it will be after an update or as it
+	// was before a deletion
+	Snapshot    string `gorm:"type:text"`
+	RequestedBy uint
+	DecidedBy   uint
+	DecidedAt   *time.Time
+	// Reason is why the change was rejected
+	Reason string `gorm:"type:varchar(255)"`
+	// PendingIbanID is the IBAN while the change is pending and NULL once
+	// decided; its unique index allows one pending change per IBAN
+	PendingIbanID *uint `gorm:"uniqueIndex"`
+}
+
+// Actions of IBAN changes
+const (
+	ChangeUpdate = "update"
+	ChangeDelete = "delete"
+)
+
+// States of IBAN changes
+const (
+	ChangePending  = "pending"
+	ChangeApproved = "approved"
+	ChangeRejected = "rejected"
+)
+
+// MaxChangeReason is the longest reason for rejecting a change
+const MaxChangeReason = 255
+
+// BeforeSave Callback
+func (change *IbanChange) BeforeSave(tx *gorm.DB) (err error) {
+	if change.Status == "" {
+		change.Status = ChangePending
+	}
+	if utf8.RuneCountInString(change.Reason) > MaxChangeReason {
+		return fmt.Errorf("reason may be at most %d characters", MaxChangeReason)
+	}
+	switch change.Action {
+	case ChangeUpdate, ChangeDelete:
+	default:
+		return fmt.Errorf("action must be %s or %s", ChangeUpdate, ChangeDelete)
+	}
+	switch change.Status {
+	case ChangePending:
+		ibanID := change.IbanID
+		change.PendingIbanID = &ibanID
+		return nil
+	case ChangeApproved, ChangeRejected:
+		change.PendingIbanID = nil
+		return nil
+	}
+	return fmt.Errorf("status must be one of %s, %s or %s", ChangePending, ChangeApproved, ChangeRejected)
+}
+
+// NewIbanChange stages the update or deletion of a group IBAN by a user
+func NewIbanChange(action string, iban Iban, userID uint) (IbanChange, error) {
+	change := IbanChange{IbanID: iban.IbanID, GroupID: iban.OwnerID, Action: action, RequestedBy: userID}
+	b, err := json.Marshal(iban)
+	change.Snapshot = string(b)
+	return change, err
+}
+
+// Iban decodes the snapshot
+func (change *IbanChange) Iban() (Iban, error) {
+	var iban Iban
+	err := json.Unmarshal([]byte(change.Snapshot), &iban)
+	return iban, err
+}
+
+// Pending reports whether the change still awaits a decision
+func (change *IbanChange) Pending() bool {
+	return change.Status == ChangePending
+}
+
+// Apply carries out an approved change
+func (change *IbanChange) Apply(tx *gorm.DB) error {
+	var count int64
+	if err := tx.Model(&Iban{}).Where("iban_id = ?", change.IbanID).Count(&count).Error; err != nil {
+		return err
+	}
+	if count == 0 {
+		return fmt.Errorf("iban is not exist")
+	}
+	if change.Action == ChangeDelete {
+		return tx.Where("iban_id = ?", change.IbanID).Delete(&Iban{}).Error
+	}
+	iban, err := change.Iban()
+	if err != nil {
+		return err
+	}
+	return tx.Save(&iban).Error
+}
+
+// Decide records the approval or rejection of the change by a user
+func (change *IbanChange) Decide(status string, userID uint, reason string) {
+	now := time.Now()
+	change.Status, change.DecidedBy, change.DecidedAt, change.Reason = status, userID, &now, reason
+}
+
+// HasPendingChange reports whether an IBAN has a change awaiting a decision
+func HasPendingChange(db *gorm.DB, ibanID uint) bool {
+	var count int64
+	db.Model(&IbanChange{}).Where("iban_id = ? AND status = ?", ibanID, ChangePending).Count(&count)
+	return count > 0
+}
@@ -0,0 +1,86 @@
+package model
+
+import (
+	"strings"
+	"testing"
+
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+)
+
+func TestIbanChangeApply(t *testing.T) {
+	db, err := gorm.Open(sqlite.Open(":memory:"), &gorm.Config{})
+	if err != nil {
+		t.Fatalf("Failed to connect to test database: %v", err)
+	}
+	if err := db.AutoMigrate(&Iban{}, &IbanChange{}); err != nil {
+		t.Fatalf("Failed to auto-migrate: %v", err)
+	}
+	iban := Iban{Text: "DE89370400440532013000", Handle: "donations", OwnerID: 3, OwnerType: OwnerGroup}
+	if err := db.Create(&iban).Error; err != nil {
+		t.Fatalf("Failed to create IBAN: %v", err)
+	}
+
+	edited := iban
+	edited.Text, edited.Handle = "DE02120300000000202051", "donate"
+	update, err := NewIbanChange(ChangeUpdate, edited, 7)
+	if err != nil || update.GroupID != 3 || update.IbanID != iban.IbanID {
+		t.Fatalf("NewIbanChange() = %+v, %v", update, err)
+	}
+	if err := db.Create(&update).Error; err != nil || !update.Pending() || !HasPendingChange(db, iban.IbanID) {
+		t.Fatalf("Change must be pending, got %s: %v", update.Status, err)
+	}
+
+	second, _ := NewIbanChange(ChangeDelete, iban, 8)
+	if err := db.Create(&second).Error; err == nil {
+		t.Errorf("Create() of a second pending change must fail")
+	}
+
+	var live Iban
+	db.First(&live, iban.IbanID)
+	if live.Handle != "donations" {
+		t.Errorf("Staging changed the IBAN to %s", live.Handle)
+	}
+	if err := update.Apply(db); err != nil {
+		t.Fatalf("Apply() returned unexpected error: %v", err)
+	}
+	update.Decide(ChangeApproved, 8, "")
+	if err := db.Save(&update).Error; err != nil || update.PendingIbanID != nil || HasPendingChange(db, iban.IbanID) {
+		t.Fatalf("Change must be decided, got %s: %v", update.Status, err)
+	}
+	if err := db.Create(&second).Error; err != nil {
+		t.Errorf("Create() after the decision returned unexpected error: %v", err)
+	}
+	db.First(&live, iban.IbanID)
+	if live.Handle != "donate" || live.Text != "DE02120300000000202051" || live.OwnerType != OwnerGroup {
+		t.Errorf("Apply() left %s %s", live.Handle, live.Text)
+	}
+
+	deletion, _ := NewIbanChange(ChangeDelete, live, 7)
+	if err := deletion.Apply(db); err != nil {
+		t.Fatalf("Apply() returned unexpected error: %v", err)
+	}
+	if err := db.First(&Iban{}, iban.IbanID).Error; err == nil {
+		t.Errorf("Apply() of a deletion kept the IBAN")
+	}
+	if err := update.Apply(db); err == nil || err.Error() != "iban is not exist" {
+		t.Errorf("Apply() to a deleted IBAN = %v", err)
+	}
+
+	tests := []struct {
+		name    string
+		change  IbanChange
+		wantErr string
+	}{
+		{name: "Unknown action", change: IbanChange{Action: "rename"}, wantErr: "action must be update or delete"},
+		{name: "Unknown status", change: IbanChange{Action: ChangeDelete, Status: "withdrawn"}, wantErr: "status must be one of pending, approved or rejected"},
+		{name: "Long reason", change: IbanChange{Action: ChangeDelete, Status: ChangeRejected, Reason: strings.Repeat("x", MaxChangeReason+1)}, wantErr: "reason may be at most 255 characters"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := db.Create(&tt.change).Error; err == nil || err.Error() != tt.wantErr {
+				t.Errorf("Create() = %v, want %s", err, tt.wantErr)
+			}
+		})
+	}
+}
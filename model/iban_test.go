@@ -1,6 +1,7 @@
 package model
 
 import (
+	"strings"
 	"testing"
 
 	"gorm.io/driver/sqlite"
@@ -235,6 +236,24 @@ func TestIbanBeforeSave(t *testing.T) {
 	}
 }
 
+func TestIbanBeforeSaveNormalizesText(t *testing.T) {
+	db := setupTestDB(t)
+
+	iban := Iban{Handle: "spaced", Text: "tr32 0010 0099 9990 1234 5678 90", BIC: "adab tr is", OwnerID: 1}
+	if err := db.Create(&iban).Error; err != nil {
+		t.Fatalf("Failed to create test IBAN: %v", err)
+	}
+
+	var stored Iban
+	db.First(&stored, iban.IbanID)
+	if stored.Text != "TR320010009999901234567890" {
+		t.Errorf("Text = %s, want TR320010009999901234567890", stored.Text)
+	}
+	if stored.BIC != "ADABTRIS" {
+		t.Errorf("BIC = %s, want ADABTRIS", stored.BIC)
+	}
+}
+
 func TestIbanValidate(t *testing.T) {
 	db := setupTestDB(t)
 
@@ -266,6 +285,35 @@ func TestIbanValidate(t *testing.T) {
 			expectError: true,
 			errorMsg:    "you have to provide IBAN",
 		},
+		{
+			name:        "Wrong IBAN length",
+			iban:        Iban{Text: "TR32001000999990123456789", Handle: "myiban", IsPrivate: false},
+			expectError: true,
+			errorMsg:    "invalid IBAN length for TR: expected 26 characters, got 25",
+		},
+		{
+			name:        "Bad IBAN check digits",
+			iban:        Iban{Text: "TR420010009999901234567891", Handle: "myiban", IsPrivate: false},
+			expectError: true,
+			errorMsg:    "IBAN checksum does not match",
+		},
+		{
+			name:        "Valid BIC",
+			iban:        Iban{Text: "TR320010009999901234567890", BIC: "ADABTRISXXX", Handle: "myiban", IsPrivate: false},
+			expectError: false,
+		},
+		{
+			name:        "Malformed BIC",
+			iban:        Iban{Text: "TR320010009999901234567890", BIC: "ADABTR", Handle: "myiban", IsPrivate: false},
+			expectError: true,
+			errorMsg:    "BIC must be 8 or 11 characters, got 6",
+		},
+		{
+			name:        "BIC from another country",
+			iban:        Iban{Text: "TR320010009999901234567890", BIC: "COBADEFFXXX", Handle: "myiban", IsPrivate: false},
+			expectError: true,
+			errorMsg:    "BIC country does not match: BIC is from DE, IBAN from TR",
+		},
 		{
 			name:        "Empty handle",
 			iban:        Iban{Text: "TR320010009999901234567890", Handle: "", IsPrivate: false},
@@ -290,6 +338,29 @@ func TestIbanValidate(t *testing.T) {
 			expectError: true,
 			errorMsg:    "you have to provide password",
 		},
+		{
+			name:        "Valid UK account",
+			iban:        Iban{Method: "sortcode", Text: "31415926", RoutingCode: "20-00-00", Handle: "pounds"},
+			expectError: false,
+		},
+		{
+			name:        "Empty crypto address",
+			iban:        Iban{Method: "crypto", Text: " ", Handle: "coins"},
+			expectError: true,
+			errorMsg:    "you have to provide the account",
+		},
+		{
+			name:        "Bad Ethereum checksum",
+			iban:        Iban{Method: "crypto", Text: "0x5aAeb6053F3E94C9b9A09f33669435E7Ef1BeAeD", Handle: "coins"},
+			expectError: true,
+			errorMsg:    "account checksum does not match: Ethereum address 0x5aAeb6053F3E94C9b9A09f33669435E7Ef1BeAeD",
+		},
+		{
+			name:        "BIC for a US account",
+			iban:        Iban{Method: "aba", Text: "000123456789", RoutingCode: "021000021", BIC: "CHASUS33", Handle: "dollars"},
+			expectError: true,
+			errorMsg:    "a BIC can only be given for an IBAN",
+		},
 	}
 
 	for _, tt := range tests {
@@ -344,3 +415,132 @@ func TestIbanStructFields(t *testing.T) {
 		t.Errorf("OwnerID = %d, want 1", iban.OwnerID)
 	}
 }
+
+func TestIbanPresetAmounts(t *testing.T) {
+	tests := []struct {
+		name        string
+		amounts     []int64
+		currency    string
+		presets     string
+		expectError bool
+	}{
+		{name: "Sorted without duplicates", amounts: []int64{2000, 500, 1000, 500}, currency: "EUR", presets: "500,1000,2000"},
+		{name: "None", amounts: nil, presets: ""},
+		{name: "Currency only", amounts: nil, currency: "try", presets: ""},
+		{name: "Without currency", amounts: []int64{500}, presets: "500", expectError: true},
+		{name: "Invalid currency", amounts: []int64{500}, currency: "EURO", presets: "500", expectError: true},
+		{name: "Too many", amounts: []int64{1, 2, 3, 4, 5, 6, 7}, currency: "EUR", presets: "1,2,3,4,5,6,7", expectError: true},
+		{name: "Not positive", amounts: []int64{0, 500}, currency: "EUR", presets: "0,500", expectError: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			iban := &Iban{Currency: tt.currency}
+			iban.SetPresetAmounts(tt.amounts)
+			if iban.Presets != tt.presets {
+				t.Errorf("Presets = %q, want %q", iban.Presets, tt.presets)
+			}
+			err := iban.CheckPresets()
+			if tt.expectError && err == nil {
+				t.Error("Expected error but got none")
+			}
+			if !tt.expectError && err != nil {
+				t.Errorf("Unexpected error: %v", err)
+			}
+		})
+	}
+
+	iban := &Iban{Presets: "500,1000,2000"}
+	if got := iban.PresetAmounts(); len(got) != 3 || got[0] != 500 || got[2] != 2000 {
+		t.Errorf("PresetAmounts() = %v, want [500 1000 2000]", got)
+	}
+}
+
+func TestIbanCheckAccount(t *testing.T) {
+	tests := []struct {
+		name        string
+		holderName  string
+		accountType string
+		expectError bool
+	}{
+		{name: "None"},
+		{name: "Company account", holderName: "Acme GmbH", accountType: AccountBusiness},
+		{name: "Upper case type", accountType: "Savings"},
+		{name: "Unknown type", accountType: "brokerage", expectError: true},
+		{name: "Holder name too long", holderName: strings.Repeat("a", MaxHolderName+1), expectError: true},
+		{name: "Control character", holderName: "Acme\nGmbH", expectError: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			iban := &Iban{HolderName: tt.holderName, AccountType: tt.accountType}
+			err := iban.CheckAccount()
+			if tt.expectError && err == nil {
+				t.Error("Expected error but got none")
+			}
+			if !tt.expectError && err != nil {
+				t.Errorf("Unexpected error: %v", err)
+			}
+		})
+	}
+
+	owner := User{FirstName: "Jane", LastName: "Doe"}
+	if got := (&Iban{}).Holder(&owner); got != "Jane Doe" {
+		t.Errorf("Holder() = %q, want the owner's name", got)
+	}
+	if got := (&Iban{HolderName: "Acme GmbH"}).Holder(&owner); got != "Acme GmbH" {
+		t.Errorf("Holder() = %q, want Acme GmbH", got)
+	}
+	if err := (&Iban{Currency: "ABC"}).CheckPresets(); err == nil {
+		t.Error("CheckPresets() should reject an unknown currency")
+	}
+}
+
+// ibanBeforeMethods is the ibans table as it was before payment methods
+type ibanBeforeMethods struct {
+	IbanID  uint `gorm:"primary_key"`
+	Text    string
+	Handle  string
+	OwnerID uint
+}
+
+func (ibanBeforeMethods) TableName() string {
+	return "ibans"
+}
+
+func TestIbanMethodMigration(t *testing.T) {
+	db, err := gorm.Open(sqlite.Open(":memory:"), &gorm.Config{})
+	if err != nil {
+		t.Fatalf("Failed to connect to test database: %v", err)
+	}
+	if err := db.AutoMigrate(&ibanBeforeMethods{}); err != nil {
+		t.Fatalf("Failed to create the old table: %v", err)
+	}
+	old := ibanBeforeMethods{Text: "DE89370400440532013000", Handle: "old", OwnerID: 1}
+	if err := db.Create(&old).Error; err != nil {
+		t.Fatalf("Failed to create old IBAN: %v", err)
+	}
+
+	if err := db.AutoMigrate(&Iban{}); err != nil {
+		t.Fatalf("Failed to auto-migrate: %v", err)
+	}
+	if err := BackfillMethods(db); err != nil {
+		t.Fatalf("BackfillMethods() returned unexpected error: %v", err)
+	}
+
+	var migrated Iban
+	if err := db.First(&migrated, old.IbanID).Error; err != nil {
+		t.Fatalf("Failed to load migrated IBAN: %v", err)
+	}
+	if migrated.Method != "iban" || !migrated.IsIBAN() || migrated.CheckMethod() != nil {
+		t.Errorf("Method = %q, want a valid iban", migrated.Method)
+	}
+
+	link := Iban{Method: "link", Text: "PayPal.me/fakturk", Handle: "paypal", OwnerID: 1}
+	if err := db.Create(&link).Error; err != nil {
+		t.Fatalf("Failed to create link: %v", err)
+	}
+	if link.Text != "https://paypal.me/fakturk" || link.IsIBAN() {
+		t.Errorf("Text = %q, want the normalized link", link.Text)
+	}
+}
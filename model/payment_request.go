@@ -0,0 +1,101 @@
+package model
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"fmt"
+	"time"
+
+	"github.com/tapsilat/iban.im/iso11649"
+	"github.com/tapsilat/iban.im/iso4217"
+	"gorm.io/gorm"
+)
+
+// Payment request statuses
+const (
+	PaymentRequestOpen      = "open"
+	PaymentRequestPaid      = "paid"
+	PaymentRequestCancelled = "cancelled"
+)
+
+// PaymentRequest : a payment the owner asks for to one of their IBANs, shown
+// at /:userHandle/:ibanHandle/r/:publicID
+type PaymentRequest struct {
+	PaymentRequestID uint `gorm:"primary_key"`
+	CreatedAt        time.Time
+	UpdatedAt        time.Time
+	DeletedAt        *time.Time `sql:"index"`
+	// PublicID is the random part of the public URL, so that requests
+	// cannot be enumerated
+	PublicID string `gorm:"type:varchar(32);not null;uniqueIndex"`
+	OwnerID  uint   `gorm:"not null;index"`
+	IbanID   uint   `gorm:"not null;index"`
+	// Amount in cents, 0 leaves the amount to the payer
+	Amount   int64
+	Currency string `gorm:"type:varchar(3)"`
+	// Reference is an ISO 11649 RF creditor reference
+	Reference   string `gorm:"type:varchar(25)"`
+	Description string
+	// Payer is who is expected to pay, as shown on bank statements, to
+	// match payments without a reference
+	Payer     string `gorm:"type:varchar(70)"`
+	ExpiresAt *time.Time
+	Status    string `gorm:"type:varchar(10);not null;default:open"`
+}
+
+// BeforeCreate Callback
+func (request *PaymentRequest) BeforeCreate(tx *gorm.DB) (err error) {
+	if request.PublicID == "" {
+		b := make([]byte, 8)
+		if _, err = rand.Read(b); err != nil {
+			return
+		}
+		request.PublicID = hex.EncodeToString(b)
+	}
+	if request.Status == "" {
+		request.Status = PaymentRequestOpen
+	}
+	return
+}
+
+// BeforeSave Callback
+func (request *PaymentRequest) BeforeSave(tx *gorm.DB) (err error) {
+	request.Currency = iso4217.Normalize(request.Currency)
+	request.Reference = iso11649.Normalize(request.Reference)
+	return request.Check()
+}
+
+// Check validates amount, currency, reference and status
+func (request *PaymentRequest) Check() error {
+	currency := iso4217.Normalize(request.Currency)
+	switch {
+	case request.Amount < 0:
+		return fmt.Errorf("amount must not be negative")
+	case request.Amount > 0 && currency == "":
+		return fmt.Errorf("you have to provide the currency of the amount")
+	case currency != "":
+		if err := iso4217.Validate(currency); err != nil {
+			return err
+		}
+	}
+	if request.Reference != "" {
+		if err := iso11649.Validate(request.Reference); err != nil {
+			return err
+		}
+	}
+	switch request.Status {
+	case "", PaymentRequestOpen, PaymentRequestPaid, PaymentRequestCancelled:
+		return nil
+	}
+	return fmt.Errorf("status must be one of %s, %s or %s", PaymentRequestOpen, PaymentRequestPaid, PaymentRequestCancelled)
+}
+
+// Expired reports whether the request is past its expiry
+func (request *PaymentRequest) Expired(now time.Time) bool {
+	return request.ExpiresAt != nil && !now.Before(*request.ExpiresAt)
+}
+
+// Payable reports whether the request still waits for a payment
+func (request *PaymentRequest) Payable(now time.Time) bool {
+	return request.Status == PaymentRequestOpen && !request.Expired(now)
+}
@@ -0,0 +1,86 @@
+package model
+
+import (
+	"testing"
+	"time"
+)
+
+func TestPaymentRequestCheck(t *testing.T) {
+	tests := []struct {
+		name        string
+		request     PaymentRequest
+		expectError bool
+	}{
+		{name: "Amount with currency", request: PaymentRequest{Amount: 1250, Currency: "EUR"}},
+		{name: "Open amount", request: PaymentRequest{Description: "Donation"}},
+		{name: "Lower case currency", request: PaymentRequest{Amount: 1, Currency: "chf"}},
+		{name: "With reference", request: PaymentRequest{Reference: "RF18 5390 0754 7034"}},
+		{name: "Paid", request: PaymentRequest{Status: PaymentRequestPaid}},
+		{name: "Negative amount", request: PaymentRequest{Amount: -1, Currency: "EUR"}, expectError: true},
+		{name: "Amount without currency", request: PaymentRequest{Amount: 1250}, expectError: true},
+		{name: "Invalid currency", request: PaymentRequest{Amount: 1250, Currency: "EURO"}, expectError: true},
+		{name: "Unknown currency", request: PaymentRequest{Amount: 1250, Currency: "XYZ"}, expectError: true},
+		{name: "Invalid reference", request: PaymentRequest{Reference: "RF19539007547034"}, expectError: true},
+		{name: "Unknown status", request: PaymentRequest{Status: "pending"}, expectError: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.request.Check()
+			if tt.expectError && err == nil {
+				t.Error("Expected error but got none")
+			}
+			if !tt.expectError && err != nil {
+				t.Errorf("Unexpected error: %v", err)
+			}
+		})
+	}
+}
+
+func TestPaymentRequestCreate(t *testing.T) {
+	db := setupTestDB(t)
+	if err := db.AutoMigrate(&PaymentRequest{}); err != nil {
+		t.Fatalf("Failed to auto-migrate: %v", err)
+	}
+
+	first := PaymentRequest{OwnerID: 1, IbanID: 1, Amount: 500, Currency: "eur", Reference: "rf18 5390 0754 7034"}
+	second := PaymentRequest{OwnerID: 1, IbanID: 1}
+	for _, request := range []*PaymentRequest{&first, &second} {
+		if err := db.Create(request).Error; err != nil {
+			t.Fatalf("Failed to create payment request: %v", err)
+		}
+	}
+
+	if len(first.PublicID) != 16 || first.PublicID == second.PublicID {
+		t.Errorf("PublicID = %q and %q, want two different random IDs", first.PublicID, second.PublicID)
+	}
+	if first.Status != PaymentRequestOpen || first.Currency != "EUR" || first.Reference != "RF18539007547034" {
+		t.Errorf("Create() = %+v, want an open request with normalized currency and reference", first)
+	}
+	if err := db.Create(&PaymentRequest{OwnerID: 1, IbanID: 1, Amount: 500}).Error; err == nil {
+		t.Error("Expected an amount without currency to be rejected")
+	}
+}
+
+func TestPaymentRequestPayable(t *testing.T) {
+	now := time.Date(2025, 6, 1, 12, 0, 0, 0, time.UTC)
+	tomorrow, yesterday := now.AddDate(0, 0, 1), now.AddDate(0, 0, -1)
+
+	tests := []struct {
+		name    string
+		request PaymentRequest
+		want    bool
+	}{
+		{name: "Open without expiry", request: PaymentRequest{Status: PaymentRequestOpen}, want: true},
+		{name: "Open until tomorrow", request: PaymentRequest{Status: PaymentRequestOpen, ExpiresAt: &tomorrow}, want: true},
+		{name: "Expired", request: PaymentRequest{Status: PaymentRequestOpen, ExpiresAt: &yesterday}},
+		{name: "Paid", request: PaymentRequest{Status: PaymentRequestPaid}},
+		{name: "Cancelled", request: PaymentRequest{Status: PaymentRequestCancelled}},
+	}
+
+	for _, tt := range tests {
+		if got := tt.request.Payable(now); got != tt.want {
+			t.Errorf("%s: Payable() = %v, want %v", tt.name, got, tt.want)
+		}
+	}
+}
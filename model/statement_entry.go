@@ -0,0 +1,70 @@
+package model
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// Statement entry statuses
+const (
+	StatementEntryMatched   = "matched"
+	StatementEntryUnmatched = "unmatched"
+	StatementEntryDismissed = "dismissed"
+)
+
+// StatementEntry : an incoming payment from an imported bank statement.
+// Unmatched entries make up the owner's review list.
+type StatementEntry struct {
+	StatementEntryID uint `gorm:"primary_key"`
+	CreatedAt        time.Time
+	UpdatedAt        time.Time
+	DeletedAt        *time.Time `sql:"index"`
+	OwnerID          uint       `gorm:"not null;index"`
+	IbanID           uint       `gorm:"not null;uniqueIndex:idx_statement_entries_fingerprint"`
+	// Fingerprint identifies the entry when the same statement is imported
+	// again
+	Fingerprint      string `gorm:"type:varchar(64);not null;uniqueIndex:idx_statement_entries_fingerprint"`
+	PaymentRequestID *uint  `gorm:"index"`
+	BookingDate      time.Time
+	// Amount in cents
+	Amount        int64
+	Currency      string `gorm:"type:varchar(3)"`
+	Reference     string `gorm:"type:varchar(25)"`
+	Remittance    string
+	RemitterName  string
+	RemitterIBAN  string `gorm:"type:varchar(34)"`
+	BankReference string
+	Status        string `gorm:"type:varchar(10);not null;default:unmatched"`
+	// Note tells why the entry was not matched
+	Note string
+}
+
+// BeforeSave Callback
+func (entry *StatementEntry) BeforeSave(tx *gorm.DB) (err error) {
+	if entry.Fingerprint == "" {
+		entry.Fingerprint = entry.ComputeFingerprint()
+	}
+	switch entry.Status {
+	case "":
+		entry.Status = StatementEntryUnmatched
+	case StatementEntryMatched, StatementEntryUnmatched, StatementEntryDismissed:
+	default:
+		return fmt.Errorf("status must be one of %s, %s or %s", StatementEntryMatched, StatementEntryUnmatched, StatementEntryDismissed)
+	}
+	if entry.Status == StatementEntryMatched && entry.PaymentRequestID == nil {
+		return fmt.Errorf("a matched statement entry needs a payment request")
+	}
+	return nil
+}
+
+// ComputeFingerprint hashes what the bank reported about the entry
+func (entry *StatementEntry) ComputeFingerprint() string {
+	sum := sha256.Sum256([]byte(fmt.Sprintf("%s|%d|%s|%s|%s|%s|%s|%s",
+		entry.BookingDate.Format(time.DateOnly), entry.Amount, entry.Currency, entry.Reference,
+		entry.Remittance, entry.RemitterName, entry.RemitterIBAN, entry.BankReference)))
+	return hex.EncodeToString(sum[:])
+}
@@ -0,0 +1,46 @@
+package model
+
+import (
+	"testing"
+	"time"
+)
+
+func TestStatementEntryCreate(t *testing.T) {
+	db := setupTestDB(t)
+	if err := db.AutoMigrate(&StatementEntry{}); err != nil {
+		t.Fatalf("Failed to auto-migrate: %v", err)
+	}
+
+	booked := time.Date(2024, 3, 1, 0, 0, 0, 0, time.UTC)
+	entry := StatementEntry{OwnerID: 1, IbanID: 1, BookingDate: booked, Amount: 1250, Currency: "EUR", RemitterName: "Franz Mustermann"}
+	if err := db.Create(&entry).Error; err != nil {
+		t.Fatalf("Failed to create statement entry: %v", err)
+	}
+	if entry.Status != StatementEntryUnmatched || len(entry.Fingerprint) != 64 {
+		t.Errorf("Create() = %+v, want an unmatched entry with fingerprint", entry)
+	}
+
+	tests := []struct {
+		name        string
+		entry       StatementEntry
+		expectError bool
+	}{
+		{name: "Same entry again", entry: StatementEntry{OwnerID: 1, IbanID: 1, BookingDate: booked, Amount: 1250, Currency: "EUR", RemitterName: "Franz Mustermann"}, expectError: true},
+		{name: "Same entry on another IBAN", entry: StatementEntry{OwnerID: 1, IbanID: 2, BookingDate: booked, Amount: 1250, Currency: "EUR", RemitterName: "Franz Mustermann"}},
+		{name: "Other amount", entry: StatementEntry{OwnerID: 1, IbanID: 1, BookingDate: booked, Amount: 1251, Currency: "EUR", RemitterName: "Franz Mustermann"}},
+		{name: "Matched without request", entry: StatementEntry{OwnerID: 1, IbanID: 1, BookingDate: booked, Amount: 1, Status: StatementEntryMatched}, expectError: true},
+		{name: "Unknown status", entry: StatementEntry{OwnerID: 1, IbanID: 1, BookingDate: booked, Amount: 2, Status: "ignored"}, expectError: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := db.Create(&tt.entry).Error
+			if tt.expectError && err == nil {
+				t.Error("Expected error but got none")
+			}
+			if !tt.expectError && err != nil {
+				t.Errorf("Unexpected error: %v", err)
+			}
+		})
+	}
+}
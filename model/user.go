@@ -1,9 +1,12 @@
 package model
 
 import (
+	"slices"
+	"strings"
 	"time"
 
 	"golang.org/x/crypto/bcrypt"
+	"gorm.io/gorm"
 )
 
 // User : Model with injected fields `ID`, `CreatedAt`, `UpdatedAt`
@@ -23,6 +26,41 @@ type User struct {
 	Verified  bool
 	Active    bool
 	Ibans     []*Iban `gorm:"polymorphic:Owner;"`
+
+	// Postal address, printed as the creditor on Swiss QR-bills
+	Street         string `gorm:"type:varchar(70)"`
+	BuildingNumber string `gorm:"type:varchar(16)"`
+	PostalCode     string `gorm:"type:varchar(16)"`
+	Town           string `gorm:"type:varchar(35)"`
+	Country        string `gorm:"type:varchar(2)"`
+}
+
+// reservedHandles are the first path segments of the server's own routes
+// in main.go and of the dashboard pages of the frontend, which
+// /:userHandle/:ibanHandle pages cannot use
+var reservedHandles = []string{"g", "api", "assets", "auth", "dashboard", "graph"}
+
+// ReservedHandle reports whether a user handle clashes with a route
+func ReservedHandle(handle string) bool {
+	return slices.Contains(reservedHandles, strings.ToLower(strings.TrimSpace(handle)))
+}
+
+// UsersWithReservedHandles finds the users who signed up with a handle
+// before it was reserved; the routes hide their public pages until they
+// change it
+func UsersWithReservedHandles(db *gorm.DB) ([]User, error) {
+	var users []User
+	err := db.Where("LOWER(handle) IN ?", reservedHandles).Order("user_id").Find(&users).Error
+	return users, err
+}
+
+// DisplayName : the account holder name shown to payers, the handle if the
+// user has no name
+func (user *User) DisplayName() string {
+	if name := strings.TrimSpace(user.FirstName + " " + user.LastName); name != "" {
+		return name
+	}
+	return user.Handle
 }
 
 // HashPassword : hashing the password
@@ -2,6 +2,9 @@ package model
 
 import (
 	"testing"
+
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
 )
 
 func TestUserHashPassword(t *testing.T) {
@@ -165,3 +168,62 @@ func TestUserStructFields(t *testing.T) {
 		t.Error("Active should be true")
 	}
 }
+
+func TestUserDisplayName(t *testing.T) {
+	tests := []struct {
+		user User
+		want string
+	}{
+		{user: User{FirstName: "Franz", LastName: "Mustermann", Handle: "franz"}, want: "Franz Mustermann"},
+		{user: User{FirstName: "Cher", Handle: "cher"}, want: "Cher"},
+		{user: User{Handle: "anonymous"}, want: "anonymous"},
+	}
+
+	for _, tt := range tests {
+		if got := tt.user.DisplayName(); got != tt.want {
+			t.Errorf("DisplayName() = %s, want %s", got, tt.want)
+		}
+	}
+}
+
+func TestReservedHandle(t *testing.T) {
+	tests := []struct {
+		handle string
+		want   bool
+	}{
+		{handle: "g", want: true},
+		{handle: "assets", want: true},
+		{handle: " Dashboard ", want: true},
+		{handle: "franz", want: false},
+		{handle: "gallery", want: false},
+	}
+
+	for _, tt := range tests {
+		if got := ReservedHandle(tt.handle); got != tt.want {
+			t.Errorf("ReservedHandle(%q) = %v, want %v", tt.handle, got, tt.want)
+		}
+	}
+}
+
+func TestUsersWithReservedHandles(t *testing.T) {
+	db, err := gorm.Open(sqlite.Open(":memory:"), &gorm.Config{})
+	if err != nil {
+		t.Fatalf("Failed to connect to test database: %v", err)
+	}
+	if err := db.AutoMigrate(&User{}); err != nil {
+		t.Fatalf("Failed to auto-migrate: %v", err)
+	}
+	for _, handle := range []string{"franz", "G", "assets"} {
+		if err := db.Create(&User{Email: handle + "@example.com", Handle: handle}).Error; err != nil {
+			t.Fatalf("Failed to create user: %v", err)
+		}
+	}
+
+	users, err := UsersWithReservedHandles(db)
+	if err != nil {
+		t.Fatalf("UsersWithReservedHandles() returned unexpected error: %v", err)
+	}
+	if len(users) != 2 || users[0].Handle != "G" || users[1].Handle != "assets" {
+		t.Errorf("UsersWithReservedHandles() = %+v, want G and assets", users)
+	}
+}
@@ -0,0 +1,299 @@
+// Package pain001 builds ISO 20022 customer credit transfer initiations
+// (pain.001.001.09) for SEPA credit transfers, the batch payment files
+// banks accept for upload.
+package pain001
+
+import (
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"regexp"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/tapsilat/iban.im/iso11649"
+	"github.com/tapsilat/iban.im/iso13616"
+	"github.com/tapsilat/iban.im/iso9362"
+)
+
+// Namespace of the pain.001.001.09 schema.
+const Namespace = "urn:iso:std:iso:20022:tech:xsd:pain.001.001.09"
+
+// Currency is the only currency of SEPA credit transfers.
+const Currency = "EUR"
+
+// MaxAmount is the largest SEPA credit transfer, in euro cents.
+const MaxAmount = 99999999999
+
+// Errors returned by Validate and XML. They are wrapped with the offending
+// detail, so use errors.Is to tell them apart.
+var (
+	ErrNotSEPA  = errors.New("SEPA credit transfers are only available for SEPA IBANs")
+	ErrAmount   = errors.New("amount must be between 0.01 and 999999999.99 EUR")
+	ErrCurrency = errors.New("SEPA credit transfers are only available in EUR")
+	ErrField    = errors.New("invalid pain.001 field")
+	ErrEmpty    = errors.New("a batch needs at least one transfer")
+)
+
+// identifier is the restricted character set of SEPA message and
+// end-to-end identifications.
+var identifier = regexp.MustCompile(`^[A-Za-z0-9/\-?:().,'+ ]{1,35}$`)
+
+// Party is the debtor or a creditor of a transfer.
+type Party struct {
+	Name string
+	IBAN string
+	// BIC is optional within SEPA.
+	BIC string
+}
+
+// Transfer is a single credit transfer of a batch.
+type Transfer struct {
+	// EndToEndID is passed on to the creditor, "NOTPROVIDED" if empty.
+	EndToEndID string
+	Creditor   Party
+	// Amount in euro cents.
+	Amount int64
+	// Currency of the creditor's account, EUR if empty; others are rejected
+	// rather than paid as euros.
+	Currency string
+	// Reference is a structured ISO 11649 creditor reference; it cannot be
+	// combined with Text.
+	Reference string
+	// Text is the unstructured remittance information.
+	Text string
+}
+
+// Batch is a payment information block with the transfers from one debtor
+// account, executed on the same day.
+type Batch struct {
+	MessageID     string
+	Created       time.Time
+	Debtor        Party
+	ExecutionDate time.Time
+	Transfers     []Transfer
+}
+
+// Validate checks that party has a name, a SEPA IBAN and, if given, a
+// valid BIC of a bank that may hold the IBAN.
+func (p Party) Validate() error {
+	if err := checkField("name", p.Name, 70); err != nil {
+		return err
+	}
+	if strings.TrimSpace(p.Name) == "" {
+		return fmt.Errorf("%w: name is required", ErrField)
+	}
+	if err := iso13616.Validate(p.IBAN); err != nil {
+		return err
+	}
+	if !iso13616.IsSEPA(p.IBAN) {
+		return fmt.Errorf("%w: %s", ErrNotSEPA, iso13616.PrintFormat(p.IBAN))
+	}
+	if p.BIC == "" {
+		return nil
+	}
+	if err := iso9362.Validate(p.BIC); err != nil {
+		return err
+	}
+	return iso9362.CheckCountry(p.BIC, iso13616.CountryCode(p.IBAN))
+}
+
+// Validate checks the creditor, amount and remittance information of t.
+func (t Transfer) Validate() error {
+	if err := t.Creditor.Validate(); err != nil {
+		return err
+	}
+	if t.Currency != "" && !strings.EqualFold(t.Currency, Currency) {
+		return fmt.Errorf("%w, the account is in %s", ErrCurrency, strings.ToUpper(t.Currency))
+	}
+	if t.Amount <= 0 || t.Amount > MaxAmount {
+		return ErrAmount
+	}
+	if t.EndToEndID != "" && !identifier.MatchString(t.EndToEndID) {
+		return fmt.Errorf("%w: end-to-end ID must be up to 35 letters, digits or /-?:().,'+", ErrField)
+	}
+	if t.Reference != "" && t.Text != "" {
+		return fmt.Errorf("%w: a transfer carries either a reference or a remittance text", ErrField)
+	}
+	if t.Reference != "" {
+		return iso11649.Validate(t.Reference)
+	}
+	return checkField("remittance text", t.Text, 140)
+}
+
+// Validate checks the debtor and every transfer of b. Errors of transfers
+// name their position, starting at 1.
+func (b Batch) Validate() error {
+	if !identifier.MatchString(b.MessageID) {
+		return fmt.Errorf("%w: message ID must be 1 to 35 letters, digits or /-?:().,'+", ErrField)
+	}
+	if err := b.Debtor.Validate(); err != nil {
+		return fmt.Errorf("debtor: %w", err)
+	}
+	if len(b.Transfers) == 0 {
+		return ErrEmpty
+	}
+	for i, t := range b.Transfers {
+		if err := t.Validate(); err != nil {
+			return fmt.Errorf("transfer %d: %w", i+1, err)
+		}
+	}
+	return nil
+}
+
+// ControlSum is the total of all transfers in cents.
+func (b Batch) ControlSum() int64 {
+	var sum int64
+	for _, t := range b.Transfers {
+		sum += t.Amount
+	}
+	return sum
+}
+
+// XML validates b and renders it as a pain.001.001.09 document.
+func (b Batch) XML() ([]byte, error) {
+	if err := b.Validate(); err != nil {
+		return nil, err
+	}
+
+	count := fmt.Sprint(len(b.Transfers))
+	sum := amount(b.ControlSum())
+	info := paymentInformation{
+		ID:              b.MessageID,
+		Method:          "TRF",
+		BatchBooking:    true,
+		NumberOfTxs:     count,
+		ControlSum:      sum,
+		ServiceLevel:    "SEPA",
+		ExecutionDate:   b.ExecutionDate.Format(time.DateOnly),
+		DebtorName:      strings.TrimSpace(b.Debtor.Name),
+		DebtorIBAN:      iso13616.Normalize(b.Debtor.IBAN),
+		DebtorAgent:     agentOf(b.Debtor.BIC),
+		ChargeBearer:    "SLEV",
+		CreditTransfers: make([]creditTransfer, 0, len(b.Transfers)),
+	}
+	if info.DebtorAgent == nil {
+		info.DebtorAgent = &agent{Other: &otherID{ID: "NOTPROVIDED"}}
+	}
+	for _, t := range b.Transfers {
+		tx := creditTransfer{
+			EndToEndID:    t.EndToEndID,
+			Amount:        instructedAmount{Currency: Currency, Value: amount(t.Amount)},
+			CreditorAgent: agentOf(t.Creditor.BIC),
+			CreditorName:  strings.TrimSpace(t.Creditor.Name),
+			CreditorIBAN:  iso13616.Normalize(t.Creditor.IBAN),
+		}
+		if tx.EndToEndID == "" {
+			tx.EndToEndID = "NOTPROVIDED"
+		}
+		switch {
+		case t.Reference != "":
+			tx.Remittance = &remittance{Reference: &creditorReference{Code: "SCOR", Issuer: "ISO", Ref: iso11649.Normalize(t.Reference)}}
+		case t.Text != "":
+			tx.Remittance = &remittance{Text: t.Text}
+		}
+		info.CreditTransfers = append(info.CreditTransfers, tx)
+	}
+
+	doc := document{
+		Namespace: Namespace,
+		Header: groupHeader{
+			MessageID:   b.MessageID,
+			Created:     b.Created.UTC().Format("2006-01-02T15:04:05"),
+			NumberOfTxs: count,
+			ControlSum:  sum,
+			Initiator:   strings.TrimSpace(b.Debtor.Name),
+		},
+		Payment: info,
+	}
+	out, err := xml.MarshalIndent(doc, "", "  ")
+	if err != nil {
+		return nil, err
+	}
+	return append([]byte(xml.Header), out...), nil
+}
+
+func checkField(label, value string, max int) error {
+	if utf8.RuneCountInString(value) > max {
+		return fmt.Errorf("%w: %s must be at most %d characters", ErrField, label, max)
+	}
+	return nil
+}
+
+func amount(cents int64) string {
+	return fmt.Sprintf("%d.%02d", cents/100, cents%100)
+}
+
+func agentOf(bic string) *agent {
+	if bic == "" {
+		return nil
+	}
+	return &agent{BIC: iso9362.Normalize(bic)}
+}
+
+type document struct {
+	XMLName   xml.Name           `xml:"Document"`
+	Namespace string             `xml:"xmlns,attr"`
+	Header    groupHeader        `xml:"CstmrCdtTrfInitn>GrpHdr"`
+	Payment   paymentInformation `xml:"CstmrCdtTrfInitn>PmtInf"`
+}
+
+type groupHeader struct {
+	MessageID   string `xml:"MsgId"`
+	Created     string `xml:"CreDtTm"`
+	NumberOfTxs string `xml:"NbOfTxs"`
+	ControlSum  string `xml:"CtrlSum"`
+	Initiator   string `xml:"InitgPty>Nm"`
+}
+
+type paymentInformation struct {
+	ID              string           `xml:"PmtInfId"`
+	Method          string           `xml:"PmtMtd"`
+	BatchBooking    bool             `xml:"BtchBookg"`
+	NumberOfTxs     string           `xml:"NbOfTxs"`
+	ControlSum      string           `xml:"CtrlSum"`
+	ServiceLevel    string           `xml:"PmtTpInf>SvcLvl>Cd"`
+	ExecutionDate   string           `xml:"ReqdExctnDt>Dt"`
+	DebtorName      string           `xml:"Dbtr>Nm"`
+	DebtorIBAN      string           `xml:"DbtrAcct>Id>IBAN"`
+	DebtorAgent     *agent           `xml:"DbtrAgt"`
+	ChargeBearer    string           `xml:"ChrgBr"`
+	CreditTransfers []creditTransfer `xml:"CdtTrfTxInf"`
+}
+
+// agent is a financial institution by BIC, or "NOTPROVIDED" for debtor
+// agents without one
+type agent struct {
+	BIC   string   `xml:"FinInstnId>BICFI,omitempty"`
+	Other *otherID `xml:"FinInstnId>Othr,omitempty"`
+}
+
+type otherID struct {
+	ID string `xml:"Id"`
+}
+
+type creditTransfer struct {
+	EndToEndID    string           `xml:"PmtId>EndToEndId"`
+	Amount        instructedAmount `xml:"Amt>InstdAmt"`
+	CreditorAgent *agent           `xml:"CdtrAgt,omitempty"`
+	CreditorName  string           `xml:"Cdtr>Nm"`
+	CreditorIBAN  string           `xml:"CdtrAcct>Id>IBAN"`
+	Remittance    *remittance      `xml:"RmtInf,omitempty"`
+}
+
+type instructedAmount struct {
+	Currency string `xml:"Ccy,attr"`
+	Value    string `xml:",chardata"`
+}
+
+type remittance struct {
+	Text      string             `xml:"Ustrd,omitempty"`
+	Reference *creditorReference `xml:"Strd>CdtrRefInf,omitempty"`
+}
+
+type creditorReference struct {
+	Code   string `xml:"Tp>CdOrPrtry>Cd"`
+	Issuer string `xml:"Tp>Issr"`
+	Ref    string `xml:"Ref"`
+}
@@ -0,0 +1,117 @@
+package pain001
+
+import (
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/tapsilat/iban.im/iso11649"
+	"github.com/tapsilat/iban.im/iso13616"
+	"github.com/tapsilat/iban.im/iso9362"
+)
+
+func testBatch() Batch {
+	return Batch{
+		MessageID:     "IBANIM-20240301-1",
+		Created:       time.Date(2024, 3, 1, 9, 30, 0, 0, time.UTC),
+		Debtor:        Party{Name: "Acme GmbH", IBAN: "DE89 3704 0044 0532 0130 00", BIC: "cobadeff"},
+		ExecutionDate: time.Date(2024, 3, 4, 0, 0, 0, 0, time.UTC),
+		Transfers: []Transfer{
+			{EndToEndID: "INV-1", Creditor: Party{Name: "Franz Mustermann", IBAN: "AT611904300234573201", BIC: "BKAUATWW"}, Amount: 1250, Reference: "rf18 5390 0754 7034"},
+			{Creditor: Party{Name: "Erika Musterfrau", IBAN: "FR1420041010050500013M02606"}, Amount: 4000, Text: "Rent & fees"},
+		},
+	}
+}
+
+func TestXML(t *testing.T) {
+	out, err := testBatch().XML()
+	if err != nil {
+		t.Fatalf("XML() returned unexpected error: %v", err)
+	}
+	doc := string(out)
+	for _, want := range []string{
+		`<?xml version="1.0" encoding="UTF-8"?>`,
+		`<Document xmlns="urn:iso:std:iso:20022:tech:xsd:pain.001.001.09">`,
+		`<MsgId>IBANIM-20240301-1</MsgId>`,
+		`<CreDtTm>2024-03-01T09:30:00</CreDtTm>`,
+		`<NbOfTxs>2</NbOfTxs>`,
+		`<CtrlSum>52.50</CtrlSum>`,
+		`<ReqdExctnDt>`, `<Dt>2024-03-04</Dt>`,
+		`<IBAN>DE89370400440532013000</IBAN>`,
+		`<BICFI>COBADEFF</BICFI>`,
+		`<EndToEndId>INV-1</EndToEndId>`,
+		`<InstdAmt Ccy="EUR">12.50</InstdAmt>`,
+		`<Cd>SCOR</Cd>`, `<Issr>ISO</Issr>`, `<Ref>RF18539007547034</Ref>`,
+		`<EndToEndId>NOTPROVIDED</EndToEndId>`,
+		`<Ustrd>Rent &amp; fees</Ustrd>`,
+		`<ChrgBr>SLEV</ChrgBr>`,
+	} {
+		if !strings.Contains(doc, want) {
+			t.Errorf("XML() lacks %s:\n%s", want, doc)
+		}
+	}
+	if strings.Contains(doc, "<Othr>") {
+		t.Errorf("XML() has an other ID next to the BIC:\n%s", doc)
+	}
+	// The second creditor has no BIC and thus no agent
+	if strings.Count(doc, "<CdtrAgt>") != 1 {
+		t.Errorf("XML() has %d creditor agents, want 1", strings.Count(doc, "<CdtrAgt>"))
+	}
+}
+
+func TestXMLWithoutDebtorBIC(t *testing.T) {
+	batch := testBatch()
+	batch.Debtor.BIC = ""
+	out, err := batch.XML()
+	if err != nil {
+		t.Fatalf("XML() returned unexpected error: %v", err)
+	}
+	if !strings.Contains(string(out), "<DbtrAgt>\n        <FinInstnId>\n          <Othr>\n            <Id>NOTPROVIDED</Id>") {
+		t.Errorf("XML() lacks the NOTPROVIDED debtor agent:\n%s", out)
+	}
+}
+
+func TestValidate(t *testing.T) {
+	tests := []struct {
+		name    string
+		modify  func(*Batch)
+		wantErr error
+		prefix  string
+	}{
+		{name: "Valid", modify: func(b *Batch) {}},
+		{name: "No transfers", modify: func(b *Batch) { b.Transfers = nil }, wantErr: ErrEmpty},
+		{name: "Message ID too long", modify: func(b *Batch) { b.MessageID = strings.Repeat("X", 36) }, wantErr: ErrField},
+		{name: "Debtor outside SEPA", modify: func(b *Batch) { b.Debtor = Party{Name: "Acme", IBAN: "TR320010009999901234567890"} }, wantErr: ErrNotSEPA, prefix: "debtor: "},
+		{name: "Invalid creditor IBAN", modify: func(b *Batch) { b.Transfers[1].Creditor.IBAN = "DE89370400440532013001" }, wantErr: iso13616.ErrChecksum, prefix: "transfer 2: "},
+		{name: "Creditor outside SEPA", modify: func(b *Batch) { b.Transfers[1].Creditor.IBAN = "BR1800360305000010009795493C1" }, wantErr: ErrNotSEPA, prefix: "transfer 2: "},
+		{name: "BIC of another country", modify: func(b *Batch) { b.Transfers[0].Creditor.BIC = "COBADEFF" }, wantErr: iso9362.ErrCountry, prefix: "transfer 1: "},
+		{name: "Zero amount", modify: func(b *Batch) { b.Transfers[0].Amount = 0 }, wantErr: ErrAmount},
+		{name: "Account in EUR", modify: func(b *Batch) { b.Transfers[0].Currency = "eur" }},
+		{name: "Account in another currency", modify: func(b *Batch) { b.Transfers[1].Currency = "CHF" }, wantErr: ErrCurrency, prefix: "transfer 2: "},
+		{name: "Invalid reference", modify: func(b *Batch) { b.Transfers[0].Reference = "RF19539007547034" }, wantErr: iso11649.ErrChecksum},
+		{name: "Reference and text", modify: func(b *Batch) { b.Transfers[0].Text = "Dinner" }, wantErr: ErrField},
+		{name: "End-to-end ID with invalid characters", modify: func(b *Batch) { b.Transfers[0].EndToEndID = "INV_1" }, wantErr: ErrField},
+		{name: "Creditor without name", modify: func(b *Batch) { b.Transfers[1].Creditor.Name = " " }, wantErr: ErrField},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			batch := testBatch()
+			tt.modify(&batch)
+			err := batch.Validate()
+			if tt.wantErr == nil {
+				if err != nil {
+					t.Errorf("Validate() returned unexpected error: %v", err)
+				}
+				return
+			}
+			if !errors.Is(err, tt.wantErr) {
+				t.Errorf("Validate() error = %v, want %v", err, tt.wantErr)
+			}
+			if err != nil && !strings.HasPrefix(err.Error(), tt.prefix) {
+				t.Errorf("Validate() error = %v, want prefix %q", err, tt.prefix)
+			}
+		})
+	}
+}
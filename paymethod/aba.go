@@ -0,0 +1,70 @@
+package paymethod
+
+import "fmt"
+
+// abaMethod is a US account: a nine digit ABA routing number of the bank
+// and an account number of up to 17 digits
+type abaMethod struct{}
+
+func (abaMethod) Label() string {
+	return "US bank account"
+}
+
+func (abaMethod) Validate(a Account) error {
+	routing, ok := digits(a.Routing)
+	if !ok || len(routing) != 9 {
+		return fmt.Errorf("%w: routing number must be 9 digits", ErrFormat)
+	}
+	if !validRoutingPrefix(routing) {
+		return fmt.Errorf("%w: routing number %s has no Federal Reserve district", ErrFormat, routing)
+	}
+	if !abaChecksum(routing) {
+		return fmt.Errorf("%w: routing number %s", ErrChecksum, routing)
+	}
+	if number, ok := digits(a.Number); !ok || len(number) < 4 || len(number) > 17 {
+		return fmt.Errorf("%w: US account number must be 4 to 17 digits", ErrFormat)
+	}
+	return nil
+}
+
+// validRoutingPrefix checks the first two digits: 00 for the government,
+// 01-12 for the Federal Reserve districts, 21-32 for thrifts, 61-72 for
+// electronic transactions and 80 for traveler's checks
+func validRoutingPrefix(routing string) bool {
+	prefix := int(routing[0]-'0')*10 + int(routing[1]-'0')
+	return prefix <= 12 || (prefix >= 21 && prefix <= 32) || (prefix >= 61 && prefix <= 72) || prefix == 80
+}
+
+// abaChecksum weighs the digits 3, 7, 1 in turn; the sum is a multiple of
+// ten
+func abaChecksum(routing string) bool {
+	weights := [3]int{3, 7, 1}
+	sum := 0
+	for i := 0; i < len(routing); i++ {
+		sum += int(routing[i]-'0') * weights[i%3]
+	}
+	return sum%10 == 0
+}
+
+func (abaMethod) Normalize(a Account) Account {
+	a.Routing, _ = digits(a.Routing)
+	a.Number, _ = digits(a.Number)
+	return a
+}
+
+func (m abaMethod) Print(a Account) string {
+	a = m.Normalize(a)
+	return a.Routing + " " + a.Number
+}
+
+func (m abaMethod) Fields(a Account) []Field {
+	a = m.Normalize(a)
+	return []Field{
+		{Label: "Routing number (ABA)", Value: a.Routing, Copy: a.Routing},
+		{Label: "Account number", Value: a.Number, Copy: a.Number},
+	}
+}
+
+func (abaMethod) URI(Account) string {
+	return ""
+}
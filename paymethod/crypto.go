@@ -0,0 +1,236 @@
+package paymethod
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"math/big"
+	"strings"
+
+	"golang.org/x/crypto/sha3"
+)
+
+// Crypto networks
+const (
+	Bitcoin  = "bitcoin"
+	Ethereum = "ethereum"
+)
+
+// cryptoMethod is a Bitcoin or Ethereum address, paid with a BIP 21 or
+// EIP 681 URI. Only mainnet addresses are accepted.
+type cryptoMethod struct{}
+
+func (cryptoMethod) Label() string {
+	return "Crypto address"
+}
+
+func (cryptoMethod) Validate(a Account) error {
+	address := strings.TrimSpace(a.Number)
+	switch Network(address) {
+	case Bitcoin:
+		if strings.HasPrefix(strings.ToLower(address), "bc1") {
+			return checkBech32(address)
+		}
+		return checkBase58(address)
+	case Ethereum:
+		return checkEIP55(address)
+	}
+	return fmt.Errorf("%w: expected a Bitcoin or Ethereum address", ErrFormat)
+}
+
+// Network tells the network of a crypto address by its form, or returns an
+// empty string.
+func Network(address string) string {
+	address = strings.TrimSpace(address)
+	switch {
+	case strings.HasPrefix(address, "0x") && len(address) == 42:
+		return Ethereum
+	case strings.HasPrefix(strings.ToLower(address), "bc1"),
+		strings.HasPrefix(address, "1"),
+		strings.HasPrefix(address, "3"):
+		return Bitcoin
+	}
+	return ""
+}
+
+// Normalize writes bech32 addresses in lower case and Ethereum addresses
+// with their EIP 55 checksum
+func (cryptoMethod) Normalize(a Account) Account {
+	address := strings.TrimSpace(a.Number)
+	switch {
+	case Network(address) == Ethereum:
+		address = eip55(address)
+	case strings.HasPrefix(strings.ToLower(address), "bc1"):
+		address = strings.ToLower(address)
+	}
+	return Account{Number: address}
+}
+
+func (m cryptoMethod) Print(a Account) string {
+	return m.Normalize(a).Number
+}
+
+func (m cryptoMethod) Fields(a Account) []Field {
+	address := m.Normalize(a).Number
+	label := "Crypto address"
+	switch Network(address) {
+	case Bitcoin:
+		label = "Bitcoin address"
+	case Ethereum:
+		label = "Ethereum address"
+	}
+	return []Field{{Label: label, Value: address, Copy: address}}
+}
+
+func (m cryptoMethod) URI(a Account) string {
+	address := m.Normalize(a).Number
+	if network := Network(address); network != "" {
+		return network + ":" + address
+	}
+	return ""
+}
+
+const base58Alphabet = "123456789ABCDEFGHJKLMNPQRSTUVWXYZabcdefghijkmnopqrstuvwxyz"
+
+// checkBase58 validates a legacy P2PKH or P2SH address: a version byte, the
+// 20 byte hash and the first four bytes of its double SHA-256
+func checkBase58(address string) error {
+	n := new(big.Int)
+	for _, c := range address {
+		i := strings.IndexRune(base58Alphabet, c)
+		if i < 0 {
+			return fmt.Errorf("%w: %q is not a base58 character", ErrFormat, c)
+		}
+		n.Mul(n, big.NewInt(58))
+		n.Add(n, big.NewInt(int64(i)))
+	}
+	// Leading ones stand for zero bytes
+	zeros := len(address) - len(strings.TrimLeft(address, "1"))
+	decoded := append(make([]byte, zeros), n.Bytes()...)
+	if len(decoded) != 25 || (decoded[0] != 0x00 && decoded[0] != 0x05) {
+		return fmt.Errorf("%w: not a Bitcoin mainnet address", ErrFormat)
+	}
+	first := sha256.Sum256(decoded[:21])
+	second := sha256.Sum256(first[:])
+	if !bytes.Equal(second[:4], decoded[21:]) {
+		return fmt.Errorf("%w: Bitcoin address %s", ErrChecksum, address)
+	}
+	return nil
+}
+
+const bech32Charset = "qpzry9x8gf2tvdw0s3jn54khce6mua7l"
+
+// Checksum constants of BIP 173 (segwit v0) and BIP 350 (v1 and later)
+const (
+	bech32Const  = 1
+	bech32mConst = 0x2bc830a3
+)
+
+func bech32Polymod(values []byte) uint32 {
+	generator := [5]uint32{0x3b6a57b2, 0x26508e6d, 0x1ea119fa, 0x3d4233dd, 0x2a1462b3}
+	chk := uint32(1)
+	for _, v := range values {
+		top := chk >> 25
+		chk = (chk&0x1ffffff)<<5 ^ uint32(v)
+		for i := 0; i < 5; i++ {
+			if (top>>i)&1 == 1 {
+				chk ^= generator[i]
+			}
+		}
+	}
+	return chk
+}
+
+// checkBech32 validates a segwit address: the "bc" prefix, the witness
+// version and program, and the bech32 or bech32m checksum
+func checkBech32(address string) error {
+	if address != strings.ToLower(address) && address != strings.ToUpper(address) {
+		return fmt.Errorf("%w: bech32 addresses may not mix cases", ErrFormat)
+	}
+	address = strings.ToLower(address)
+	if len(address) > 90 || !strings.HasPrefix(address, "bc1") {
+		return fmt.Errorf("%w: not a Bitcoin mainnet address", ErrFormat)
+	}
+	values := []byte{3, 3, 0, 2, 3} // "bc" expanded
+	data := make([]byte, 0, len(address)-3)
+	for _, c := range address[3:] {
+		i := strings.IndexRune(bech32Charset, c)
+		if i < 0 {
+			return fmt.Errorf("%w: %q is not a bech32 character", ErrFormat, c)
+		}
+		data = append(data, byte(i))
+	}
+	if len(data) < 7 {
+		return fmt.Errorf("%w: bech32 address is too short", ErrFormat)
+	}
+	version := data[0]
+	want := uint32(bech32Const)
+	if version > 0 {
+		want = bech32mConst
+	}
+	if bech32Polymod(append(values, data...)) != want {
+		return fmt.Errorf("%w: Bitcoin address %s", ErrChecksum, address)
+	}
+	program, ok := convertBits(data[1 : len(data)-6])
+	switch {
+	case version > 16 || !ok || len(program) < 2 || len(program) > 40:
+		return fmt.Errorf("%w: invalid witness program", ErrFormat)
+	case version == 0 && len(program) != 20 && len(program) != 32:
+		return fmt.Errorf("%w: invalid witness program", ErrFormat)
+	}
+	return nil
+}
+
+// convertBits regroups 5 bit values into bytes; left over bits must be
+// zero padding
+func convertBits(data []byte) ([]byte, bool) {
+	var out []byte
+	acc, bits := 0, 0
+	for _, v := range data {
+		acc = acc<<5 | int(v)
+		bits += 5
+		if bits >= 8 {
+			bits -= 8
+			out = append(out, byte(acc>>bits))
+			acc &= 1<<bits - 1
+		}
+	}
+	return out, bits < 5 && acc == 0
+}
+
+// checkEIP55 validates an Ethereum address; mixed case ones must carry the
+// EIP 55 checksum, all lower or upper case ones carry none
+func checkEIP55(address string) error {
+	hexPart := address[2:]
+	if _, err := hex.DecodeString(hexPart); err != nil {
+		return fmt.Errorf("%w: Ethereum address must be 40 hex digits", ErrFormat)
+	}
+	if hexPart == strings.ToLower(hexPart) || hexPart == strings.ToUpper(hexPart) {
+		return nil
+	}
+	if eip55(address) != address {
+		return fmt.Errorf("%w: Ethereum address %s", ErrChecksum, address)
+	}
+	return nil
+}
+
+// eip55 writes the letters of an Ethereum address in upper case where the
+// Keccak-256 hash of the lower case address has a nibble of 8 or more
+func eip55(address string) string {
+	lower := strings.ToLower(address[2:])
+	h := sha3.NewLegacyKeccak256()
+	h.Write([]byte(lower))
+	hash := h.Sum(nil)
+	out := []byte(lower)
+	for i, c := range out {
+		nibble := hash[i/2] >> 4
+		if i%2 == 1 {
+			nibble = hash[i/2] & 0x0f
+		}
+		if c >= 'a' && c <= 'f' && nibble >= 8 {
+			out[i] = c - 'a' + 'A'
+		}
+	}
+	return "0x" + string(out)
+}
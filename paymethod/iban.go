@@ -0,0 +1,33 @@
+package paymethod
+
+import (
+	"github.com/tapsilat/iban.im/iso13616"
+	"github.com/tapsilat/iban.im/payto"
+)
+
+// ibanMethod is an ISO 13616 IBAN, paid with a payto URI
+type ibanMethod struct{}
+
+func (ibanMethod) Label() string {
+	return "IBAN"
+}
+
+func (ibanMethod) Validate(a Account) error {
+	return iso13616.Validate(a.Number)
+}
+
+func (ibanMethod) Normalize(a Account) Account {
+	return Account{Number: iso13616.Normalize(a.Number)}
+}
+
+func (ibanMethod) Print(a Account) string {
+	return iso13616.PrintFormat(a.Number)
+}
+
+func (m ibanMethod) Fields(a Account) []Field {
+	return []Field{{Label: "IBAN", Value: m.Print(a), Copy: iso13616.Normalize(a.Number)}}
+}
+
+func (ibanMethod) URI(a Account) string {
+	return payto.URI{IBAN: a.Number}.String()
+}
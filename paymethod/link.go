@@ -0,0 +1,89 @@
+package paymethod
+
+import (
+	"fmt"
+	"net/url"
+	"strings"
+)
+
+// MaxLinkLength is the longest payment link that can be stored
+const MaxLinkLength = 100
+
+// linkProviders are the hosts of the payment pages that are accepted as
+// links, so that a public page cannot send payers to an arbitrary site
+var linkProviders = map[string]string{
+	"paypal.me":      "PayPal.me",
+	"revolut.me":     "Revolut",
+	"monzo.me":       "Monzo",
+	"venmo.com":      "Venmo",
+	"cash.app":       "Cash App",
+	"wise.com":       "Wise",
+	"buy.stripe.com": "Stripe",
+}
+
+// linkMethod is a hosted payment page such as paypal.me/name
+type linkMethod struct{}
+
+func (linkMethod) Label() string {
+	return "Payment link"
+}
+
+// parseLink reads a link with or without the https scheme
+func parseLink(s string) (*url.URL, error) {
+	s = strings.TrimSpace(s)
+	if !strings.Contains(s, "://") {
+		s = "https://" + s
+	}
+	u, err := url.Parse(s)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrFormat, err)
+	}
+	u.Host = strings.TrimPrefix(strings.ToLower(u.Host), "www.")
+	u.Fragment = ""
+	return u, nil
+}
+
+func (linkMethod) Validate(a Account) error {
+	u, err := parseLink(a.Number)
+	if err != nil {
+		return err
+	}
+	if u.Scheme != "https" {
+		return fmt.Errorf("%w: payment links must use https", ErrLink)
+	}
+	if _, ok := linkProviders[u.Host]; !ok {
+		return fmt.Errorf("%w: %s is not a supported payment provider", ErrLink, u.Host)
+	}
+	if strings.Trim(u.Path, "/") == "" || u.User != nil {
+		return fmt.Errorf("%w: the link must lead to your payment page", ErrLink)
+	}
+	if len(u.String()) > MaxLinkLength {
+		return fmt.Errorf("%w: payment links may be at most %d characters", ErrLink, MaxLinkLength)
+	}
+	return nil
+}
+
+func (linkMethod) Normalize(a Account) Account {
+	u, err := parseLink(a.Number)
+	if err != nil {
+		return Account{Number: strings.TrimSpace(a.Number)}
+	}
+	return Account{Number: u.String()}
+}
+
+func (m linkMethod) Print(a Account) string {
+	return m.Normalize(a).Number
+}
+
+func (m linkMethod) Fields(a Account) []Field {
+	link := m.Normalize(a).Number
+	label := "Payment link"
+	if u, err := url.Parse(link); err == nil && linkProviders[u.Host] != "" {
+		label = linkProviders[u.Host]
+	}
+	return []Field{{Label: label, Value: link, Copy: link}}
+}
+
+func (m linkMethod) URI(a Account) string {
+	return m.Normalize(a).Number
+}
@@ -0,0 +1,100 @@
+// Package paymethod validates and describes the accounts a public page can
+// be paid to: IBANs, UK sort code accounts, US bank accounts, crypto
+// addresses and hosted payment links.
+package paymethod
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// Payment method types
+const (
+	IBAN     = "iban"
+	SortCode = "sortcode"
+	ABA      = "aba"
+	Crypto   = "crypto"
+	Link     = "link"
+)
+
+// Validation errors. The methods wrap them with the offending detail, so use
+// errors.Is to tell them apart. IBANs fail with the errors of iso13616.
+var (
+	ErrType     = errors.New("unknown payment method")
+	ErrFormat   = errors.New("invalid account format")
+	ErrChecksum = errors.New("account checksum does not match")
+	ErrLink     = errors.New("unsupported payment link")
+)
+
+// Account is where a payment method pays into.
+type Account struct {
+	// Number is the IBAN, account number, crypto address or link
+	Number string
+	// Routing is the UK sort code or the US ABA routing number
+	Routing string
+}
+
+// Field is a labelled detail that payers need, e.g. the sort code.
+type Field struct {
+	Label string
+	// Value is the detail as printed, Copy as payers paste it
+	Value string
+	Copy  string
+}
+
+// Method validates and renders the accounts of one type.
+type Method interface {
+	// Label names the type, e.g. "UK bank account"
+	Label() string
+	// Validate checks the account as entered, with spaces and separators
+	Validate(a Account) error
+	// Normalize returns the account in the form it is stored in
+	Normalize(a Account) Account
+	// Print is the account on one line, for lists
+	Print(a Account) string
+	// Fields are the details payers need, in display order
+	Fields(a Account) []Field
+	// URI opens a wallet or payment page, empty if there is none
+	URI(a Account) string
+}
+
+// Types lists the payment method types in the order they are offered.
+var Types = []string{IBAN, SortCode, ABA, Crypto, Link}
+
+var methods = map[string]Method{
+	IBAN:     ibanMethod{},
+	SortCode: sortCodeMethod{},
+	ABA:      abaMethod{},
+	Crypto:   cryptoMethod{},
+	Link:     linkMethod{},
+}
+
+// Lookup returns the method of type t.
+func Lookup(t string) (Method, bool) {
+	m, ok := methods[t]
+	return m, ok
+}
+
+// Validate checks an account of type t and returns it normalized.
+func Validate(t string, a Account) (Account, error) {
+	m, ok := Lookup(t)
+	if !ok {
+		return a, fmt.Errorf("%w %q, expected one of %s", ErrType, t, strings.Join(Types, ", "))
+	}
+	if err := m.Validate(a); err != nil {
+		return a, err
+	}
+	return m.Normalize(a), nil
+}
+
+// digits removes spaces and dashes and reports whether only digits remain.
+func digits(s string) (string, bool) {
+	s = strings.Map(func(r rune) rune {
+		if r == ' ' || r == '-' {
+			return -1
+		}
+		return r
+	}, s)
+	return s, s != "" && strings.Trim(s, "0123456789") == ""
+}
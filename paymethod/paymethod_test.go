@@ -0,0 +1,102 @@
+package paymethod
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/tapsilat/iban.im/iso13616"
+)
+
+func TestValidate(t *testing.T) {
+	tests := []struct {
+		name       string
+		method     string
+		account    Account
+		want       Account
+		wantErr    error
+		wantFields int
+	}{
+		{name: "IBAN", method: IBAN, account: Account{Number: "de89 3704 0044 0532 0130 00"}, want: Account{Number: "DE89370400440532013000"}, wantFields: 1},
+		{name: "Invalid IBAN", method: IBAN, account: Account{Number: "DE89370400440532013001"}, wantErr: iso13616.ErrChecksum},
+		{name: "UK account", method: SortCode, account: Account{Number: "3141 5926", Routing: "20-00-00"}, want: Account{Number: "31415926", Routing: "200000"}, wantFields: 2},
+		{name: "Short sort code", method: SortCode, account: Account{Number: "31415926", Routing: "20-00"}, wantErr: ErrFormat},
+		{name: "Short UK account number", method: SortCode, account: Account{Number: "3141592", Routing: "200000"}, wantErr: ErrFormat},
+		{name: "US account", method: ABA, account: Account{Number: "000123456789", Routing: "021000021"}, want: Account{Number: "000123456789", Routing: "021000021"}, wantFields: 2},
+		{name: "ABA checksum", method: ABA, account: Account{Number: "000123456789", Routing: "021000022"}, wantErr: ErrChecksum},
+		{name: "ABA district", method: ABA, account: Account{Number: "000123456789", Routing: "500000000"}, wantErr: ErrFormat},
+		{name: "US account number too long", method: ABA, account: Account{Number: "123456789012345678", Routing: "011000015"}, wantErr: ErrFormat},
+		{name: "Bitcoin P2PKH", method: Crypto, account: Account{Number: "1BvBMSEYstWetqTFn5Au4m4GFg7xJaNVN2"}, want: Account{Number: "1BvBMSEYstWetqTFn5Au4m4GFg7xJaNVN2"}, wantFields: 1},
+		{name: "Bitcoin P2SH", method: Crypto, account: Account{Number: "3J98t1WpEZ73CNmQviecrnyiWrnqRhWNLy"}, want: Account{Number: "3J98t1WpEZ73CNmQviecrnyiWrnqRhWNLy"}, wantFields: 1},
+		{name: "Bitcoin base58 checksum", method: Crypto, account: Account{Number: "1BvBMSEYstWetqTFn5Au4m4GFg7xJaNVN3"}, wantErr: ErrChecksum},
+		{name: "Bitcoin segwit", method: Crypto, account: Account{Number: "BC1QW508D6QEJXTDG4Y5R3ZARVARY0C5XW7KV8F3T4"}, want: Account{Number: "bc1qw508d6qejxtdg4y5r3zarvary0c5xw7kv8f3t4"}, wantFields: 1},
+		{name: "Bitcoin taproot", method: Crypto, account: Account{Number: "bc1p0xlxvlhemja6c4dqv22uapctqupfhlxm9h8z3k2e72q4k9hcz7vqzk5jj0"}, want: Account{Number: "bc1p0xlxvlhemja6c4dqv22uapctqupfhlxm9h8z3k2e72q4k9hcz7vqzk5jj0"}, wantFields: 1},
+		{name: "Bitcoin bech32 checksum", method: Crypto, account: Account{Number: "bc1qw508d6qejxtdg4y5r3zarvary0c5xw7kv8f3t5"}, wantErr: ErrChecksum},
+		{name: "Bitcoin bech32 mixed case", method: Crypto, account: Account{Number: "bc1qw508d6qejxtdg4y5r3zarvary0c5xw7kv8F3t4"}, wantErr: ErrFormat},
+		{name: "Ethereum checksummed", method: Crypto, account: Account{Number: "0x5aAeb6053F3E94C9b9A09f33669435E7Ef1BeAed"}, want: Account{Number: "0x5aAeb6053F3E94C9b9A09f33669435E7Ef1BeAed"}, wantFields: 1},
+		{name: "Ethereum lower case", method: Crypto, account: Account{Number: "0xfb6916095ca1df60bb79ce92ce3ea74c37c5d359"}, want: Account{Number: "0xfB6916095ca1df60bB79Ce92cE3Ea74c37c5d359"}, wantFields: 1},
+		{name: "Ethereum checksum", method: Crypto, account: Account{Number: "0x5aAeb6053F3E94C9b9A09f33669435E7Ef1BeAeD"}, wantErr: ErrChecksum},
+		{name: "Unknown network", method: Crypto, account: Account{Number: "LVg2kJoFNg45Nbpy53h7Fe1wKyeXVRhMH9"}, wantErr: ErrFormat},
+		{name: "PayPal.me", method: Link, account: Account{Number: "www.PayPal.me/fakturk"}, want: Account{Number: "https://paypal.me/fakturk"}, wantFields: 1},
+		{name: "Unsupported provider", method: Link, account: Account{Number: "https://paypal.me.example.com/fakturk"}, wantErr: ErrLink},
+		{name: "Plain http", method: Link, account: Account{Number: "http://paypal.me/fakturk"}, wantErr: ErrLink},
+		{name: "Provider home page", method: Link, account: Account{Number: "https://revolut.me/"}, wantErr: ErrLink},
+		{name: "Unknown method", method: "cheque", account: Account{Number: "1"}, wantErr: ErrType},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := Validate(tt.method, tt.account)
+			if tt.wantErr != nil {
+				if !errors.Is(err, tt.wantErr) {
+					t.Errorf("Validate() = %v, want %v", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Validate() returned unexpected error: %v", err)
+			}
+			if got != tt.want {
+				t.Errorf("Validate() = %+v, want %+v", got, tt.want)
+			}
+			m, _ := Lookup(tt.method)
+			if fields := m.Fields(got); len(fields) != tt.wantFields {
+				t.Errorf("Fields() = %+v, want %d fields", fields, tt.wantFields)
+			}
+		})
+	}
+}
+
+func TestRender(t *testing.T) {
+	tests := []struct {
+		method    string
+		account   Account
+		wantPrint string
+		wantURI   string
+		wantLabel string
+	}{
+		{method: IBAN, account: Account{Number: "DE89370400440532013000"}, wantPrint: "DE89 3704 0044 0532 0130 00", wantURI: "payto://iban/DE89370400440532013000", wantLabel: "IBAN"},
+		{method: SortCode, account: Account{Number: "31415926", Routing: "200000"}, wantPrint: "20-00-00 31415926", wantLabel: "Sort code"},
+		{method: ABA, account: Account{Number: "000123456789", Routing: "021000021"}, wantPrint: "021000021 000123456789", wantLabel: "Routing number (ABA)"},
+		{method: Crypto, account: Account{Number: "bc1qw508d6qejxtdg4y5r3zarvary0c5xw7kv8f3t4"}, wantPrint: "bc1qw508d6qejxtdg4y5r3zarvary0c5xw7kv8f3t4", wantURI: "bitcoin:bc1qw508d6qejxtdg4y5r3zarvary0c5xw7kv8f3t4", wantLabel: "Bitcoin address"},
+		{method: Crypto, account: Account{Number: "0x5aAeb6053F3E94C9b9A09f33669435E7Ef1BeAed"}, wantPrint: "0x5aAeb6053F3E94C9b9A09f33669435E7Ef1BeAed", wantURI: "ethereum:0x5aAeb6053F3E94C9b9A09f33669435E7Ef1BeAed", wantLabel: "Ethereum address"},
+		{method: Link, account: Account{Number: "https://paypal.me/fakturk"}, wantPrint: "https://paypal.me/fakturk", wantURI: "https://paypal.me/fakturk", wantLabel: "PayPal.me"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.wantLabel, func(t *testing.T) {
+			m, ok := Lookup(tt.method)
+			if !ok {
+				t.Fatalf("Lookup(%q) found no method", tt.method)
+			}
+			if got := m.Print(tt.account); got != tt.wantPrint {
+				t.Errorf("Print() = %q, want %q", got, tt.wantPrint)
+			}
+			if got := m.URI(tt.account); got != tt.wantURI {
+				t.Errorf("URI() = %q, want %q", got, tt.wantURI)
+			}
+			if got := m.Fields(tt.account)[0].Label; got != tt.wantLabel {
+				t.Errorf("Fields()[0].Label = %q, want %q", got, tt.wantLabel)
+			}
+		})
+	}
+}
@@ -0,0 +1,54 @@
+package paymethod
+
+import "fmt"
+
+// sortCodeMethod is a UK account: a six digit sort code for the branch and
+// an eight digit account number. Sort codes carry no check digits of their
+// own; the modulus checks of UK account numbers need the weight tables of
+// Pay.UK and are left to the paying bank.
+type sortCodeMethod struct{}
+
+func (sortCodeMethod) Label() string {
+	return "UK bank account"
+}
+
+func (sortCodeMethod) Validate(a Account) error {
+	if code, ok := digits(a.Routing); !ok || len(code) != 6 {
+		return fmt.Errorf("%w: sort code must be 6 digits", ErrFormat)
+	}
+	if number, ok := digits(a.Number); !ok || len(number) != 8 {
+		return fmt.Errorf("%w: UK account number must be 8 digits", ErrFormat)
+	}
+	return nil
+}
+
+func (sortCodeMethod) Normalize(a Account) Account {
+	a.Routing, _ = digits(a.Routing)
+	a.Number, _ = digits(a.Number)
+	return a
+}
+
+// sortCode prints the sort code in pairs, e.g. 20-00-00
+func sortCode(s string) string {
+	if len(s) != 6 {
+		return s
+	}
+	return s[0:2] + "-" + s[2:4] + "-" + s[4:6]
+}
+
+func (m sortCodeMethod) Print(a Account) string {
+	a = m.Normalize(a)
+	return sortCode(a.Routing) + " " + a.Number
+}
+
+func (m sortCodeMethod) Fields(a Account) []Field {
+	a = m.Normalize(a)
+	return []Field{
+		{Label: "Sort code", Value: sortCode(a.Routing), Copy: a.Routing},
+		{Label: "Account number", Value: a.Number, Copy: a.Number},
+	}
+}
+
+func (sortCodeMethod) URI(Account) string {
+	return ""
+}
@@ -0,0 +1,143 @@
+// Package payto builds and parses payto URIs as defined by RFC 8905 for the
+// "iban" target type, e.g.
+//
+//	payto://iban/COBADEFF/DE89370400440532013000?amount=EUR:12.50&receiver-name=Franz%20Mustermann
+package payto
+
+import (
+	"errors"
+	"fmt"
+	"net/url"
+	"regexp"
+	"strings"
+
+	"github.com/tapsilat/iban.im/cents"
+	"github.com/tapsilat/iban.im/iso13616"
+	"github.com/tapsilat/iban.im/iso9362"
+)
+
+// Scheme is the URI scheme of payto URIs.
+const Scheme = "payto"
+
+// Errors returned by Parse and ParseAmount.
+var (
+	ErrScheme = errors.New("not a payto URI")
+	ErrTarget = errors.New("unsupported payto target type, only iban is supported")
+	ErrPath   = errors.New("payto path must be an IBAN, optionally preceded by a BIC")
+	ErrAmount = errors.New("payto amount must be a currency and a value, e.g. EUR:12.50")
+)
+
+var currencyCode = regexp.MustCompile(`^[A-Z]{3}$`)
+
+// URI is a payto://iban/ URI.
+type URI struct {
+	IBAN         string
+	BIC          string
+	ReceiverName string
+	// Currency is the ISO 4217 code of the amount.
+	Currency string
+	// Amount in cents, 0 leaves the amount to the payer.
+	Amount  int64
+	Message string
+}
+
+// IsURI reports whether s looks like a payto URI rather than a plain IBAN.
+func IsURI(s string) bool {
+	return strings.HasPrefix(strings.ToLower(strings.TrimSpace(s)), Scheme+":")
+}
+
+// String renders the canonical form of u: IBAN and BIC in electronic
+// format and the options in a fixed order. The amount is left out without a
+// currency.
+func (u URI) String() string {
+	var b strings.Builder
+	b.WriteString(Scheme + "://iban/")
+	if u.BIC != "" {
+		b.WriteString(iso9362.Normalize(u.BIC) + "/")
+	}
+	b.WriteString(iso13616.Normalize(u.IBAN))
+
+	sep := "?"
+	option := func(name, value string) {
+		if value == "" {
+			return
+		}
+		b.WriteString(sep + name + "=" + escape(value))
+		sep = "&"
+	}
+	if u.Amount > 0 && u.Currency != "" {
+		option("amount", FormatAmount(u.Currency, u.Amount))
+	}
+	option("receiver-name", u.ReceiverName)
+	option("message", u.Message)
+	return b.String()
+}
+
+// escape percent-encodes an option value, keeping the colon of amounts
+// readable.
+func escape(s string) string {
+	s = strings.ReplaceAll(url.QueryEscape(s), "+", "%20")
+	return strings.ReplaceAll(s, "%3A", ":")
+}
+
+// Parse reads a payto://iban/ URI, validating the IBAN and the optional
+// BIC. Options other than amount, receiver-name and message are ignored.
+func Parse(s string) (URI, error) {
+	parsed, err := url.Parse(strings.TrimSpace(s))
+	if err != nil || !strings.EqualFold(parsed.Scheme, Scheme) {
+		return URI{}, ErrScheme
+	}
+	if !strings.EqualFold(parsed.Host, "iban") {
+		return URI{}, fmt.Errorf("%w: %q", ErrTarget, parsed.Host)
+	}
+
+	var uri URI
+	switch segments := strings.Split(strings.Trim(parsed.Path, "/"), "/"); len(segments) {
+	case 1:
+		uri.IBAN = segments[0]
+	case 2:
+		uri.BIC, uri.IBAN = segments[0], segments[1]
+	default:
+		return URI{}, ErrPath
+	}
+	if err := iso13616.Validate(uri.IBAN); err != nil {
+		return URI{}, err
+	}
+	uri.IBAN = iso13616.Normalize(uri.IBAN)
+	if uri.BIC != "" {
+		if err := iso9362.Validate(uri.BIC); err != nil {
+			return URI{}, err
+		}
+		uri.BIC = iso9362.Normalize(uri.BIC)
+	}
+
+	query := parsed.Query()
+	uri.ReceiverName = query.Get("receiver-name")
+	uri.Message = query.Get("message")
+	if amount := query.Get("amount"); amount != "" {
+		if uri.Currency, uri.Amount, err = ParseAmount(amount); err != nil {
+			return URI{}, err
+		}
+	}
+	return uri, nil
+}
+
+// FormatAmount renders cents in the payto amount format, e.g. "EUR:12.50".
+func FormatAmount(currency string, amount int64) string {
+	return strings.ToUpper(currency) + ":" + cents.Format(amount)
+}
+
+// ParseAmount reads a payto amount such as "EUR:12.50" into currency and
+// cents. Fractions beyond cents must be zero.
+func ParseAmount(s string) (string, int64, error) {
+	currency, value, ok := strings.Cut(s, ":")
+	currency = strings.ToUpper(currency)
+	if !ok || !currencyCode.MatchString(currency) {
+		return "", 0, ErrAmount
+	}
+	amount, err := cents.Parse(value)
+	if err != nil || amount == 0 {
+		return "", 0, ErrAmount
+	}
+	return currency, amount, nil
+}
@@ -0,0 +1,144 @@
+package payto
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/tapsilat/iban.im/iso13616"
+)
+
+func TestString(t *testing.T) {
+	tests := []struct {
+		name string
+		uri  URI
+		want string
+	}{
+		{
+			name: "IBAN only",
+			uri:  URI{IBAN: "DE89 3704 0044 0532 0130 00"},
+			want: "payto://iban/DE89370400440532013000",
+		},
+		{
+			name: "All options",
+			uri:  URI{IBAN: "DE89370400440532013000", BIC: "cobadeff", ReceiverName: "Franz Mustermann", Currency: "eur", Amount: 1250, Message: "Dinner & drinks"},
+			want: "payto://iban/COBADEFF/DE89370400440532013000?amount=EUR:12.50&receiver-name=Franz%20Mustermann&message=Dinner%20%26%20drinks",
+		},
+		{
+			name: "Amount without currency",
+			uri:  URI{IBAN: "DE89370400440532013000", Amount: 1250, Message: "Rent"},
+			want: "payto://iban/DE89370400440532013000?message=Rent",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.uri.String(); got != tt.want {
+				t.Errorf("String() = %s, want %s", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestParse(t *testing.T) {
+	tests := []struct {
+		name    string
+		input   string
+		want    URI
+		wantErr error
+	}{
+		{
+			name:  "IBAN only",
+			input: "payto://iban/DE89370400440532013000",
+			want:  URI{IBAN: "DE89370400440532013000"},
+		},
+		{
+			name:  "BIC and options",
+			input: "PAYTO://IBAN/cobadeff/de89370400440532013000?amount=EUR:200.0&receiver-name=Franz+Mustermann&message=Dinner%20%26%20drinks&instruction=x",
+			want:  URI{IBAN: "DE89370400440532013000", BIC: "COBADEFF", ReceiverName: "Franz Mustermann", Currency: "EUR", Amount: 20000, Message: "Dinner & drinks"},
+		},
+		{
+			name:    "Other scheme",
+			input:   "https://iban/DE89370400440532013000",
+			wantErr: ErrScheme,
+		},
+		{
+			name:    "Other target type",
+			input:   "payto://bitcoin/1A1zP1eP5QGefi2DMPTfTL5SLmv7DivfNa",
+			wantErr: ErrTarget,
+		},
+		{
+			name:    "Too many segments",
+			input:   "payto://iban/COBADEFF/DE89370400440532013000/extra",
+			wantErr: ErrPath,
+		},
+		{
+			name:    "Invalid IBAN",
+			input:   "payto://iban/DE89370400440532013001",
+			wantErr: iso13616.ErrChecksum,
+		},
+		{
+			name:    "Invalid amount",
+			input:   "payto://iban/DE89370400440532013000?amount=12.50",
+			wantErr: ErrAmount,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := Parse(tt.input)
+			if tt.wantErr != nil {
+				if !errors.Is(err, tt.wantErr) {
+					t.Errorf("Parse() error = %v, want %v", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Parse() returned unexpected error: %v", err)
+			}
+			if got != tt.want {
+				t.Errorf("Parse() = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestRoundTrip(t *testing.T) {
+	uri := URI{IBAN: "CH9300762011623852957", ReceiverName: "Zoë Müller", Currency: "CHF", Amount: 5, Message: "a+b=c?"}
+	got, err := Parse(uri.String())
+	if err != nil || got != uri {
+		t.Errorf("Parse(%s) = %+v, %v, want %+v", uri.String(), got, err, uri)
+	}
+}
+
+func TestParseAmount(t *testing.T) {
+	tests := []struct {
+		amount   string
+		currency string
+		cents    int64
+		wantErr  bool
+	}{
+		{amount: "EUR:12.50", currency: "EUR", cents: 1250},
+		{amount: "eur:12", currency: "EUR", cents: 1200},
+		{amount: "CHF:0.01", currency: "CHF", cents: 1},
+		{amount: "EUR:1.50000000", currency: "EUR", cents: 150},
+		{amount: "EUR:1.005", wantErr: true},
+		{amount: "EUR:0", wantErr: true},
+		{amount: "EUR:-1", wantErr: true},
+		{amount: "EUR:", wantErr: true},
+		{amount: "EURO:1", wantErr: true},
+		{amount: "12.50", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		currency, cents, err := ParseAmount(tt.amount)
+		if tt.wantErr {
+			if err == nil {
+				t.Errorf("ParseAmount(%q) = %s, %d, want error", tt.amount, currency, cents)
+			}
+			continue
+		}
+		if err != nil || currency != tt.currency || cents != tt.cents {
+			t.Errorf("ParseAmount(%q) = %s, %d, %v, want %s, %d", tt.amount, currency, cents, err, tt.currency, tt.cents)
+		}
+	}
+}
@@ -0,0 +1,153 @@
+// Package qrimage renders payment payloads as QR code images.
+package qrimage
+
+import (
+	"bytes"
+	"fmt"
+	"image"
+	"image/color"
+	"image/draw"
+	"image/png"
+
+	qrcode "github.com/skip2/go-qrcode"
+)
+
+// Payment QR standards ask for error correction level M.
+const level = qrcode.Medium
+
+// quietZone is the number of light modules go-qrcode puts around the code.
+const quietZone = 4
+
+// Option changes how a QR code is drawn.
+type Option func(*options)
+
+type options struct {
+	swissCross bool
+}
+
+// WithSwissCross puts the Swiss cross in the centre of the code, as the
+// Swiss QR-bill standard requires.
+func WithSwissCross() Option {
+	return func(o *options) {
+		o.swissCross = true
+	}
+}
+
+// Rect is an axis aligned rectangle in the unit of the drawing.
+type Rect struct {
+	X, Y, W, H float64
+	Dark       bool
+}
+
+// SwissCross returns the shapes of a Swiss cross with the given side length
+// centred on (cx, cy), in drawing order: a light frame, the dark square and
+// the light cross.
+func SwissCross(cx, cy, side float64) []Rect {
+	inner := side * 6 / 7
+	long, short := inner*0.6, inner*0.19
+	return []Rect{
+		{X: cx - side/2, Y: cy - side/2, W: side, H: side},
+		{X: cx - inner/2, Y: cy - inner/2, W: inner, H: inner, Dark: true},
+		{X: cx - short/2, Y: cy - long/2, W: short, H: long},
+		{X: cx - long/2, Y: cy - short/2, W: long, H: short},
+	}
+}
+
+// swissCrossSide is the side of the cross relative to the code: 7 mm on a
+// 46 mm code.
+const swissCrossSide = 7.0 / 46.0
+
+// Modules returns the dark (true) and light modules of the QR code for
+// payload, without the quiet zone.
+func Modules(payload string) ([][]bool, error) {
+	q, err := qrcode.New(payload, level)
+	if err != nil {
+		return nil, err
+	}
+	q.DisableBorder = true
+	return q.Bitmap(), nil
+}
+
+// PNG renders payload as a size x size pixel PNG image.
+func PNG(payload string, size int, opts ...Option) ([]byte, error) {
+	o := apply(opts)
+	q, err := qrcode.New(payload, level)
+	if err != nil {
+		return nil, err
+	}
+	if !o.swissCross {
+		return q.PNG(size)
+	}
+
+	src := q.Image(size)
+	img := image.NewRGBA(src.Bounds())
+	draw.Draw(img, img.Bounds(), src, src.Bounds().Min, draw.Src)
+	// go-qrcode scales modules to whole pixels and centres the code
+	width := img.Bounds().Dx()
+	modules := len(q.Bitmap())
+	code := float64(width / modules * (modules - 2*quietZone))
+	center := float64(width) / 2
+	for _, r := range SwissCross(center, center, code*swissCrossSide) {
+		fill := color.RGBA{R: 255, G: 255, B: 255, A: 255}
+		if r.Dark {
+			fill = color.RGBA{A: 255}
+		}
+		rect := image.Rect(int(r.X+0.5), int(r.Y+0.5), int(r.X+r.W+0.5), int(r.Y+r.H+0.5))
+		draw.Draw(img, rect, &image.Uniform{C: fill}, image.Point{}, draw.Src)
+	}
+
+	var b bytes.Buffer
+	if err := png.Encode(&b, img); err != nil {
+		return nil, err
+	}
+	return b.Bytes(), nil
+}
+
+// SVG renders payload as a scalable image with one unit per module,
+// including the quiet zone.
+func SVG(payload string, opts ...Option) ([]byte, error) {
+	o := apply(opts)
+	q, err := qrcode.New(payload, level)
+	if err != nil {
+		return nil, err
+	}
+	bitmap := q.Bitmap()
+
+	var b bytes.Buffer
+	fmt.Fprintf(&b, `<svg xmlns="http://www.w3.org/2000/svg" viewBox="0 0 %d %d" shape-rendering="crispEdges">`, len(bitmap), len(bitmap))
+	fmt.Fprintf(&b, `<rect width="100%%" height="100%%" fill="#fff"/><path fill="#000" d="`)
+	for y, row := range bitmap {
+		for x := 0; x < len(row); x++ {
+			if !row[x] {
+				continue
+			}
+			// Merge dark modules of a row into one rectangle
+			start := x
+			for x < len(row) && row[x] {
+				x++
+			}
+			fmt.Fprintf(&b, "M%d %dh%dv1h-%dz", start, y, x-start, x-start)
+		}
+	}
+	b.WriteString(`"/>`)
+	if o.swissCross {
+		center := float64(len(bitmap)) / 2
+		for _, r := range SwissCross(center, center, float64(len(bitmap)-2*quietZone)*swissCrossSide) {
+			fill := "#fff"
+			if r.Dark {
+				fill = "#000"
+			}
+			fmt.Fprintf(&b, `<rect x="%.3f" y="%.3f" width="%.3f" height="%.3f" fill="%s"/>`, r.X, r.Y, r.W, r.H, fill)
+		}
+	}
+	b.WriteString(`</svg>`)
+	return b.Bytes(), nil
+}
+
+func apply(opts []Option) options {
+	var o options
+	for _, opt := range opts {
+		opt(&o)
+	}
+	return o
+}
@@ -0,0 +1,47 @@
+package qrimage
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+)
+
+func TestPNG(t *testing.T) {
+	image, err := PNG("BCD\n002\n1\nSCT", 200)
+	if err != nil {
+		t.Fatalf("PNG() returned unexpected error: %v", err)
+	}
+	if !bytes.HasPrefix(image, []byte("\x89PNG")) {
+		t.Error("PNG() did not return a PNG image")
+	}
+}
+
+func TestSVG(t *testing.T) {
+	image, err := SVG("BCD\n002\n1\nSCT")
+	if err != nil {
+		t.Fatalf("SVG() returned unexpected error: %v", err)
+	}
+	svg := string(image)
+	if !strings.HasPrefix(svg, "<svg") || !strings.HasSuffix(svg, "</svg>") || !strings.Contains(svg, "M") {
+		t.Errorf("SVG() = %s", svg)
+	}
+}
+
+func TestSwissCross(t *testing.T) {
+	plain, err := SVG("SPC\n0200\n1")
+	if err != nil {
+		t.Fatalf("SVG() returned unexpected error: %v", err)
+	}
+	crossed, err := SVG("SPC\n0200\n1", WithSwissCross())
+	if err != nil {
+		t.Fatalf("SVG() returned unexpected error: %v", err)
+	}
+	if bytes.Equal(plain, crossed) || !strings.Contains(string(crossed), "<rect") {
+		t.Errorf("SVG() with the Swiss cross = %s", crossed)
+	}
+
+	rects := SwissCross(10, 10, 7)
+	if len(rects) == 0 || rects[0].W != 7 || rects[0].X != 6.5 || rects[0].Dark {
+		t.Errorf("SwissCross() frame = %+v, want a white 7x7 square centered at 10,10", rects)
+	}
+}
@@ -2,6 +2,7 @@ package resolvers
 
 import (
 	"context"
+	"regexp"
 	"strings"
 
 	"github.com/tapsilat/iban.im/config"
@@ -29,8 +30,31 @@ func (r *Resolvers) ChangeProfile(ctx context.Context, args changeProfileMutatio
 		user.Bio = *args.Bio
 	}
 	if args.Handle != nil {
+		if model.ReservedHandle(*args.Handle) {
+			msg := "Handle is reserved"
+			return &ChangeProfileResponse{Status: false, Msg: &msg, User: nil}, nil
+		}
 		user.Handle = strings.ToLower(*args.Handle)
 	}
+	if args.Street != nil {
+		user.Street = strings.TrimSpace(*args.Street)
+	}
+	if args.BuildingNumber != nil {
+		user.BuildingNumber = strings.TrimSpace(*args.BuildingNumber)
+	}
+	if args.PostalCode != nil {
+		user.PostalCode = strings.TrimSpace(*args.PostalCode)
+	}
+	if args.Town != nil {
+		user.Town = strings.TrimSpace(*args.Town)
+	}
+	if args.Country != nil {
+		user.Country = strings.ToUpper(strings.TrimSpace(*args.Country))
+		if user.Country != "" && !countryCode.MatchString(user.Country) {
+			msg := "Country must be a two letter ISO 3166 code"
+			return &ChangeProfileResponse{Status: false, Msg: &msg, User: nil}, nil
+		}
+	}
 
 	if err := config.DB.Save(&user).Error; err != nil {
 		msg := err.Error()
@@ -40,10 +64,17 @@ func (r *Resolvers) ChangeProfile(ctx context.Context, args changeProfileMutatio
 }
 
 type changeProfileMutationArgs struct {
-	Bio    *string
-	Handle *string
+	Bio            *string
+	Handle         *string
+	Street         *string
+	BuildingNumber *string
+	PostalCode     *string
+	Town           *string
+	Country        *string
 }
 
+var countryCode = regexp.MustCompile(`^[A-Z]{2}$`)
+
 // ChangeProfileResponse is the response type
 type ChangeProfileResponse struct {
 	Status bool
@@ -0,0 +1,54 @@
+package resolvers
+
+import (
+	"context"
+
+	"github.com/tapsilat/iban.im/iso13616"
+)
+
+// ComposeIban query builds an IBAN from national account details
+func (r *Resolvers) ComposeIban(ctx context.Context, args ComposeIbanQueryArgs) (*ComposeIbanResponse, error) {
+	branchCode := ""
+	if args.BranchCode != nil {
+		branchCode = *args.BranchCode
+	}
+
+	iban, err := iso13616.Compose(args.Country, args.BankCode, branchCode, args.Account)
+	if err != nil {
+		msg := err.Error()
+		return &ComposeIbanResponse{Status: false, Msg: &msg}, nil
+	}
+
+	printFormat := iso13616.PrintFormat(iban)
+	return &ComposeIbanResponse{Status: true, Iban: &iban, Print: &printFormat}, nil
+}
+
+type ComposeIbanQueryArgs struct {
+	Country    string
+	BankCode   string
+	BranchCode *string
+	Account    string
+}
+
+// ComposeIbanResponse is the response type
+type ComposeIbanResponse struct {
+	Status bool
+	Msg    *string
+	Iban   *string
+	Print  *string
+}
+
+// Ok for ComposeIbanResponse
+func (r *ComposeIbanResponse) Ok() bool {
+	return r.Status
+}
+
+// Error for ComposeIbanResponse
+func (r *ComposeIbanResponse) Error() *string {
+	return r.Msg
+}
+
+// PrintFormat for ComposeIbanResponse
+func (r *ComposeIbanResponse) PrintFormat() *string {
+	return r.Print
+}
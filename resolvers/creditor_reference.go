@@ -0,0 +1,64 @@
+package resolvers
+
+import (
+	"context"
+
+	"github.com/tapsilat/iban.im/iso11649"
+)
+
+// GenerateCreditorReference query turns an invoice or customer number into
+// an ISO 11649 RF creditor reference
+func (r *Resolvers) GenerateCreditorReference(ctx context.Context, args CreditorReferenceQueryArgs) (*CreditorReferenceResponse, error) {
+	ref, err := iso11649.Generate(args.Reference)
+	if err != nil {
+		msg := err.Error()
+		return &CreditorReferenceResponse{Status: false, Msg: &msg}, nil
+	}
+	return newCreditorReferenceResponse(ref), nil
+}
+
+// ValidateCreditorReference query checks an ISO 11649 RF creditor reference
+func (r *Resolvers) ValidateCreditorReference(ctx context.Context, args CreditorReferenceQueryArgs) (*CreditorReferenceResponse, error) {
+	if err := iso11649.Validate(args.Reference); err != nil {
+		msg := err.Error()
+		return &CreditorReferenceResponse{Status: false, Msg: &msg}, nil
+	}
+	return newCreditorReferenceResponse(iso11649.Normalize(args.Reference)), nil
+}
+
+type CreditorReferenceQueryArgs struct {
+	Reference string
+}
+
+// CreditorReferenceResponse is the response type
+type CreditorReferenceResponse struct {
+	Status bool
+	Msg    *string
+	Ref    *string
+	Print  *string
+}
+
+func newCreditorReferenceResponse(ref string) *CreditorReferenceResponse {
+	printFormat := iso11649.PrintFormat(ref)
+	return &CreditorReferenceResponse{Status: true, Ref: &ref, Print: &printFormat}
+}
+
+// Ok for CreditorReferenceResponse
+func (r *CreditorReferenceResponse) Ok() bool {
+	return r.Status
+}
+
+// Error for CreditorReferenceResponse
+func (r *CreditorReferenceResponse) Error() *string {
+	return r.Msg
+}
+
+// Reference for CreditorReferenceResponse
+func (r *CreditorReferenceResponse) Reference() *string {
+	return r.Ref
+}
+
+// PrintFormat for CreditorReferenceResponse
+func (r *CreditorReferenceResponse) PrintFormat() *string {
+	return r.Print
+}
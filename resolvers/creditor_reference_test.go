@@ -0,0 +1,58 @@
+package resolvers
+
+import (
+	"context"
+	"testing"
+)
+
+func TestGenerateCreditorReference(t *testing.T) {
+	resolver := &Resolvers{}
+
+	resp, err := resolver.GenerateCreditorReference(context.Background(), CreditorReferenceQueryArgs{Reference: "5390 0754 7034"})
+	if err != nil {
+		t.Fatalf("GenerateCreditorReference returned unexpected error: %v", err)
+	}
+	if !resp.Ok() || *resp.Reference() != "RF18539007547034" {
+		t.Errorf("GenerateCreditorReference() = %v, %v", resp.Ok(), resp.Error())
+	}
+	if *resp.PrintFormat() != "RF18 5390 0754 7034" {
+		t.Errorf("PrintFormat() = %s", *resp.PrintFormat())
+	}
+
+	resp, _ = resolver.GenerateCreditorReference(context.Background(), CreditorReferenceQueryArgs{Reference: "INV-1"})
+	if resp.Ok() || resp.Error() == nil || *resp.Error() != "invalid creditor reference format: INV-1 may only contain letters and digits" {
+		t.Errorf("Expected format error, got %v", resp.Error())
+	}
+}
+
+func TestValidateCreditorReference(t *testing.T) {
+	tests := []struct {
+		name          string
+		reference     string
+		expectSuccess bool
+		expectError   string
+	}{
+		{name: "Valid", reference: "rf18 5390 0754 7034", expectSuccess: true},
+		{name: "Wrong check digits", reference: "RF19539007547034", expectError: "creditor reference checksum does not match"},
+		{name: "Not an RF reference", reference: "539007547034", expectError: "invalid creditor reference format: expected RF, two check digits and up to 21 characters"},
+	}
+
+	resolver := &Resolvers{}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			resp, err := resolver.ValidateCreditorReference(context.Background(), CreditorReferenceQueryArgs{Reference: tt.reference})
+			if err != nil {
+				t.Fatalf("ValidateCreditorReference returned unexpected error: %v", err)
+			}
+			if resp.Ok() != tt.expectSuccess {
+				t.Errorf("Ok() = %v, want %v", resp.Ok(), tt.expectSuccess)
+			}
+			if tt.expectSuccess && *resp.Reference() != "RF18539007547034" {
+				t.Errorf("Reference() = %s, want RF18539007547034", *resp.Reference())
+			}
+			if tt.expectError != "" && (resp.Error() == nil || *resp.Error() != tt.expectError) {
+				t.Errorf("Error() = %v, want %s", resp.Error(), tt.expectError)
+			}
+		})
+	}
+}
@@ -30,8 +30,23 @@ func (r *Resolvers) DeleteProfile(ctx context.Context, args deleteProfileMutatio
 		return &DeleteProfileResponse{Status: false, Msg: &msg, MsgText: nil}, nil
 	}
 
+	// Groups must not be left without an owner
+	var memberships []model.GroupMember
+	config.DB.Where("user_id = ?", user.UserID).Find(&memberships)
+	for _, member := range memberships {
+		if lastOwner(member) {
+			msg := "You are the last owner of a group; make someone else owner or delete the group first"
+			return &DeleteProfileResponse{Status: false, Msg: &msg, MsgText: nil}, nil
+		}
+	}
+	if err := config.DB.Where("user_id = ?", user.UserID).Delete(&model.GroupMember{}).Error; err != nil {
+		msg := "Failed to leave user groups"
+		log.Printf("Error deleting group memberships for user %d: %v", user.UserID, err)
+		return &DeleteProfileResponse{Status: false, Msg: &msg, MsgText: nil}, err
+	}
+
 	// Delete all associated IBANs (soft delete)
-	if err := config.DB.Where("owner_id = ? AND owner_type = ?", user.UserID, "User").Delete(&model.Iban{}).Error; err != nil {
+	if err := config.DB.Where("owner_id = ? AND owner_type = ?", user.UserID, model.OwnerUser).Delete(&model.Iban{}).Error; err != nil {
 		msg := "Failed to delete user IBANs"
 		log.Printf("Error deleting IBANs for user %d: %v", user.UserID, err)
 		return &DeleteProfileResponse{Status: false, Msg: &msg, MsgText: nil}, err
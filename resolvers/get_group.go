@@ -0,0 +1,110 @@
+package resolvers
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/tapsilat/iban.im/config"
+	"github.com/tapsilat/iban.im/handler"
+	"github.com/tapsilat/iban.im/model"
+)
+
+// GetGroup query returns a group by its handle with its public IBANs, or
+// all of them for its owners and admins
+func (r *Resolvers) GetGroup(ctx context.Context, args GroupQueryArgs) (response *GetGroupResponse, err error) {
+	response = &GetGroupResponse{}
+	group := model.Group{}
+	var ibans []model.Iban
+	manager := false
+
+	defer func() {
+		if reportError(&err, &response.Msg) {
+			return
+		}
+		response.Status = true
+		response.Group = &GroupResponse{g: &group}
+		response.Ibans = []*IbanResponse{}
+		for i := range ibans {
+			if !manager {
+				// Plain members must not brute-force private IBANs offline
+				ibans[i].Password = ""
+			}
+			response.Ibans = append(response.Ibans, &IbanResponse{i: &ibans[i]})
+		}
+	}()
+
+	if err = config.DB.Where("handle = ?", strings.ToLower(args.Handle)).First(&group).Error; err != nil {
+		err = fmt.Errorf("group is not exist")
+		return
+	}
+	query := config.DB.Where("owner_id = ? AND owner_type = ?", group.GroupID, model.OwnerGroup)
+	if userID := ctx.Value(handler.ContextKey("UserID")); userID != nil {
+		manager = model.CanManageGroup(config.DB, group.GroupID, uint(userID.(int)))
+	}
+	if !manager {
+		query = query.Where("is_private = false")
+	}
+	err = query.Order("handle").Find(&ibans).Error
+	return
+}
+
+// GetMyGroups query lists the groups the user is a member of
+func (r *Resolvers) GetMyGroups(ctx context.Context) (*GetMyGroupsResponse, error) {
+	userID := ctx.Value(handler.ContextKey("UserID"))
+	if userID == nil {
+		msg := "Not Authorized"
+		return &GetMyGroupsResponse{Status: false, Msg: &msg}, nil
+	}
+
+	var groups []model.Group
+	members := config.DB.Model(&model.GroupMember{}).Select("group_id").Where("user_id = ? AND status = ?", userID.(int), model.MemberActive)
+	if err := config.DB.Where("group_id IN (?)", members).Order("group_name").Find(&groups).Error; err != nil {
+		msg := err.Error()
+		return &GetMyGroupsResponse{Status: false, Msg: &msg}, nil
+	}
+	response := &GetMyGroupsResponse{Status: true, Groups: []*GroupResponse{}}
+	for i := range groups {
+		response.Groups = append(response.Groups, &GroupResponse{g: &groups[i]})
+	}
+	return response, nil
+}
+
+type GroupQueryArgs struct {
+	Handle string
+}
+
+// GetGroupResponse is the response type
+type GetGroupResponse struct {
+	Status bool
+	Msg    *string
+	Group  *GroupResponse
+	Ibans  []*IbanResponse
+}
+
+// Ok for GetGroupResponse
+func (r *GetGroupResponse) Ok() bool {
+	return r.Status
+}
+
+// Error for GetGroupResponse
+func (r *GetGroupResponse) Error() *string {
+	return r.Msg
+}
+
+// GetMyGroupsResponse is the response type
+type GetMyGroupsResponse struct {
+	Status bool
+	Msg    *string
+	Groups []*GroupResponse
+}
+
+// Ok for GetMyGroupsResponse
+func (r *GetMyGroupsResponse) Ok() bool {
+	return r.Status
+}
+
+// Error for GetMyGroupsResponse
+func (r *GetMyGroupsResponse) Error() *string {
+	return r.Msg
+}
@@ -0,0 +1,95 @@
+package resolvers
+
+import (
+	"context"
+
+	graphql "github.com/graph-gophers/graphql-go"
+
+	"github.com/tapsilat/iban.im/config"
+	"github.com/tapsilat/iban.im/handler"
+	"github.com/tapsilat/iban.im/model"
+)
+
+// GetGroupMembers query lists the members and open invitations of a group to
+// its members
+func (r *Resolvers) GetGroupMembers(ctx context.Context, args GroupMembersQueryArgs) (response *GetGroupMembersResponse, err error) {
+	response = &GetGroupMembersResponse{}
+	var members []model.GroupMember
+
+	defer func() {
+		if reportError(&err, &response.Msg) {
+			return
+		}
+		response.Status = true
+		response.Members = []*GroupMemberResponse{}
+		for i := range members {
+			response.Members = append(response.Members, newGroupMemberResponse(&members[i]))
+		}
+	}()
+
+	group, _, err := findGroupAs(ctx.Value(handler.ContextKey("UserID")), args.GroupID, model.RoleOwner, model.RoleAdmin, model.RoleMember)
+	if err != nil {
+		return
+	}
+	err = config.DB.Where("group_id = ?", group.GroupID).Order("group_member_id").Find(&members).Error
+	return
+}
+
+// GetMyGroupInvitations query lists the invitations the user has not yet
+// accepted
+func (r *Resolvers) GetMyGroupInvitations(ctx context.Context) (*GetMyGroupInvitationsResponse, error) {
+	userID := ctx.Value(handler.ContextKey("UserID"))
+	if userID == nil {
+		msg := "Not Authorized"
+		return &GetMyGroupInvitationsResponse{Status: false, Msg: &msg}, nil
+	}
+
+	var members []model.GroupMember
+	if err := config.DB.Where("user_id = ? AND status = ?", userID.(int), model.MemberInvited).Order("group_member_id").Find(&members).Error; err != nil {
+		msg := err.Error()
+		return &GetMyGroupInvitationsResponse{Status: false, Msg: &msg}, nil
+	}
+	response := &GetMyGroupInvitationsResponse{Status: true, Invitations: []*GroupMemberResponse{}}
+	for i := range members {
+		response.Invitations = append(response.Invitations, newGroupMemberResponse(&members[i]))
+	}
+	return response, nil
+}
+
+type GroupMembersQueryArgs struct {
+	GroupID graphql.ID
+}
+
+// GetGroupMembersResponse is the response type
+type GetGroupMembersResponse struct {
+	Status  bool
+	Msg     *string
+	Members []*GroupMemberResponse
+}
+
+// Ok for GetGroupMembersResponse
+func (r *GetGroupMembersResponse) Ok() bool {
+	return r.Status
+}
+
+// Error for GetGroupMembersResponse
+func (r *GetGroupMembersResponse) Error() *string {
+	return r.Msg
+}
+
+// GetMyGroupInvitationsResponse is the response type
+type GetMyGroupInvitationsResponse struct {
+	Status      bool
+	Msg         *string
+	Invitations []*GroupMemberResponse
+}
+
+// Ok for GetMyGroupInvitationsResponse
+func (r *GetMyGroupInvitationsResponse) Ok() bool {
+	return r.Status
+}
+
+// Error for GetMyGroupInvitationsResponse
+func (r *GetMyGroupInvitationsResponse) Error() *string {
+	return r.Msg
+}
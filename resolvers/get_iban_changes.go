@@ -0,0 +1,71 @@
+package resolvers
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	graphql "github.com/graph-gophers/graphql-go"
+
+	"github.com/tapsilat/iban.im/config"
+	"github.com/tapsilat/iban.im/handler"
+	"github.com/tapsilat/iban.im/model"
+)
+
+// GetIbanChanges query lists the pending and decided changes of the IBANs of
+// a group to its members, newest first
+func (r *Resolvers) GetIbanChanges(ctx context.Context, args IbanChangesQueryArgs) (response *GetIbanChangesResponse, err error) {
+	response = &GetIbanChangesResponse{}
+	var changes []model.IbanChange
+
+	defer func() {
+		if reportError(&err, &response.Msg) {
+			return
+		}
+		response.Status = true
+		response.Changes = []*IbanChangeResponse{}
+		for i := range changes {
+			response.Changes = append(response.Changes, &IbanChangeResponse{c: &changes[i]})
+		}
+	}()
+
+	group, _, err := findGroupAs(ctx.Value(handler.ContextKey("UserID")), args.GroupID, model.RoleOwner, model.RoleAdmin, model.RoleMember)
+	if err != nil {
+		return
+	}
+	query := config.DB.Where("group_id = ?", group.GroupID)
+	if args.Status != nil && *args.Status != "" {
+		status := strings.ToLower(strings.TrimSpace(*args.Status))
+		switch status {
+		case model.ChangePending, model.ChangeApproved, model.ChangeRejected:
+		default:
+			err = fmt.Errorf("status must be one of %s, %s or %s", model.ChangePending, model.ChangeApproved, model.ChangeRejected)
+			return
+		}
+		query = query.Where("status = ?", status)
+	}
+	err = query.Order("iban_change_id DESC").Find(&changes).Error
+	return
+}
+
+type IbanChangesQueryArgs struct {
+	GroupID graphql.ID
+	Status  *string
+}
+
+// GetIbanChangesResponse is the response type
+type GetIbanChangesResponse struct {
+	Status  bool
+	Msg     *string
+	Changes []*IbanChangeResponse
+}
+
+// Ok for GetIbanChangesResponse
+func (r *GetIbanChangesResponse) Ok() bool {
+	return r.Status
+}
+
+// Error for GetIbanChangesResponse
+func (r *GetIbanChangesResponse) Error() *string {
+	return r.Msg
+}
@@ -0,0 +1,93 @@
+package resolvers
+
+import (
+	"context"
+
+	graphql "github.com/graph-gophers/graphql-go"
+
+	"github.com/tapsilat/iban.im/config"
+	"github.com/tapsilat/iban.im/handler"
+	"github.com/tapsilat/iban.im/model"
+)
+
+// GetMyPaymentRequests resolver lists the user's payment requests, newest
+// first, optionally for one IBAN or status
+func (r *Resolvers) GetMyPaymentRequests(ctx context.Context, args GetMyPaymentRequestsQueryArgs) (*GetMyPaymentRequestsResponse, error) {
+	userID := ctx.Value(handler.ContextKey("UserID"))
+	if userID == nil {
+		msg := "Not Authorized"
+		return &GetMyPaymentRequestsResponse{Status: false, Msg: &msg, PaymentRequests: []*PaymentRequestResponse{}}, nil
+	}
+
+	query := config.DB.Where("owner_id = ?", userID)
+	if args.IbanID != nil {
+		query = query.Where("iban_id = ?", *args.IbanID)
+	}
+	if args.Status != nil {
+		query = query.Where("status = ?", *args.Status)
+	}
+	var requests []model.PaymentRequest
+	if err := query.Order("created_at desc").Find(&requests).Error; err != nil {
+		msg := err.Error()
+		return &GetMyPaymentRequestsResponse{Status: false, Msg: &msg, PaymentRequests: []*PaymentRequestResponse{}}, nil
+	}
+
+	response := &GetMyPaymentRequestsResponse{Status: true, PaymentRequests: []*PaymentRequestResponse{}}
+	for i := range requests {
+		response.PaymentRequests = append(response.PaymentRequests, &PaymentRequestResponse{p: &requests[i]})
+	}
+	return response, nil
+}
+
+// GetPaymentRequest resolver returns one of the user's payment requests
+func (r *Resolvers) GetPaymentRequest(ctx context.Context, args GetPaymentRequestQueryArgs) (*GetPaymentRequestResponse, error) {
+	request, err := findOwnPaymentRequest(ctx.Value(handler.ContextKey("UserID")), args.Id)
+	if err != nil {
+		msg := err.Error()
+		return &GetPaymentRequestResponse{Status: false, Msg: &msg}, nil
+	}
+	return &GetPaymentRequestResponse{Status: true, PaymentRequest: &PaymentRequestResponse{p: &request}}, nil
+}
+
+type GetMyPaymentRequestsQueryArgs struct {
+	IbanID *graphql.ID
+	Status *string
+}
+
+type GetPaymentRequestQueryArgs struct {
+	Id graphql.ID
+}
+
+// GetMyPaymentRequestsResponse is the response type
+type GetMyPaymentRequestsResponse struct {
+	Status          bool
+	Msg             *string
+	PaymentRequests []*PaymentRequestResponse
+}
+
+// Ok for GetMyPaymentRequestsResponse
+func (r *GetMyPaymentRequestsResponse) Ok() bool {
+	return r.Status
+}
+
+// Error for GetMyPaymentRequestsResponse
+func (r *GetMyPaymentRequestsResponse) Error() *string {
+	return r.Msg
+}
+
+// GetPaymentRequestResponse is the response type
+type GetPaymentRequestResponse struct {
+	Status         bool
+	Msg            *string
+	PaymentRequest *PaymentRequestResponse
+}
+
+// Ok for GetPaymentRequestResponse
+func (r *GetPaymentRequestResponse) Ok() bool {
+	return r.Status
+}
+
+// Error for GetPaymentRequestResponse
+func (r *GetPaymentRequestResponse) Error() *string {
+	return r.Msg
+}
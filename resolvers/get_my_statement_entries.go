@@ -0,0 +1,63 @@
+package resolvers
+
+import (
+	"context"
+
+	graphql "github.com/graph-gophers/graphql-go"
+
+	"github.com/tapsilat/iban.im/config"
+	"github.com/tapsilat/iban.im/handler"
+	"github.com/tapsilat/iban.im/model"
+)
+
+// GetMyStatementEntries resolver lists imported statement entries, newest
+// first. With status "unmatched" it is the review list of payments no
+// payment request was found for.
+func (r *Resolvers) GetMyStatementEntries(ctx context.Context, args GetMyStatementEntriesQueryArgs) (*GetMyStatementEntriesResponse, error) {
+	userID := ctx.Value(handler.ContextKey("UserID"))
+	if userID == nil {
+		msg := "Not Authorized"
+		return &GetMyStatementEntriesResponse{Status: false, Msg: &msg, Entries: []*StatementEntryResponse{}}, nil
+	}
+
+	query := config.DB.Where("owner_id = ?", userID)
+	if args.IbanID != nil {
+		query = query.Where("iban_id = ?", *args.IbanID)
+	}
+	if args.Status != nil {
+		query = query.Where("status = ?", *args.Status)
+	}
+	var entries []model.StatementEntry
+	if err := query.Order("booking_date desc, statement_entry_id desc").Find(&entries).Error; err != nil {
+		msg := err.Error()
+		return &GetMyStatementEntriesResponse{Status: false, Msg: &msg, Entries: []*StatementEntryResponse{}}, nil
+	}
+
+	response := &GetMyStatementEntriesResponse{Status: true, Entries: []*StatementEntryResponse{}}
+	for i := range entries {
+		response.Entries = append(response.Entries, &StatementEntryResponse{e: &entries[i]})
+	}
+	return response, nil
+}
+
+type GetMyStatementEntriesQueryArgs struct {
+	IbanID *graphql.ID
+	Status *string
+}
+
+// GetMyStatementEntriesResponse is the response type
+type GetMyStatementEntriesResponse struct {
+	Status  bool
+	Msg     *string
+	Entries []*StatementEntryResponse
+}
+
+// Ok for GetMyStatementEntriesResponse
+func (r *GetMyStatementEntriesResponse) Ok() bool {
+	return r.Status
+}
+
+// Error for GetMyStatementEntriesResponse
+func (r *GetMyStatementEntriesResponse) Error() *string {
+	return r.Msg
+}
@@ -3,8 +3,10 @@ package resolvers
 import (
 	"context"
 	"fmt"
+	"slices"
 
 	"github.com/tapsilat/iban.im/config"
+	"github.com/tapsilat/iban.im/iso4217"
 	"github.com/tapsilat/iban.im/model"
 )
 
@@ -41,6 +43,20 @@ func (r *Resolvers) GetProfile(ctx context.Context, args ProfileQueryArgs) (resp
 	ibans = r.FindIbanByOwner(int(user.UserID))
 	if len(ibans) == 0 {
 		err = fmt.Errorf("iban is not exist")
+		return
+	}
+	// Payers may only want the accounts they can pay in their currency
+	if args.Currency != nil && *args.Currency != "" {
+		currency := iso4217.Normalize(*args.Currency)
+		if err = iso4217.Validate(currency); err != nil {
+			return
+		}
+		ibans = slices.DeleteFunc(ibans, func(iban model.Iban) bool {
+			return iban.Currency != currency
+		})
+		if len(ibans) == 0 {
+			err = fmt.Errorf("no iban in %s", currency)
+		}
 	}
 	return
 }
@@ -65,4 +81,5 @@ func (r *GetProfileResponse) Error() *string {
 
 type ProfileQueryArgs struct {
 	Username string
+	Currency *string
 }
@@ -0,0 +1,89 @@
+package resolvers
+
+import (
+	"context"
+	"fmt"
+	"log"
+
+	graphql "github.com/graph-gophers/graphql-go"
+
+	"github.com/tapsilat/iban.im/config"
+	"github.com/tapsilat/iban.im/handler"
+	"github.com/tapsilat/iban.im/model"
+)
+
+// GroupDelete mutation deletes a group without IBANs and its memberships,
+// confirmed with the password of an owner like DeleteProfile
+func (r *Resolvers) GroupDelete(ctx context.Context, args GroupDeleteMutationArgs) (response *GroupDeleteResponse, err error) {
+	response = &GroupDeleteResponse{}
+	userID := ctx.Value(handler.ContextKey("UserID"))
+
+	group, _, err := findGroupAs(userID, args.Id, model.RoleOwner)
+	defer func() {
+		if !reportError(&err, &response.Msg) {
+			response.Status = true
+		}
+	}()
+	if err != nil {
+		return
+	}
+
+	user := model.User{}
+	if err = config.DB.First(&user, userID).Error; err != nil {
+		err = fmt.Errorf("user not found")
+		return
+	}
+	if !user.ComparePassword(args.ConfirmPassword) {
+		err = fmt.Errorf("invalid password confirmation")
+		return
+	}
+
+	// IBANs of a group are only deleted with the approval of a second owner
+	// or admin, deleting the group must not bypass it
+	var ibans int64
+	if err = config.DB.Model(&model.Iban{}).Where("owner_id = ? AND owner_type = ?", group.GroupID, model.OwnerGroup).Count(&ibans).Error; err != nil {
+		log.Printf("Error counting IBANs of group %d: %v", group.GroupID, err)
+		err = fmt.Errorf("failed to delete group")
+		return
+	}
+	if ibans > 0 {
+		err = fmt.Errorf("delete the IBANs of the group first")
+		return
+	}
+	if err = config.DB.Where("group_id = ?", group.GroupID).Delete(&model.GroupMember{}).Error; err != nil {
+		log.Printf("Error deleting members of group %d: %v", group.GroupID, err)
+		err = fmt.Errorf("failed to delete group members")
+		return
+	}
+	// Soft delete, the handle stays taken
+	if err = config.DB.Delete(&group).Error; err != nil {
+		log.Printf("Error deleting group %d: %v", group.GroupID, err)
+		err = fmt.Errorf("failed to delete group")
+		return
+	}
+
+	// Log the deletion for auditing (GroupID and Handle only for privacy)
+	log.Printf("Group deleted: GroupID=%d, Handle=%s, by UserID=%d", group.GroupID, group.Handle, user.UserID)
+	return
+}
+
+type GroupDeleteMutationArgs struct {
+	Id              graphql.ID
+	ConfirmPassword string
+}
+
+// GroupDeleteResponse is the response type
+type GroupDeleteResponse struct {
+	Status bool
+	Msg    *string
+}
+
+// Ok for GroupDeleteResponse
+func (r *GroupDeleteResponse) Ok() bool {
+	return r.Status
+}
+
+// Error for GroupDeleteResponse
+func (r *GroupDeleteResponse) Error() *string {
+	return r.Msg
+}
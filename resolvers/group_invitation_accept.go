@@ -0,0 +1,64 @@
+package resolvers
+
+import (
+	"context"
+	"fmt"
+
+	graphql "github.com/graph-gophers/graphql-go"
+
+	"github.com/tapsilat/iban.im/config"
+	"github.com/tapsilat/iban.im/handler"
+	"github.com/tapsilat/iban.im/model"
+)
+
+// GroupInvitationAccept mutation makes the user a member of the group that
+// invited them; groupMemberRemove declines an invitation
+func (r *Resolvers) GroupInvitationAccept(ctx context.Context, args GroupInvitationAcceptMutationArgs) (response *GroupInvitationAcceptResponse, err error) {
+	response = &GroupInvitationAcceptResponse{}
+	userID := ctx.Value(handler.ContextKey("UserID"))
+
+	member, err := findGroupMember(userID, args.Id)
+	defer func() {
+		if reportError(&err, &response.Msg) {
+			return
+		}
+		response.Status = true
+		response.Member = newGroupMemberResponse(&member)
+	}()
+	if err != nil {
+		return
+	}
+	if member.UserID != uint(userID.(int)) {
+		err = fmt.Errorf("not authorized")
+		return
+	}
+	if member.Active() {
+		err = fmt.Errorf("invitation is already accepted")
+		return
+	}
+
+	member.Status = model.MemberActive
+	err = config.DB.Save(&member).Error
+	return
+}
+
+type GroupInvitationAcceptMutationArgs struct {
+	Id graphql.ID
+}
+
+// GroupInvitationAcceptResponse is the response type
+type GroupInvitationAcceptResponse struct {
+	Status bool
+	Msg    *string
+	Member *GroupMemberResponse
+}
+
+// Ok for GroupInvitationAcceptResponse
+func (r *GroupInvitationAcceptResponse) Ok() bool {
+	return r.Status
+}
+
+// Error for GroupInvitationAcceptResponse
+func (r *GroupInvitationAcceptResponse) Error() *string {
+	return r.Msg
+}
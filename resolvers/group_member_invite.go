@@ -0,0 +1,83 @@
+package resolvers
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	graphql "github.com/graph-gophers/graphql-go"
+
+	"github.com/tapsilat/iban.im/config"
+	"github.com/tapsilat/iban.im/handler"
+	"github.com/tapsilat/iban.im/model"
+)
+
+// GroupMemberInvite mutation invites a user to a group by handle; owners and
+// admins may invite, and only owners may invite owners
+func (r *Resolvers) GroupMemberInvite(ctx context.Context, args GroupMemberInviteMutationArgs) (response *GroupMemberInviteResponse, err error) {
+	response = &GroupMemberInviteResponse{}
+	member := model.GroupMember{Role: model.RoleMember, Status: model.MemberInvited}
+
+	defer func() {
+		if reportError(&err, &response.Msg) {
+			return
+		}
+		response.Status = true
+		response.Member = newGroupMemberResponse(&member)
+	}()
+
+	group, inviter, err := findGroupAs(ctx.Value(handler.ContextKey("UserID")), args.GroupID, model.RoleOwner, model.RoleAdmin)
+	if err != nil {
+		return
+	}
+	if args.Role != nil && *args.Role != "" {
+		member.Role = strings.ToLower(strings.TrimSpace(*args.Role))
+	}
+	if member.Role == model.RoleOwner && inviter.Role != model.RoleOwner {
+		err = fmt.Errorf("only owners may invite owners")
+		return
+	}
+
+	user := model.User{}
+	if err = config.DB.Where("handle = ?", strings.ToLower(strings.TrimSpace(args.Handle))).First(&user).Error; err != nil {
+		err = fmt.Errorf("user is not exist")
+		return
+	}
+	if existing, ok := model.FindMembership(config.DB, group.GroupID, user.UserID); ok {
+		if existing.Active() {
+			err = fmt.Errorf("user is already a member")
+		} else {
+			err = fmt.Errorf("user is already invited")
+		}
+		return
+	}
+
+	member.GroupID = group.GroupID
+	member.UserID = user.UserID
+	member.InvitedBy = inviter.UserID
+	err = config.DB.Create(&member).Error
+	return
+}
+
+type GroupMemberInviteMutationArgs struct {
+	GroupID graphql.ID
+	Handle  string
+	Role    *string
+}
+
+// GroupMemberInviteResponse is the response type
+type GroupMemberInviteResponse struct {
+	Status bool
+	Msg    *string
+	Member *GroupMemberResponse
+}
+
+// Ok for GroupMemberInviteResponse
+func (r *GroupMemberInviteResponse) Ok() bool {
+	return r.Status
+}
+
+// Error for GroupMemberInviteResponse
+func (r *GroupMemberInviteResponse) Error() *string {
+	return r.Msg
+}
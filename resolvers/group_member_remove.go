@@ -0,0 +1,69 @@
+package resolvers
+
+import (
+	"context"
+	"fmt"
+
+	graphql "github.com/graph-gophers/graphql-go"
+
+	"github.com/tapsilat/iban.im/config"
+	"github.com/tapsilat/iban.im/handler"
+	"github.com/tapsilat/iban.im/model"
+)
+
+// GroupMemberRemove mutation removes a member or withdraws an invitation.
+// Users may leave or decline themselves, admins may remove members and
+// owners anyone; the last owner cannot leave.
+func (r *Resolvers) GroupMemberRemove(ctx context.Context, args GroupMemberRemoveMutationArgs) (response *GroupMemberRemoveResponse, err error) {
+	response = &GroupMemberRemoveResponse{}
+	userID := ctx.Value(handler.ContextKey("UserID"))
+
+	defer func() {
+		if !reportError(&err, &response.Msg) {
+			response.Status = true
+		}
+	}()
+
+	member, err := findGroupMember(userID, args.Id)
+	if err != nil {
+		return
+	}
+	if member.UserID != uint(userID.(int)) {
+		caller, ok := model.FindMembership(config.DB, member.GroupID, uint(userID.(int)))
+		switch {
+		case !ok || !caller.CanManage():
+			err = fmt.Errorf("not authorized")
+		case caller.Role == model.RoleAdmin && member.Role != model.RoleMember:
+			err = fmt.Errorf("only owners may remove admins and owners")
+		}
+		if err != nil {
+			return
+		}
+	}
+	if lastOwner(member) {
+		err = fmt.Errorf("a group needs an owner")
+		return
+	}
+	err = config.DB.Delete(&member).Error
+	return
+}
+
+type GroupMemberRemoveMutationArgs struct {
+	Id graphql.ID
+}
+
+// GroupMemberRemoveResponse is the response type
+type GroupMemberRemoveResponse struct {
+	Status bool
+	Msg    *string
+}
+
+// Ok for GroupMemberRemoveResponse
+func (r *GroupMemberRemoveResponse) Ok() bool {
+	return r.Status
+}
+
+// Error for GroupMemberRemoveResponse
+func (r *GroupMemberRemoveResponse) Error() *string {
+	return r.Msg
+}
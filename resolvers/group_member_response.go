@@ -0,0 +1,85 @@
+package resolvers
+
+import (
+	"fmt"
+	"strconv"
+
+	graphql "github.com/graph-gophers/graphql-go"
+
+	"github.com/tapsilat/iban.im/config"
+	"github.com/tapsilat/iban.im/model"
+)
+
+// GroupMemberResponse is the group member response type
+type GroupMemberResponse struct {
+	m *model.GroupMember
+	u *model.User
+	g *model.Group
+}
+
+// newGroupMemberResponse loads the user and group of a membership
+func newGroupMemberResponse(member *model.GroupMember) *GroupMemberResponse {
+	user := model.User{}
+	group := model.Group{}
+	config.DB.First(&user, member.UserID)
+	config.DB.Unscoped().First(&group, member.GroupID)
+	return &GroupMemberResponse{m: member, u: &user, g: &group}
+}
+
+// ID for GroupMemberResponse
+func (r *GroupMemberResponse) ID() graphql.ID {
+	return graphql.ID(strconv.Itoa(int(r.m.GroupMemberID)))
+}
+
+// Group for GroupMemberResponse
+func (r *GroupMemberResponse) Group() *GroupResponse {
+	return &GroupResponse{g: r.g}
+}
+
+// Handle for GroupMemberResponse, the handle of the user
+func (r *GroupMemberResponse) Handle() string {
+	return r.u.Handle
+}
+
+// FirstName for GroupMemberResponse
+func (r *GroupMemberResponse) FirstName() string {
+	return r.u.FirstName
+}
+
+// LastName for GroupMemberResponse
+func (r *GroupMemberResponse) LastName() string {
+	return r.u.LastName
+}
+
+// Role for GroupMemberResponse
+func (r *GroupMemberResponse) Role() string {
+	return r.m.Role
+}
+
+// Status for GroupMemberResponse
+func (r *GroupMemberResponse) Status() string {
+	return r.m.Status
+}
+
+// CreatedAt for GroupMemberResponse
+func (r *GroupMemberResponse) CreatedAt() string {
+	return r.m.CreatedAt.String()
+}
+
+// findGroupMember loads a membership by its ID
+func findGroupMember(userID interface{}, id graphql.ID) (model.GroupMember, error) {
+	var member model.GroupMember
+	if userID == nil {
+		return member, fmt.Errorf("not authorized")
+	}
+	if err := config.DB.Where("group_member_id = ?", id).First(&member).Error; err != nil {
+		return member, fmt.Errorf("member is not exist")
+	}
+	return member, nil
+}
+
+// lastOwner reports whether the member is the only active owner of its group,
+// who may not leave or step down
+func lastOwner(member model.GroupMember) bool {
+	return member.Role == model.RoleOwner && member.Active() && model.CountOwners(config.DB, member.GroupID) <= 1
+}
@@ -0,0 +1,72 @@
+package resolvers
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	graphql "github.com/graph-gophers/graphql-go"
+
+	"github.com/tapsilat/iban.im/config"
+	"github.com/tapsilat/iban.im/handler"
+	"github.com/tapsilat/iban.im/model"
+)
+
+// GroupMemberRole mutation changes the role of a member; only owners may,
+// and the last owner cannot step down
+func (r *Resolvers) GroupMemberRole(ctx context.Context, args GroupMemberRoleMutationArgs) (response *GroupMemberRoleResponse, err error) {
+	response = &GroupMemberRoleResponse{}
+	userID := ctx.Value(handler.ContextKey("UserID"))
+
+	member, err := findGroupMember(userID, args.Id)
+	defer func() {
+		if reportError(&err, &response.Msg) {
+			return
+		}
+		response.Status = true
+		response.Member = newGroupMemberResponse(&member)
+	}()
+	if err != nil {
+		return
+	}
+	caller, ok := model.FindMembership(config.DB, member.GroupID, uint(userID.(int)))
+	if !ok || !caller.CanManage() {
+		err = fmt.Errorf("not authorized")
+		return
+	}
+	if caller.Role != model.RoleOwner {
+		err = fmt.Errorf("only owners may change roles")
+		return
+	}
+
+	role := strings.ToLower(strings.TrimSpace(args.Role))
+	if role != model.RoleOwner && lastOwner(member) {
+		err = fmt.Errorf("a group needs an owner")
+		return
+	}
+	member.Role = role
+	err = config.DB.Save(&member).Error
+	return
+}
+
+type GroupMemberRoleMutationArgs struct {
+	Id   graphql.ID
+	Role string
+}
+
+// GroupMemberRoleResponse is the response type
+type GroupMemberRoleResponse struct {
+	Status bool
+	Msg    *string
+	Member *GroupMemberResponse
+}
+
+// Ok for GroupMemberRoleResponse
+func (r *GroupMemberRoleResponse) Ok() bool {
+	return r.Status
+}
+
+// Error for GroupMemberRoleResponse
+func (r *GroupMemberRoleResponse) Error() *string {
+	return r.Msg
+}
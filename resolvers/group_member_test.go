@@ -0,0 +1,148 @@
+package resolvers
+
+import (
+	"strconv"
+	"testing"
+
+	graphql "github.com/graph-gophers/graphql-go"
+
+	"github.com/tapsilat/iban.im/model"
+)
+
+func TestGroupMembership(t *testing.T) {
+	resolver, db, cleanup := setupTestResolverWithDB(t)
+	defer cleanup()
+
+	owner := createTestUser(t, db, "owner@example.com", "pass", "owner", "Olivia", "Owner")
+	admin := createTestUser(t, db, "admin@example.com", "pass", "admin", "Adam", "Admin")
+	member := createTestUser(t, db, "member@example.com", "pass", "member", "Mia", "Member")
+	ownerCtx := contextWithUserID(int(owner.UserID))
+	adminCtx := contextWithUserID(int(admin.UserID))
+	memberCtx := contextWithUserID(int(member.UserID))
+
+	created, _ := resolver.GroupNew(ownerCtx, GroupNewMutationArgs{Handle: "acme", Name: "Acme Ltd"})
+	if !created.Ok() {
+		t.Fatalf("GroupNew failed: %v", *created.Error())
+	}
+	groupID := created.Group.ID()
+
+	invited, _ := resolver.GroupMemberInvite(ownerCtx, GroupMemberInviteMutationArgs{GroupID: groupID, Handle: "admin", Role: strPtr("admin")})
+	if !invited.Ok() || invited.Member.Status() != model.MemberInvited {
+		t.Fatalf("GroupMemberInvite failed: %v", invited.Error())
+	}
+	adminID := invited.Member.ID()
+
+	// Invitations give no rights until accepted
+	resp, _ := resolver.GroupMemberInvite(adminCtx, GroupMemberInviteMutationArgs{GroupID: groupID, Handle: "member"})
+	if resp.Ok() || *resp.Error() != "not authorized" {
+		t.Errorf("Invite by an invited admin: Ok() = %v, Error() = %v", resp.Ok(), resp.Error())
+	}
+	invitations, _ := resolver.GetMyGroupInvitations(adminCtx)
+	if len(invitations.Invitations) != 1 || invitations.Invitations[0].Group().Handle() != "acme" {
+		t.Fatalf("GetMyGroupInvitations = %d invitations, want the one to acme", len(invitations.Invitations))
+	}
+	accepted, _ := resolver.GroupInvitationAccept(memberCtx, GroupInvitationAcceptMutationArgs{Id: adminID})
+	if accepted.Ok() {
+		t.Errorf("Someone else accepted the invitation")
+	}
+	accepted, _ = resolver.GroupInvitationAccept(adminCtx, GroupInvitationAcceptMutationArgs{Id: adminID})
+	if !accepted.Ok() || accepted.Member.Status() != model.MemberActive || accepted.Member.Handle() != "admin" {
+		t.Fatalf("GroupInvitationAccept failed: %v", accepted.Error())
+	}
+
+	tests := []struct {
+		name string
+		args GroupMemberInviteMutationArgs
+		want string
+	}{
+		{name: "Admin invites an owner", args: GroupMemberInviteMutationArgs{Handle: "member", Role: strPtr("owner")}, want: "only owners may invite owners"},
+		{name: "Unknown user", args: GroupMemberInviteMutationArgs{Handle: "nobody"}, want: "user is not exist"},
+		{name: "Existing member", args: GroupMemberInviteMutationArgs{Handle: "owner"}, want: "user is already a member"},
+		{name: "Unknown role", args: GroupMemberInviteMutationArgs{Handle: "member", Role: strPtr("treasurer")}, want: "role must be one of owner, admin or member"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.args.GroupID = groupID
+			resp, _ := resolver.GroupMemberInvite(adminCtx, tt.args)
+			if resp.Ok() || *resp.Error() != tt.want {
+				t.Errorf("Ok() = %v, Error() = %v, want %s", resp.Ok(), resp.Error(), tt.want)
+			}
+		})
+	}
+
+	invited, _ = resolver.GroupMemberInvite(adminCtx, GroupMemberInviteMutationArgs{GroupID: groupID, Handle: "member"})
+	if !invited.Ok() {
+		t.Fatalf("GroupMemberInvite by admin failed: %v", *invited.Error())
+	}
+	memberID := invited.Member.ID()
+	resolver.GroupInvitationAccept(memberCtx, GroupInvitationAcceptMutationArgs{Id: memberID})
+
+	members, _ := resolver.GetGroupMembers(memberCtx, GroupMembersQueryArgs{GroupID: groupID})
+	if !members.Ok() || len(members.Members) != 3 {
+		t.Errorf("GetGroupMembers = %d members, want 3", len(members.Members))
+	}
+	mine, _ := resolver.GetMyGroups(memberCtx)
+	if len(mine.Groups) != 1 {
+		t.Errorf("GetMyGroups of a member = %d groups, want 1", len(mine.Groups))
+	}
+
+	// Only owners and admins may edit the IBANs of the group
+	id, _ := strconv.Atoi(string(groupID))
+	iban := createTestIban(t, db, uint(id), "DE89370400440532013000", "donations", "", false)
+	db.Model(iban).Update("owner_type", model.OwnerGroup)
+	ibanID := graphql.ID(strconv.Itoa(int(iban.IbanID)))
+	update := IbanUpdateMutationArgs{Id: ibanID, Text: "DE89370400440532013000", Handle: "donate"}
+	updated, _ := resolver.IbanUpdate(memberCtx, update)
+	if updated.Ok() || *updated.Error() != "not authorized" {
+		t.Errorf("IbanUpdate by a member: Ok() = %v, Error() = %v", updated.Ok(), updated.Error())
+	}
+	updated, _ = resolver.IbanUpdate(adminCtx, update)
+	if !updated.Ok() {
+		t.Errorf("IbanUpdate by an admin failed: %v", *updated.Error())
+	}
+	deleted, _ := resolver.IbanDelete(memberCtx, IbanDeleteMutationArgs{Id: ibanID})
+	if deleted.Ok() {
+		t.Errorf("IbanDelete by a member succeeded")
+	}
+
+	roleResp, _ := resolver.GroupMemberRole(adminCtx, GroupMemberRoleMutationArgs{Id: memberID, Role: "admin"})
+	if roleResp.Ok() || *roleResp.Error() != "only owners may change roles" {
+		t.Errorf("Role change by an admin: Ok() = %v, Error() = %v", roleResp.Ok(), roleResp.Error())
+	}
+	roleResp, _ = resolver.GroupMemberRole(ownerCtx, GroupMemberRoleMutationArgs{Id: adminID, Role: "Member"})
+	if !roleResp.Ok() || roleResp.Member.Role() != model.RoleMember {
+		t.Errorf("Role change by the owner failed: %v", roleResp.Error())
+	}
+	updated, _ = resolver.IbanUpdate(adminCtx, update)
+	if updated.Ok() {
+		t.Errorf("IbanUpdate by a former admin succeeded")
+	}
+
+	ownerMember, _ := model.FindMembership(db, uint(id), owner.UserID)
+	ownerID := graphql.ID(strconv.Itoa(int(ownerMember.GroupMemberID)))
+	roleResp, _ = resolver.GroupMemberRole(ownerCtx, GroupMemberRoleMutationArgs{Id: ownerID, Role: "admin"})
+	if roleResp.Ok() || *roleResp.Error() != "a group needs an owner" {
+		t.Errorf("Last owner stepping down: Ok() = %v, Error() = %v", roleResp.Ok(), roleResp.Error())
+	}
+	removed, _ := resolver.GroupMemberRemove(ownerCtx, GroupMemberRemoveMutationArgs{Id: ownerID})
+	if removed.Ok() || *removed.Error() != "a group needs an owner" {
+		t.Errorf("Last owner leaving: Ok() = %v, Error() = %v", removed.Ok(), removed.Error())
+	}
+	profile, _ := resolver.DeleteProfile(ownerCtx, deleteProfileMutationArgs{ConfirmPassword: "pass"})
+	if profile.Ok() {
+		t.Errorf("DeleteProfile of the last owner succeeded")
+	}
+
+	removed, _ = resolver.GroupMemberRemove(adminCtx, GroupMemberRemoveMutationArgs{Id: memberID})
+	if removed.Ok() {
+		t.Errorf("A member removed another member")
+	}
+	removed, _ = resolver.GroupMemberRemove(memberCtx, GroupMemberRemoveMutationArgs{Id: memberID})
+	if !removed.Ok() {
+		t.Errorf("Leaving the group failed: %v", *removed.Error())
+	}
+	members, _ = resolver.GetGroupMembers(memberCtx, GroupMembersQueryArgs{GroupID: groupID})
+	if members.Ok() {
+		t.Errorf("A former member can still list the members")
+	}
+}
@@ -0,0 +1,71 @@
+package resolvers
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/tapsilat/iban.im/config"
+	"github.com/tapsilat/iban.im/handler"
+	"github.com/tapsilat/iban.im/model"
+	"gorm.io/gorm"
+)
+
+// GroupNew mutation creates a group managed by the user
+func (r *Resolvers) GroupNew(ctx context.Context, args GroupNewMutationArgs) (response *GroupNewResponse, err error) {
+	response = &GroupNewResponse{}
+	group := model.Group{}
+
+	defer func() {
+		if reportError(&err, &response.Msg) {
+			return
+		}
+		response.Status = true
+		response.Group = &GroupResponse{g: &group}
+	}()
+
+	userID := ctx.Value(handler.ContextKey("UserID"))
+	if userID == nil {
+		err = fmt.Errorf("not authorized")
+		return
+	}
+
+	group.OwnerID = uint(userID.(int))
+	group.Handle = strings.ToLower(strings.TrimSpace(args.Handle))
+	group.GroupName = args.Name
+	group.Active = true
+	groupFields(&group, args.URL, args.Logo)
+	// The creator is the first owner
+	err = config.DB.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Create(&group).Error; err != nil {
+			return err
+		}
+		owner := model.GroupMember{GroupID: group.GroupID, UserID: group.OwnerID, Role: model.RoleOwner, Status: model.MemberActive}
+		return tx.Create(&owner).Error
+	})
+	return
+}
+
+type GroupNewMutationArgs struct {
+	Handle string
+	Name   string
+	URL    *string
+	Logo   *string
+}
+
+// GroupNewResponse is the response type
+type GroupNewResponse struct {
+	Status bool
+	Msg    *string
+	Group  *GroupResponse
+}
+
+// Ok for GroupNewResponse
+func (r *GroupNewResponse) Ok() bool {
+	return r.Status
+}
+
+// Error for GroupNewResponse
+func (r *GroupNewResponse) Error() *string {
+	return r.Msg
+}
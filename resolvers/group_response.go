@@ -0,0 +1,94 @@
+package resolvers
+
+import (
+	"fmt"
+	"slices"
+	"strconv"
+	"time"
+
+	graphql "github.com/graph-gophers/graphql-go"
+
+	"github.com/tapsilat/iban.im/config"
+	"github.com/tapsilat/iban.im/model"
+)
+
+// GroupResponse is the group response type
+type GroupResponse struct {
+	g *model.Group
+}
+
+// ID for GroupResponse
+func (r *GroupResponse) ID() graphql.ID {
+	return graphql.ID(strconv.Itoa(int(r.g.GroupID)))
+}
+
+// Handle for GroupResponse
+func (r *GroupResponse) Handle() string {
+	return r.g.Handle
+}
+
+// Name for GroupResponse
+func (r *GroupResponse) Name() string {
+	return r.g.GroupName
+}
+
+// URL for GroupResponse
+func (r *GroupResponse) URL() *string {
+	return optional(r.g.GroupURL)
+}
+
+// Logo for GroupResponse
+func (r *GroupResponse) Logo() *string {
+	return optional(r.g.GroupLogo)
+}
+
+// Verified for GroupResponse
+func (r *GroupResponse) Verified() bool {
+	return r.g.Verified
+}
+
+// VerifiedAt for GroupResponse
+func (r *GroupResponse) VerifiedAt() *string {
+	if r.g.VerifiedAt == nil {
+		return nil
+	}
+	verifiedAt := r.g.VerifiedAt.UTC().Format(time.RFC3339)
+	return &verifiedAt
+}
+
+// CreatedAt for GroupResponse
+func (r *GroupResponse) CreatedAt() string {
+	return r.g.CreatedAt.String()
+}
+
+// UpdatedAt for GroupResponse
+func (r *GroupResponse) UpdatedAt() string {
+	return r.g.UpdatedAt.String()
+}
+
+// findGroupAs loads a group that the user is an active member of in one of
+// the roles
+func findGroupAs(userID interface{}, id graphql.ID, roles ...string) (model.Group, model.GroupMember, error) {
+	var group model.Group
+	if userID == nil {
+		return group, model.GroupMember{}, fmt.Errorf("not authorized")
+	}
+	if err := config.DB.Where("group_id = ?", id).First(&group).Error; err != nil {
+		return group, model.GroupMember{}, fmt.Errorf("group is not exist")
+	}
+	member, ok := model.FindMembership(config.DB, group.GroupID, uint(userID.(int)))
+	if !ok || !member.Active() || !slices.Contains(roles, member.Role) {
+		return group, member, fmt.Errorf("not authorized")
+	}
+	return group, member, nil
+}
+
+// groupFields applies the optional fields shared by groupNew and groupUpdate
+func groupFields(group *model.Group, url, logo *string) {
+	if url != nil {
+		group.SetURL(*url)
+	}
+	if logo != nil {
+		group.GroupLogo = *logo
+	}
+}
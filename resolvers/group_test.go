@@ -0,0 +1,321 @@
+package resolvers
+
+import (
+	"context"
+	"strconv"
+	"testing"
+
+	graphql "github.com/graph-gophers/graphql-go"
+
+	"github.com/tapsilat/iban.im/domainverify"
+	"github.com/tapsilat/iban.im/model"
+)
+
+func TestGroupNew(t *testing.T) {
+	tests := []struct {
+		name          string
+		args          GroupNewMutationArgs
+		withContext   bool
+		expectSuccess bool
+		expectError   string
+	}{
+		{
+			name:          "Website as bare domain",
+			args:          GroupNewMutationArgs{Handle: " Acme ", Name: "Acme Ltd", URL: strPtr("acme.example"), Logo: strPtr("https://acme.example/logo.png")},
+			withContext:   true,
+			expectSuccess: true,
+		},
+		{
+			name:          "Name only",
+			args:          GroupNewMutationArgs{Handle: "chess-club", Name: "Chess Club"},
+			withContext:   true,
+			expectSuccess: true,
+		},
+		{
+			name:        "Without context",
+			args:        GroupNewMutationArgs{Handle: "acme", Name: "Acme Ltd"},
+			expectError: "not authorized",
+		},
+		{
+			name:        "Handle taken",
+			args:        GroupNewMutationArgs{Handle: "taken", Name: "Acme Ltd"},
+			withContext: true,
+			expectError: "handle already exist",
+		},
+		{
+			name:        "Handle taken by a deleted group",
+			args:        GroupNewMutationArgs{Handle: "deleted", Name: "Acme Ltd"},
+			withContext: true,
+			expectError: "handle already exist",
+		},
+		{
+			name:        "Handle with spaces",
+			args:        GroupNewMutationArgs{Handle: "acme ltd", Name: "Acme Ltd"},
+			withContext: true,
+			expectError: "handle must be 2 to 50 lower case letters, digits, - or _",
+		},
+		{
+			name:        "Without name",
+			args:        GroupNewMutationArgs{Handle: "acme", Name: " "},
+			withContext: true,
+			expectError: "you have to provide the group name",
+		},
+		{
+			name:        "Plain http logo",
+			args:        GroupNewMutationArgs{Handle: "acme", Name: "Acme Ltd", Logo: strPtr("http://acme.example/logo.png")},
+			withContext: true,
+			expectError: "logo must be an https address",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			resolver, db, cleanup := setupTestResolverWithDB(t)
+			defer cleanup()
+
+			user := createTestUser(t, db, "test@example.com", "pass", "testuser", "Test", "User")
+			db.Create(&model.Group{GroupName: "Taken", Handle: "taken", OwnerID: user.UserID})
+			deleted := model.Group{GroupName: "Deleted", Handle: "deleted", OwnerID: user.UserID}
+			db.Create(&deleted)
+			db.Delete(&deleted)
+
+			ctx := context.Background()
+			if tt.withContext {
+				ctx = contextWithUserID(int(user.UserID))
+			}
+			resp, err := resolver.GroupNew(ctx, tt.args)
+			if err != nil {
+				t.Fatalf("GroupNew returned unexpected error: %v", err)
+			}
+			if resp.Ok() != tt.expectSuccess {
+				t.Errorf("Ok() = %v, want %v (error %v)", resp.Ok(), tt.expectSuccess, resp.Error())
+			}
+			if tt.expectError != "" && (resp.Error() == nil || *resp.Error() != tt.expectError) {
+				t.Errorf("Error() = %v, want %s", resp.Error(), tt.expectError)
+			}
+		})
+	}
+}
+
+func TestGroupLifecycle(t *testing.T) {
+	resolver, db, cleanup := setupTestResolverWithDB(t)
+	defer cleanup()
+
+	user := createTestUser(t, db, "test@example.com", "pass", "testuser", "Test", "User")
+	other := createTestUser(t, db, "other@example.com", "pass", "other", "Other", "User")
+	ctx := contextWithUserID(int(user.UserID))
+	created, _ := resolver.GroupNew(ctx, GroupNewMutationArgs{Handle: "acme", Name: "Acme Ltd", URL: strPtr("acme.example")})
+	if !created.Ok() {
+		t.Fatalf("GroupNew failed: %v", *created.Error())
+	}
+	group := created.Group
+	if *group.URL() != "https://acme.example" || group.Verified() {
+		t.Errorf("URL() = %s, Verified() = %v, want https://acme.example unverified", *group.URL(), group.Verified())
+	}
+
+	resp, _ := resolver.GroupUpdate(contextWithUserID(int(other.UserID)), GroupUpdateMutationArgs{Id: group.ID(), Name: strPtr("Not Acme")})
+	if resp.Ok() || *resp.Error() != "not authorized" {
+		t.Errorf("Update by someone else: Ok() = %v, Error() = %v", resp.Ok(), resp.Error())
+	}
+	resp, _ = resolver.GroupUpdate(ctx, GroupUpdateMutationArgs{Id: group.ID(), Handle: strPtr("Acme-Ltd"), Logo: strPtr("https://acme.example/logo.png")})
+	if !resp.Ok() {
+		t.Fatalf("GroupUpdate failed: %v", *resp.Error())
+	}
+	if resp.Group.Handle() != "acme-ltd" || resp.Group.Name() != "Acme Ltd" || *resp.Group.Logo() != "https://acme.example/logo.png" {
+		t.Errorf("Group = %s %s %s, want the new handle and logo and the old name", resp.Group.Handle(), resp.Group.Name(), *resp.Group.Logo())
+	}
+	taken, _ := resolver.GroupNew(contextWithUserID(int(other.UserID)), GroupNewMutationArgs{Handle: "acme", Name: "Not Acme"})
+	if taken.Ok() || *taken.Error() != "handle already exist" {
+		t.Errorf("GroupNew with the old handle: Ok() = %v, Error() = %v", taken.Ok(), taken.Error())
+	}
+
+	got, _ := resolver.GetGroup(ctx, GroupQueryArgs{Handle: "ACME-LTD"})
+	if !got.Ok() || got.Group.Name() != "Acme Ltd" {
+		t.Errorf("GetGroup: Ok() = %v, Error() = %v", got.Ok(), got.Error())
+	}
+	mine, _ := resolver.GetMyGroups(ctx)
+	if !mine.Ok() || len(mine.Groups) != 1 {
+		t.Errorf("GetMyGroups = %d groups, want 1", len(mine.Groups))
+	}
+	mine, _ = resolver.GetMyGroups(contextWithUserID(int(other.UserID)))
+	if !mine.Ok() || len(mine.Groups) != 0 {
+		t.Errorf("GetMyGroups of someone else = %d groups, want none", len(mine.Groups))
+	}
+
+	groupID, _ := strconv.Atoi(string(group.ID()))
+	groupIban := createTestIban(t, db, uint(groupID), "DE89370400440532013000", "donations", "", false)
+	db.Model(groupIban).Update("owner_type", "Group")
+	userIban := createTestIban(t, db, user.UserID, "GB82WEST12345698765432", "rent", "", false)
+
+	deleted, _ := resolver.GroupDelete(ctx, GroupDeleteMutationArgs{Id: group.ID(), ConfirmPassword: "wrong"})
+	if deleted.Ok() || *deleted.Error() != "invalid password confirmation" {
+		t.Errorf("Delete with wrong password: Ok() = %v, Error() = %v", deleted.Ok(), deleted.Error())
+	}
+	deleted, _ = resolver.GroupDelete(ctx, GroupDeleteMutationArgs{Id: group.ID(), ConfirmPassword: "pass"})
+	if deleted.Ok() || *deleted.Error() != "delete the IBANs of the group first" {
+		t.Errorf("Delete with IBANs: Ok() = %v, Error() = %v", deleted.Ok(), deleted.Error())
+	}
+	if err := db.First(&model.Iban{}, groupIban.IbanID).Error; err != nil {
+		t.Errorf("IBANs of the group must be kept: %v", err)
+	}
+	// As if a second owner approved the deletion
+	db.Delete(groupIban)
+	deleted, _ = resolver.GroupDelete(ctx, GroupDeleteMutationArgs{Id: group.ID(), ConfirmPassword: "pass"})
+	if !deleted.Ok() {
+		t.Fatalf("GroupDelete failed: %v", *deleted.Error())
+	}
+
+	got, _ = resolver.GetGroup(ctx, GroupQueryArgs{Handle: "acme-ltd"})
+	if got.Ok() || *got.Error() != "group is not exist" {
+		t.Errorf("GetGroup after delete: Ok() = %v, Error() = %v", got.Ok(), got.Error())
+	}
+	var count int64
+	db.Unscoped().Model(&model.Group{}).Where("handle = ? AND deleted_at IS NOT NULL", "acme-ltd").Count(&count)
+	if count != 1 {
+		t.Errorf("Group must be soft deleted")
+	}
+	if err := db.First(&model.Iban{}, userIban.IbanID).Error; err != nil {
+		t.Errorf("IBANs of the user must be kept: %v", err)
+	}
+}
+
+func TestGroupIbans(t *testing.T) {
+	resolver, db, cleanup := setupTestResolverWithDB(t)
+	defer cleanup()
+
+	owner := createTestUser(t, db, "owner@example.com", "pass", "owner", "Olivia", "Owner")
+	member := createTestUser(t, db, "member@example.com", "pass", "member", "Mia", "Member")
+	ownerCtx := contextWithUserID(int(owner.UserID))
+	memberCtx := contextWithUserID(int(member.UserID))
+	created, _ := resolver.GroupNew(ownerCtx, GroupNewMutationArgs{Handle: "acme", Name: "Acme Ltd"})
+	if !created.Ok() {
+		t.Fatalf("GroupNew failed: %v", *created.Error())
+	}
+	groupID := created.Group.ID()
+	invited, _ := resolver.GroupMemberInvite(ownerCtx, GroupMemberInviteMutationArgs{GroupID: groupID, Handle: "member"})
+	resolver.GroupInvitationAccept(memberCtx, GroupInvitationAcceptMutationArgs{Id: invited.Member.ID()})
+
+	// The owner's own IBAN may use the same handle as the group's
+	createTestIban(t, db, owner.UserID, "GB82WEST12345698765432", "donations", "", false)
+
+	unknown := graphql.ID("999")
+	tests := []struct {
+		name      string
+		ctx       context.Context
+		groupID   *graphql.ID
+		handle    string
+		isPrivate bool
+		password  string
+		want      string
+	}{
+		{name: "Plain member", ctx: memberCtx, groupID: &groupID, handle: "donations", want: "Not Authorized"},
+		{name: "Unknown group", ctx: ownerCtx, groupID: &unknown, handle: "donations", want: "group is not exist"},
+		{name: "Owner", ctx: ownerCtx, groupID: &groupID, handle: "donations"},
+		{name: "Private IBAN", ctx: ownerCtx, groupID: &groupID, handle: "payroll", isPrivate: true, password: "secret"},
+		{name: "Handle already used by the group", ctx: ownerCtx, groupID: &groupID, handle: "donations", want: "handle already exist"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			resp, _ := resolver.IbanNew(tt.ctx, IbanNewMutationArgs{GroupID: tt.groupID, Text: strPtr("DE89370400440532013000"), Handle: tt.handle, IsPrivate: tt.isPrivate, Password: tt.password})
+			if tt.want != "" {
+				if resp.Ok() || *resp.Error() != tt.want {
+					t.Errorf("Ok() = %v, Error() = %v, want %s", resp.Ok(), resp.Error(), tt.want)
+				}
+				return
+			}
+			if !resp.Ok() {
+				t.Fatalf("IbanNew failed: %v", *resp.Error())
+			}
+			if resp.Iban.OwnerType() != model.OwnerGroup {
+				t.Errorf("OwnerType() = %s, want %s", resp.Iban.OwnerType(), model.OwnerGroup)
+			}
+		})
+	}
+
+	got, _ := resolver.GetGroup(context.Background(), GroupQueryArgs{Handle: "acme"})
+	if !got.Ok() || len(got.Ibans) != 1 || got.Ibans[0].Handle() != "donations" {
+		t.Errorf("GetGroup of a visitor = %d IBANs, want the public one", len(got.Ibans))
+	}
+	got, _ = resolver.GetGroup(memberCtx, GroupQueryArgs{Handle: "acme"})
+	if !got.Ok() || len(got.Ibans) != 1 || got.Ibans[0].Handle() != "donations" {
+		t.Errorf("GetGroup of a member = %d IBANs, want the public one", len(got.Ibans))
+	}
+	for _, iban := range got.Ibans {
+		if iban.Password() != "" {
+			t.Errorf("GetGroup of a member shows the password hash of %s", iban.Handle())
+		}
+	}
+	got, _ = resolver.GetGroup(ownerCtx, GroupQueryArgs{Handle: "acme"})
+	if !got.Ok() || len(got.Ibans) != 2 {
+		t.Errorf("GetGroup of an owner = %d IBANs, want 2", len(got.Ibans))
+	}
+	mine, _ := resolver.GetMyIbans(ownerCtx)
+	if len(*mine.Iban) != 1 {
+		t.Errorf("GetMyIbans = %d IBANs, want only the owner's own", len(*mine.Iban))
+	}
+}
+
+type fakeDomains map[string][]string
+
+func (f fakeDomains) LookupTXT(ctx context.Context, name string) ([]string, error) {
+	return f[name], nil
+}
+
+func TestGroupVerify(t *testing.T) {
+	resolver, db, cleanup := setupTestResolverWithDB(t)
+	defer cleanup()
+	dns := fakeDomains{}
+	original := domainVerifier
+	domainVerifier = domainverify.Verifier{DNS: dns}
+	defer func() { domainVerifier = original }()
+
+	user := createTestUser(t, db, "test@example.com", "pass", "testuser", "Test", "User")
+	other := createTestUser(t, db, "other@example.com", "pass", "other", "Other", "User")
+	ctx := contextWithUserID(int(user.UserID))
+	created, _ := resolver.GroupNew(ctx, GroupNewMutationArgs{Handle: "acme", Name: "Acme Ltd"})
+	groupID := created.Group.ID()
+
+	requested, _ := resolver.GroupVerifyRequest(ctx, GroupVerifyMutationArgs{Id: groupID})
+	if requested.Ok() || *requested.Error() != "the group has no website to verify" {
+		t.Errorf("Request without website: Ok() = %v, Error() = %v", requested.Ok(), requested.Error())
+	}
+	resolver.GroupUpdate(ctx, GroupUpdateMutationArgs{Id: groupID, URL: strPtr("www.acme.example/about")})
+	verified, _ := resolver.GroupVerify(ctx, GroupVerifyMutationArgs{Id: groupID})
+	if verified.Ok() || *verified.Error() != "request a verification token first" {
+		t.Errorf("Verify without token: Ok() = %v, Error() = %v", verified.Ok(), verified.Error())
+	}
+	requested, _ = resolver.GroupVerifyRequest(contextWithUserID(int(other.UserID)), GroupVerifyMutationArgs{Id: groupID})
+	if requested.Ok() {
+		t.Errorf("Someone else requested a token")
+	}
+
+	requested, _ = resolver.GroupVerifyRequest(ctx, GroupVerifyMutationArgs{Id: groupID})
+	if !requested.Ok() || *requested.Domain != "www.acme.example" || *requested.FileURL != "https://www.acme.example/.well-known/iban-im.txt" {
+		t.Fatalf("GroupVerifyRequest: Ok() = %v, Error() = %v", requested.Ok(), requested.Error())
+	}
+	token := *requested.Token
+	again, _ := resolver.GroupVerifyRequest(ctx, GroupVerifyMutationArgs{Id: groupID})
+	if *again.Token != token {
+		t.Errorf("Token changed from %s to %s", token, *again.Token)
+	}
+
+	verified, _ = resolver.GroupVerify(ctx, GroupVerifyMutationArgs{Id: groupID})
+	if verified.Ok() || verified.Group != nil {
+		t.Errorf("Verified without published token")
+	}
+	dns["www.acme.example"] = []string{*requested.Record}
+	verified, _ = resolver.GroupVerify(ctx, GroupVerifyMutationArgs{Id: groupID})
+	if !verified.Ok() || *verified.Method != domainverify.MethodDNS || !verified.Group.Verified() || verified.Group.VerifiedAt() == nil {
+		t.Fatalf("GroupVerify: Ok() = %v, Error() = %v", verified.Ok(), verified.Error())
+	}
+
+	// Another page on the same domain keeps the verification, another domain not
+	updated, _ := resolver.GroupUpdate(ctx, GroupUpdateMutationArgs{Id: groupID, URL: strPtr("https://www.acme.example/contact")})
+	if !updated.Group.Verified() {
+		t.Errorf("Verification lost on the same domain")
+	}
+	updated, _ = resolver.GroupUpdate(ctx, GroupUpdateMutationArgs{Id: groupID, URL: strPtr("acme.example")})
+	if updated.Group.Verified() || updated.Group.VerifiedAt() != nil {
+		t.Errorf("Verification kept for another domain")
+	}
+}
@@ -0,0 +1,74 @@
+package resolvers
+
+import (
+	"context"
+	"strings"
+
+	graphql "github.com/graph-gophers/graphql-go"
+	"gorm.io/gorm"
+
+	"github.com/tapsilat/iban.im/config"
+	"github.com/tapsilat/iban.im/handler"
+	"github.com/tapsilat/iban.im/model"
+)
+
+// GroupUpdate mutation changes the name, handle, website or logo of a group;
+// owners and admins may edit it. The old handle stays reserved for the group.
+func (r *Resolvers) GroupUpdate(ctx context.Context, args GroupUpdateMutationArgs) (response *GroupUpdateResponse, err error) {
+	response = &GroupUpdateResponse{}
+
+	group, _, err := findGroupAs(ctx.Value(handler.ContextKey("UserID")), args.Id, model.RoleOwner, model.RoleAdmin)
+	defer func() {
+		if reportError(&err, &response.Msg) {
+			return
+		}
+		response.Status = true
+		response.Group = &GroupResponse{g: &group}
+	}()
+	if err != nil {
+		return
+	}
+
+	old := group.Handle
+	if args.Handle != nil {
+		group.Handle = strings.ToLower(strings.TrimSpace(*args.Handle))
+	}
+	if args.Name != nil {
+		group.GroupName = *args.Name
+	}
+	groupFields(&group, args.URL, args.Logo)
+	err = config.DB.Transaction(func(tx *gorm.DB) error {
+		if group.Handle != old {
+			if err := group.RetireHandle(tx, old); err != nil {
+				return err
+			}
+		}
+		return tx.Save(&group).Error
+	})
+	return
+}
+
+type GroupUpdateMutationArgs struct {
+	Id     graphql.ID
+	Handle *string
+	Name   *string
+	URL    *string
+	Logo   *string
+}
+
+// GroupUpdateResponse is the response type
+type GroupUpdateResponse struct {
+	Status bool
+	Msg    *string
+	Group  *GroupResponse
+}
+
+// Ok for GroupUpdateResponse
+func (r *GroupUpdateResponse) Ok() bool {
+	return r.Status
+}
+
+// Error for GroupUpdateResponse
+func (r *GroupUpdateResponse) Error() *string {
+	return r.Msg
+}
@@ -0,0 +1,140 @@
+package resolvers
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"time"
+
+	graphql "github.com/graph-gophers/graphql-go"
+
+	"github.com/tapsilat/iban.im/config"
+	"github.com/tapsilat/iban.im/domainverify"
+	"github.com/tapsilat/iban.im/handler"
+	"github.com/tapsilat/iban.im/model"
+)
+
+// domainVerifier checks the domains of groups; tests replace it with fakes
+var domainVerifier = domainverify.Default
+
+// GroupVerifyRequest mutation gives the owners and admins of a group the
+// token to publish on the domain of its website. The token is kept across
+// requests so that a published record stays valid.
+func (r *Resolvers) GroupVerifyRequest(ctx context.Context, args GroupVerifyMutationArgs) (response *GroupVerifyRequestResponse, err error) {
+	response = &GroupVerifyRequestResponse{}
+
+	group, _, err := findGroupAs(ctx.Value(handler.ContextKey("UserID")), args.Id, model.RoleOwner, model.RoleAdmin)
+	defer func() {
+		if reportError(&err, &response.Msg) {
+			return
+		}
+		response.Status = true
+		domain := group.Domain()
+		response.Domain = &domain
+		response.Token = &group.VerifyToken
+		record := domainverify.Record(group.VerifyToken)
+		response.Record = &record
+		fileURL := domainverify.FileURL(domain)
+		response.FileURL = &fileURL
+	}()
+	if err != nil {
+		return
+	}
+	if group.Domain() == "" {
+		err = fmt.Errorf("the group has no website to verify")
+		return
+	}
+	if group.VerifyToken != "" {
+		return
+	}
+	if err = group.NewVerifyToken(); err != nil {
+		return
+	}
+	err = config.DB.Model(&group).Update("verify_token", group.VerifyToken).Error
+	return
+}
+
+// GroupVerify mutation looks for the token on the domain of the group's
+// website and marks the group verified when found
+func (r *Resolvers) GroupVerify(ctx context.Context, args GroupVerifyMutationArgs) (response *GroupVerifyResponse, err error) {
+	response = &GroupVerifyResponse{}
+	var method string
+
+	group, _, err := findGroupAs(ctx.Value(handler.ContextKey("UserID")), args.Id, model.RoleOwner, model.RoleAdmin)
+	defer func() {
+		if reportError(&err, &response.Msg) {
+			return
+		}
+		response.Status = true
+		response.Method = optional(method)
+		response.Group = &GroupResponse{g: &group}
+	}()
+	if err != nil {
+		return
+	}
+	domain := group.Domain()
+	if domain == "" {
+		err = fmt.Errorf("the group has no website to verify")
+		return
+	}
+	if group.VerifyToken == "" {
+		err = fmt.Errorf("request a verification token first")
+		return
+	}
+
+	if method, err = domainVerifier.Verify(ctx, domain, group.VerifyToken); err != nil {
+		return
+	}
+	now := time.Now()
+	group.Verified, group.VerifiedAt = true, &now
+	if err = config.DB.Save(&group).Error; err != nil {
+		return
+	}
+	log.Printf("Group %s verified %s by %s", group.Handle, domain, method)
+	return
+}
+
+type GroupVerifyMutationArgs struct {
+	Id graphql.ID
+}
+
+// GroupVerifyRequestResponse is the response type
+type GroupVerifyRequestResponse struct {
+	Status bool
+	Msg    *string
+	Domain *string
+	Token  *string
+	// Record is the TXT record to publish on Domain
+	Record *string
+	// FileURL is where the token may be published instead
+	FileURL *string
+}
+
+// Ok for GroupVerifyRequestResponse
+func (r *GroupVerifyRequestResponse) Ok() bool {
+	return r.Status
+}
+
+// Error for GroupVerifyRequestResponse
+func (r *GroupVerifyRequestResponse) Error() *string {
+	return r.Msg
+}
+
+// GroupVerifyResponse is the response type
+type GroupVerifyResponse struct {
+	Status bool
+	Msg    *string
+	// Method is dns or http, whichever found the token
+	Method *string
+	Group  *GroupResponse
+}
+
+// Ok for GroupVerifyResponse
+func (r *GroupVerifyResponse) Ok() bool {
+	return r.Status
+}
+
+// Error for GroupVerifyResponse
+func (r *GroupVerifyResponse) Error() *string {
+	return r.Msg
+}
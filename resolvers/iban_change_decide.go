@@ -0,0 +1,107 @@
+package resolvers
+
+import (
+	"context"
+	"fmt"
+	"log"
+
+	graphql "github.com/graph-gophers/graphql-go"
+	"gorm.io/gorm"
+
+	"github.com/tapsilat/iban.im/config"
+	"github.com/tapsilat/iban.im/handler"
+	"github.com/tapsilat/iban.im/model"
+)
+
+// IbanChangeApprove mutation carries out a pending change of a group IBAN.
+// Only an owner or admin other than the one who requested it may approve.
+func (r *Resolvers) IbanChangeApprove(ctx context.Context, args IbanChangeApproveMutationArgs) (response *IbanChangeDecideResponse, err error) {
+	response = &IbanChangeDecideResponse{}
+	userID := ctx.Value(handler.ContextKey("UserID"))
+
+	change, err := findIbanChange(userID, args.Id)
+	defer func() {
+		if reportError(&err, &response.Msg) {
+			return
+		}
+		response.Status = true
+		response.Change = &IbanChangeResponse{c: &change}
+	}()
+	if err != nil {
+		return
+	}
+	if change.RequestedBy == uint(userID.(int)) {
+		err = fmt.Errorf("a second owner or admin must approve the change")
+		return
+	}
+
+	change.Decide(model.ChangeApproved, uint(userID.(int)), "")
+	err = config.DB.Transaction(func(tx *gorm.DB) error {
+		if err := change.Apply(tx); err != nil {
+			return err
+		}
+		return tx.Save(&change).Error
+	})
+	if err != nil {
+		return
+	}
+	log.Printf("IBAN %d of group %d: %s %d approved by user %d", change.IbanID, change.GroupID, change.Action, change.IbanChangeID, change.DecidedBy)
+	return
+}
+
+// IbanChangeReject mutation discards a pending change of a group IBAN; the
+// requester may withdraw it the same way
+func (r *Resolvers) IbanChangeReject(ctx context.Context, args IbanChangeRejectMutationArgs) (response *IbanChangeDecideResponse, err error) {
+	response = &IbanChangeDecideResponse{}
+	userID := ctx.Value(handler.ContextKey("UserID"))
+
+	change, err := findIbanChange(userID, args.Id)
+	defer func() {
+		if reportError(&err, &response.Msg) {
+			return
+		}
+		response.Status = true
+		response.Change = &IbanChangeResponse{c: &change}
+	}()
+	if err != nil {
+		return
+	}
+
+	reason := ""
+	if args.Reason != nil {
+		reason = *args.Reason
+	}
+	change.Decide(model.ChangeRejected, uint(userID.(int)), reason)
+	if err = config.DB.Save(&change).Error; err != nil {
+		return
+	}
+	log.Printf("IBAN %d of group %d: %s %d rejected by user %d", change.IbanID, change.GroupID, change.Action, change.IbanChangeID, change.DecidedBy)
+	return
+}
+
+type IbanChangeApproveMutationArgs struct {
+	Id graphql.ID
+}
+
+type IbanChangeRejectMutationArgs struct {
+	Id graphql.ID
+	// Reason is why the change is rejected
+	Reason *string
+}
+
+// IbanChangeDecideResponse is the response type
+type IbanChangeDecideResponse struct {
+	Status bool
+	Msg    *string
+	Change *IbanChangeResponse
+}
+
+// Ok for IbanChangeDecideResponse
+func (r *IbanChangeDecideResponse) Ok() bool {
+	return r.Status
+}
+
+// Error for IbanChangeDecideResponse
+func (r *IbanChangeDecideResponse) Error() *string {
+	return r.Msg
+}
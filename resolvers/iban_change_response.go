@@ -0,0 +1,138 @@
+package resolvers
+
+import (
+	"fmt"
+	"log"
+	"strconv"
+	"time"
+
+	graphql "github.com/graph-gophers/graphql-go"
+
+	"github.com/tapsilat/iban.im/config"
+	"github.com/tapsilat/iban.im/model"
+)
+
+// IbanChangeResponse is the pending or decided IBAN change response type
+type IbanChangeResponse struct {
+	c *model.IbanChange
+}
+
+// ID for IbanChangeResponse
+func (r *IbanChangeResponse) ID() graphql.ID {
+	return graphql.ID(strconv.Itoa(int(r.c.IbanChangeID)))
+}
+
+// IbanID for IbanChangeResponse, also of IBANs deleted since
+func (r *IbanChangeResponse) IbanID() graphql.ID {
+	return graphql.ID(strconv.Itoa(int(r.c.IbanID)))
+}
+
+// GroupID for IbanChangeResponse
+func (r *IbanChangeResponse) GroupID() graphql.ID {
+	return graphql.ID(strconv.Itoa(int(r.c.GroupID)))
+}
+
+// Action for IbanChangeResponse
+func (r *IbanChangeResponse) Action() string {
+	return r.c.Action
+}
+
+// Status for IbanChangeResponse
+func (r *IbanChangeResponse) Status() string {
+	return r.c.Status
+}
+
+// Iban for IbanChangeResponse, as it will be after an update or as it was
+// before a deletion. The password hash stays in the snapshot for Apply but
+// is left out, as all members of the group see the changes.
+func (r *IbanChangeResponse) Iban() *IbanResponse {
+	iban, err := r.c.Iban()
+	if err != nil {
+		log.Printf("Failed to decode IBAN change %d: %v", r.c.IbanChangeID, err)
+		return nil
+	}
+	iban.Password = ""
+	return &IbanResponse{i: &iban}
+}
+
+// RequestedBy for IbanChangeResponse, the handle of the user
+func (r *IbanChangeResponse) RequestedBy() string {
+	return userHandle(r.c.RequestedBy)
+}
+
+// DecidedBy for IbanChangeResponse, the handle of the user
+func (r *IbanChangeResponse) DecidedBy() *string {
+	if r.c.DecidedBy == 0 {
+		return nil
+	}
+	return optional(userHandle(r.c.DecidedBy))
+}
+
+// DecidedAt for IbanChangeResponse
+func (r *IbanChangeResponse) DecidedAt() *string {
+	if r.c.DecidedAt == nil {
+		return nil
+	}
+	decidedAt := r.c.DecidedAt.UTC().Format(time.RFC3339)
+	return &decidedAt
+}
+
+// Reason for IbanChangeResponse
+func (r *IbanChangeResponse) Reason() *string {
+	return optional(r.c.Reason)
+}
+
+// CreatedAt for IbanChangeResponse
+func (r *IbanChangeResponse) CreatedAt() string {
+	return r.c.CreatedAt.String()
+}
+
+// userHandle is the handle of a user, empty for deleted users
+func userHandle(userID uint) string {
+	user := model.User{}
+	config.DB.First(&user, userID)
+	return user.Handle
+}
+
+// findIbanChange loads a change that the user may decide on as owner or
+// admin of the group
+func findIbanChange(userID interface{}, id graphql.ID) (model.IbanChange, error) {
+	var change model.IbanChange
+	if userID == nil {
+		return change, fmt.Errorf("not authorized")
+	}
+	if err := config.DB.Where("iban_change_id = ?", id).First(&change).Error; err != nil {
+		return change, fmt.Errorf("change is not exist")
+	}
+	if !model.CanManageGroup(config.DB, change.GroupID, uint(userID.(int))) {
+		return change, fmt.Errorf("not authorized")
+	}
+	if !change.Pending() {
+		return change, fmt.Errorf("change is already %s", change.Status)
+	}
+	return change, nil
+}
+
+// stageIbanChange records an update or deletion of a group IBAN for a second
+// owner or admin to approve
+func stageIbanChange(userID interface{}, action string, iban model.Iban) (*IbanChangeResponse, error) {
+	if model.HasPendingChange(config.DB, iban.IbanID) {
+		return nil, fmt.Errorf("the IBAN already has a pending change")
+	}
+	if action == model.ChangeUpdate && iban.CheckHandle(config.DB) {
+		return nil, fmt.Errorf("handle already exist")
+	}
+	change, err := model.NewIbanChange(action, iban, uint(userID.(int)))
+	if err != nil {
+		return nil, err
+	}
+	if err = config.DB.Create(&change).Error; err != nil {
+		// Lost the race against another request for the same IBAN
+		if model.HasPendingChange(config.DB, iban.IbanID) {
+			return nil, fmt.Errorf("the IBAN already has a pending change")
+		}
+		return nil, err
+	}
+	log.Printf("IBAN %d of group %d: %s %d requested by user %d", iban.IbanID, iban.OwnerID, action, change.IbanChangeID, change.RequestedBy)
+	return &IbanChangeResponse{c: &change}, nil
+}
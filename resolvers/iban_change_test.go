@@ -0,0 +1,125 @@
+package resolvers
+
+import (
+	"context"
+	"strconv"
+	"testing"
+
+	graphql "github.com/graph-gophers/graphql-go"
+
+	"github.com/tapsilat/iban.im/model"
+)
+
+func TestIbanChangeApproval(t *testing.T) {
+	resolver, db, cleanup := setupTestResolverWithDB(t)
+	defer cleanup()
+
+	owner := createTestUser(t, db, "owner@example.com", "pass", "owner", "Olivia", "Owner")
+	admin := createTestUser(t, db, "admin@example.com", "pass", "admin", "Adam", "Admin")
+	member := createTestUser(t, db, "member@example.com", "pass", "member", "Mia", "Member")
+	ownerCtx := contextWithUserID(int(owner.UserID))
+	adminCtx := contextWithUserID(int(admin.UserID))
+	memberCtx := contextWithUserID(int(member.UserID))
+
+	created, _ := resolver.GroupNew(ownerCtx, GroupNewMutationArgs{Handle: "acme", Name: "Acme Ltd"})
+	groupID := created.Group.ID()
+	invited, _ := resolver.GroupMemberInvite(ownerCtx, GroupMemberInviteMutationArgs{GroupID: groupID, Handle: "admin", Role: strPtr("admin")})
+	resolver.GroupInvitationAccept(adminCtx, GroupInvitationAcceptMutationArgs{Id: invited.Member.ID()})
+	invited, _ = resolver.GroupMemberInvite(ownerCtx, GroupMemberInviteMutationArgs{GroupID: groupID, Handle: "member"})
+	resolver.GroupInvitationAccept(memberCtx, GroupInvitationAcceptMutationArgs{Id: invited.Member.ID()})
+
+	ibanNew, _ := resolver.IbanNew(ownerCtx, IbanNewMutationArgs{GroupID: &groupID, Text: strPtr("DE89370400440532013000"), Handle: "donations"})
+	if !ibanNew.Ok() {
+		t.Fatalf("IbanNew failed: %v", *ibanNew.Error())
+	}
+	ibanID := ibanNew.Iban.ID()
+	id, _ := strconv.Atoi(string(ibanID))
+
+	// An update is staged and the live IBAN kept
+	updated, _ := resolver.IbanUpdate(adminCtx, IbanUpdateMutationArgs{Id: ibanID, Text: "DE02120300000000202051", Handle: "donations", IsPrivate: true, Password: "secret"})
+	if !updated.Ok() || updated.Change == nil || updated.Change.Status() != model.ChangePending {
+		t.Fatalf("IbanUpdate must stage a change: Ok() = %v, Error() = %v", updated.Ok(), updated.Error())
+	}
+	if updated.Iban.Text() != "DE89370400440532013000" || updated.Change.Iban().Text() != "DE02120300000000202051" {
+		t.Errorf("Live IBAN = %s, staged = %s", updated.Iban.Text(), updated.Change.Iban().Text())
+	}
+	if updated.Change.Iban().Password() != "" {
+		t.Errorf("Change shows the password hash %s", updated.Change.Iban().Password())
+	}
+	again, _ := resolver.IbanUpdate(ownerCtx, IbanUpdateMutationArgs{Id: ibanID, Text: "DE75512108001245126199", Handle: "donations"})
+	if again.Ok() || *again.Error() != "the IBAN already has a pending change" {
+		t.Errorf("Second change: Ok() = %v, Error() = %v", again.Ok(), again.Error())
+	}
+	changeID := updated.Change.ID()
+
+	tests := []struct {
+		name string
+		ctx  context.Context
+		want string
+	}{
+		{name: "Requester", ctx: adminCtx, want: "a second owner or admin must approve the change"},
+		{name: "Plain member", ctx: memberCtx, want: "not authorized"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			resp, _ := resolver.IbanChangeApprove(tt.ctx, IbanChangeApproveMutationArgs{Id: changeID})
+			if resp.Ok() || *resp.Error() != tt.want {
+				t.Errorf("Ok() = %v, Error() = %v, want %s", resp.Ok(), resp.Error(), tt.want)
+			}
+		})
+	}
+
+	approved, _ := resolver.IbanChangeApprove(ownerCtx, IbanChangeApproveMutationArgs{Id: changeID})
+	if !approved.Ok() || approved.Change.Status() != model.ChangeApproved || *approved.Change.DecidedBy() != "owner" {
+		t.Fatalf("IbanChangeApprove failed: %v", approved.Error())
+	}
+	var live model.Iban
+	db.First(&live, id)
+	if live.Text != "DE02120300000000202051" || !live.ComparePassword("secret") {
+		t.Errorf("Approved update not applied, IBAN is %s", live.Text)
+	}
+	approved, _ = resolver.IbanChangeApprove(ownerCtx, IbanChangeApproveMutationArgs{Id: changeID})
+	if approved.Ok() || *approved.Error() != "change is already approved" {
+		t.Errorf("Approving twice: Ok() = %v, Error() = %v", approved.Ok(), approved.Error())
+	}
+
+	// A rejected deletion keeps the IBAN
+	deleted, _ := resolver.IbanDelete(ownerCtx, IbanDeleteMutationArgs{Id: ibanID})
+	if !deleted.Ok() || deleted.Change == nil || deleted.Change.Action() != model.ChangeDelete {
+		t.Fatalf("IbanDelete must stage a change: %v", deleted.Error())
+	}
+	rejected, _ := resolver.IbanChangeReject(adminCtx, IbanChangeRejectMutationArgs{Id: deleted.Change.ID(), Reason: strPtr("still in use")})
+	if !rejected.Ok() || rejected.Change.Status() != model.ChangeRejected || *rejected.Change.Reason() != "still in use" {
+		t.Fatalf("IbanChangeReject failed: %v", rejected.Error())
+	}
+	if err := db.First(&model.Iban{}, id).Error; err != nil {
+		t.Errorf("Rejected deletion removed the IBAN")
+	}
+
+	deleted, _ = resolver.IbanDelete(ownerCtx, IbanDeleteMutationArgs{Id: ibanID})
+	resolver.IbanChangeApprove(adminCtx, IbanChangeApproveMutationArgs{Id: deleted.Change.ID()})
+	if err := db.First(&model.Iban{}, id).Error; err == nil {
+		t.Errorf("Approved deletion kept the IBAN")
+	}
+
+	changes, _ := resolver.GetIbanChanges(memberCtx, IbanChangesQueryArgs{GroupID: groupID})
+	if !changes.Ok() || len(changes.Changes) != 3 || changes.Changes[0].Status() != model.ChangeApproved || changes.Changes[0].Iban().Handle() != "donations" {
+		t.Errorf("GetIbanChanges = %d changes, want the 3 decided ones newest first", len(changes.Changes))
+	}
+	changes, _ = resolver.GetIbanChanges(memberCtx, IbanChangesQueryArgs{GroupID: groupID, Status: strPtr("rejected")})
+	if len(changes.Changes) != 1 || changes.Changes[0].RequestedBy() != "owner" {
+		t.Errorf("GetIbanChanges of rejected = %d changes, want 1", len(changes.Changes))
+	}
+	changes, _ = resolver.GetIbanChanges(contextWithUserID(999), IbanChangesQueryArgs{GroupID: groupID})
+	if changes.Ok() {
+		t.Errorf("A stranger read the changes")
+	}
+
+	// Personal IBANs are changed right away
+	personal := createTestIban(t, db, owner.UserID, "GB82WEST12345698765432", "rent", "", false)
+	personalID := graphql.ID(strconv.Itoa(int(personal.IbanID)))
+	updated, _ = resolver.IbanUpdate(ownerCtx, IbanUpdateMutationArgs{Id: personalID, Text: "GB82WEST12345698765432", Handle: "flat"})
+	if !updated.Ok() || updated.Change != nil || updated.Iban.Handle() != "flat" {
+		t.Errorf("IbanUpdate of a personal IBAN: Ok() = %v, Error() = %v", updated.Ok(), updated.Error())
+	}
+}
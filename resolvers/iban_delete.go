@@ -7,6 +7,7 @@ import (
 	"github.com/graph-gophers/graphql-go"
 	"github.com/tapsilat/iban.im/config"
 	"github.com/tapsilat/iban.im/handler"
+	"github.com/tapsilat/iban.im/model"
 )
 
 func (r *Resolvers) IbanDelete(ctx context.Context, args IbanDeleteMutationArgs) (response *IbanDeleteResponse, err error) {
@@ -28,11 +29,16 @@ func (r *Resolvers) IbanDelete(ctx context.Context, args IbanDeleteMutationArgs)
 		return
 	}
 
-	if iban.OwnerID != uint(userIdStr.(int)) {
+	if !canEditIban(userIdStr, iban) {
 		err = fmt.Errorf("not authorized")
 		return
 	}
 
+	if iban.OwnerType == model.OwnerGroup {
+		response.Change, err = stageIbanChange(userIdStr, model.ChangeDelete, iban)
+		return
+	}
+
 	err = config.DB.Delete(&iban).Error
 
 	return
@@ -41,6 +47,8 @@ func (r *Resolvers) IbanDelete(ctx context.Context, args IbanDeleteMutationArgs)
 type IbanDeleteResponse struct {
 	Status bool
 	Msg    *string
+	// Change is the pending deletion of a group IBAN
+	Change *IbanChangeResponse
 }
 
 // Ok for IbanDeleteResponse
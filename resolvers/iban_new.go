@@ -5,9 +5,14 @@ import (
 	"fmt"
 	"strings"
 
+	graphql "github.com/graph-gophers/graphql-go"
+
 	"github.com/tapsilat/iban.im/config"
 	"github.com/tapsilat/iban.im/handler"
+	"github.com/tapsilat/iban.im/iso13616"
 	"github.com/tapsilat/iban.im/model"
+	"github.com/tapsilat/iban.im/paymethod"
+	"github.com/tapsilat/iban.im/payto"
 )
 
 // IbanNew mutation creates iban
@@ -21,15 +26,77 @@ func (r *Resolvers) IbanNew(ctx context.Context, args IbanNewMutationArgs) (*Iba
 	// userid,_:= strconv.Atoi(UserID.(string))
 	userid, _ := UserID.(int)
 	fmt.Printf("UserID: %+v, userid: %d\n", UserID, userid)
-	if r.HandleCheck(userid, args.Handle) {
+	// IBANs of a group are added by its owners and admins
+	ownerID, ownerType := uint(userid), model.OwnerUser
+	if args.GroupID != nil {
+		group := model.Group{}
+		if err := config.DB.Where("group_id = ?", *args.GroupID).First(&group).Error; err != nil {
+			msg := "group is not exist"
+			return &IbanNewResponse{Status: false, Msg: &msg, Iban: nil}, nil
+		}
+		if !model.CanManageGroup(config.DB, group.GroupID, uint(userid)) {
+			msg := "Not Authorized"
+			return &IbanNewResponse{Status: false, Msg: &msg, Iban: nil}, nil
+		}
+		ownerID, ownerType = group.GroupID, model.OwnerGroup
+	} else if r.HandleCheck(userid, args.Handle) {
 		msg := "Same Handle used : " + args.Handle
 		return &IbanNewResponse{Status: false, Msg: &msg, Iban: nil}, nil
 	}
 
-	// Basic validations (replacing removed qor/validations callbacks)
-	if strings.TrimSpace(args.Text) == "" {
-		msg := "you have to provide IBAN"
-		return &IbanNewResponse{Status: false, Msg: &msg, Iban: nil}, nil
+	method := paymethod.IBAN
+	if args.Method != nil && *args.Method != "" {
+		method = strings.ToLower(strings.TrimSpace(*args.Method))
+	}
+	var text, routingCode string
+	if method == paymethod.IBAN {
+		// The IBAN may also be given as payto://iban/ URI, e.g. from an invoice
+		if args.Text != nil && payto.IsURI(*args.Text) {
+			uri, err := payto.Parse(*args.Text)
+			if err != nil {
+				msg := err.Error()
+				return &IbanNewResponse{Status: false, Msg: &msg, Iban: nil}, nil
+			}
+			args.Text = &uri.IBAN
+			if args.BIC == nil && uri.BIC != "" {
+				args.BIC = &uri.BIC
+			}
+		}
+
+		var err error
+		text, err = args.iban()
+		if err != nil {
+			msg := err.Error()
+			return &IbanNewResponse{Status: false, Msg: &msg, Iban: nil}, nil
+		}
+
+		// Basic validations (replacing removed qor/validations callbacks)
+		if strings.TrimSpace(text) == "" {
+			msg := "you have to provide IBAN"
+			return &IbanNewResponse{Status: false, Msg: &msg, Iban: nil}, nil
+		}
+		if err := iso13616.Validate(text); err != nil {
+			msg := err.Error()
+			return &IbanNewResponse{Status: false, Msg: &msg, Iban: nil, Suggestions: suggestIbans(text)}, nil
+		}
+	} else {
+		var number string
+		if args.Text != nil {
+			number = *args.Text
+		}
+		if args.RoutingCode != nil {
+			routingCode = *args.RoutingCode
+		}
+		if strings.TrimSpace(number) == "" {
+			msg := "you have to provide the account"
+			return &IbanNewResponse{Status: false, Msg: &msg, Iban: nil}, nil
+		}
+		account, err := paymethod.Validate(method, paymethod.Account{Number: number, Routing: routingCode})
+		if err != nil {
+			msg := err.Error()
+			return &IbanNewResponse{Status: false, Msg: &msg, Iban: nil}, nil
+		}
+		text, routingCode = account.Number, account.Routing
 	}
 	if strings.TrimSpace(args.Handle) == "" {
 		msg := "you have to provide handle"
@@ -40,10 +107,25 @@ func (r *Resolvers) IbanNew(ctx context.Context, args IbanNewMutationArgs) (*Iba
 		return &IbanNewResponse{Status: false, Msg: &msg, Iban: nil}, nil
 	}
 
-	IbanNew := model.Iban{Text: args.Text, Password: args.Password, Handle: args.Handle, OwnerID: uint(userid), IsPrivate: args.IsPrivate}
+	IbanNew := model.Iban{Method: method, Text: text, RoutingCode: routingCode, Password: args.Password, Handle: args.Handle, OwnerID: ownerID, OwnerType: ownerType, IsPrivate: args.IsPrivate}
 	if args.Description != nil {
 		IbanNew.Description = *args.Description
 	}
+	if args.BIC != nil {
+		IbanNew.BIC = *args.BIC
+	}
+	if err := IbanNew.CheckBIC(); err != nil {
+		msg := err.Error()
+		return &IbanNewResponse{Status: false, Msg: &msg, Iban: nil}, nil
+	}
+	if err := ibanPresets(&IbanNew, args.PresetAmounts, args.Currency); err != nil {
+		msg := err.Error()
+		return &IbanNewResponse{Status: false, Msg: &msg, Iban: nil}, nil
+	}
+	if err := ibanAccount(&IbanNew, args.HolderName, args.AccountType); err != nil {
+		msg := err.Error()
+		return &IbanNewResponse{Status: false, Msg: &msg, Iban: nil}, nil
+	}
 	if args.IsPrivate {
 		IbanNew.HashPassword()
 	}
@@ -52,7 +134,7 @@ func (r *Resolvers) IbanNew(ctx context.Context, args IbanNewMutationArgs) (*Iba
 		return &IbanNewResponse{Status: false, Msg: &msg, Iban: nil}, err
 	}
 
-	return &IbanNewResponse{Status: true, Msg: nil, Iban: &IbanResponse{i: &IbanNew}}, nil
+	return &IbanNewResponse{Status: true, Msg: nil, Iban: &IbanResponse{i: &IbanNew}, Warnings: bicWarnings(&IbanNew)}, nil
 }
 
 // checks if this handle used for the user
@@ -70,18 +152,57 @@ func (r *Resolvers) HandleCheck(userid int, handle string) bool {
 }
 
 type IbanNewMutationArgs struct {
-	Text        string
+	// GroupID makes the IBAN one of the group's instead of the user's
+	GroupID *graphql.ID
+	// Method is the paymethod type, an IBAN if not given
+	Method      *string
+	Text        *string
+	RoutingCode *string
+	Country     *string
+	BankCode    *string
+	BranchCode  *string
+	Account     *string
+	BIC         *string
 	Description *string
-	Password    string
-	Handle      string
-	IsPrivate   bool
+	// PresetAmounts are suggested amounts such as "5" or "12.50"
+	PresetAmounts *[]string
+	Currency      *string
+	HolderName    *string
+	AccountType   *string
+	Password      string
+	Handle        string
+	IsPrivate     bool
+}
+
+// iban returns the given text, or composes it from the national account
+// details when no text is given
+func (args IbanNewMutationArgs) iban() (string, error) {
+	if args.Text != nil && strings.TrimSpace(*args.Text) != "" {
+		return *args.Text, nil
+	}
+	if args.Country == nil {
+		return "", nil
+	}
+	var bankCode, branchCode, account string
+	if args.BankCode != nil {
+		bankCode = *args.BankCode
+	}
+	if args.BranchCode != nil {
+		branchCode = *args.BranchCode
+	}
+	if args.Account != nil {
+		account = *args.Account
+	}
+	return iso13616.Compose(*args.Country, bankCode, branchCode, account)
 }
 
 // IbanNewResponse is the response type
 type IbanNewResponse struct {
-	Status bool
-	Msg    *string
-	Iban   *IbanResponse
+	Status      bool
+	Msg         *string
+	Iban        *IbanResponse
+	Suggestions []*IbanSuggestionResponse
+	Warnings    []string
 }
 
 // Ok for IbanNewResponse
@@ -1,11 +1,19 @@
 package resolvers
 
 import (
+	"fmt"
 	"strconv"
 
 	graphql "github.com/graph-gophers/graphql-go"
 
+	"github.com/tapsilat/iban.im/bankdir"
+	"github.com/tapsilat/iban.im/config"
+	"github.com/tapsilat/iban.im/handler"
+	"github.com/tapsilat/iban.im/iso13616"
+	"github.com/tapsilat/iban.im/iso9362"
 	"github.com/tapsilat/iban.im/model"
+	"github.com/tapsilat/iban.im/paymethod"
+	"github.com/tapsilat/iban.im/payto"
 )
 
 // IbanResponse is the user response type
@@ -25,11 +33,200 @@ func (r *IbanResponse) OwnerID() string {
 	return ownerId
 }
 
+// OwnerType for IbanResponse, User or Group
+func (r *IbanResponse) OwnerType() string {
+	if r.i.OwnerType == "" {
+		return model.OwnerUser
+	}
+	return r.i.OwnerType
+}
+
 // Text for IbanResponse
 func (r *IbanResponse) Text() string {
 	return r.i.Text
 }
 
+// Method for IbanResponse, the payment method type
+func (r *IbanResponse) Method() string {
+	if r.i.Method == "" {
+		return paymethod.IBAN
+	}
+	return r.i.Method
+}
+
+// RoutingCode for IbanResponse, the UK sort code or US routing number
+func (r *IbanResponse) RoutingCode() *string {
+	return optional(r.i.RoutingCode)
+}
+
+// ElectronicFormat for IbanResponse, other payment methods as stored
+func (r *IbanResponse) ElectronicFormat() string {
+	if !r.i.IsIBAN() {
+		return r.i.Text
+	}
+	return iso13616.Normalize(r.i.Text)
+}
+
+// PrintFormat for IbanResponse
+func (r *IbanResponse) PrintFormat() string {
+	if method, ok := r.i.PaymentMethod(); ok {
+		return method.Print(r.i.Account())
+	}
+	return r.i.Text
+}
+
+// URI for IbanResponse, the wallet or payment page of payment methods other
+// than IBANs, whose payto URI is Payto
+func (r *IbanResponse) URI() *string {
+	if r.i.IsIBAN() {
+		return nil
+	}
+	if method, ok := r.i.PaymentMethod(); ok {
+		return optional(method.URI(r.i.Account()))
+	}
+	return nil
+}
+
+// parts decomposes the IBAN, nil if it is not valid
+func (r *IbanResponse) parts() *iso13616.Parts {
+	if !r.i.IsIBAN() {
+		return nil
+	}
+	parts, err := iso13616.Decompose(r.i.Text)
+	if err != nil {
+		return nil
+	}
+	return &parts
+}
+
+// BBAN for IbanResponse
+func (r *IbanResponse) BBAN() *string {
+	if p := r.parts(); p != nil {
+		return optional(p.BBAN)
+	}
+	return nil
+}
+
+// BankCode for IbanResponse
+func (r *IbanResponse) BankCode() *string {
+	if p := r.parts(); p != nil {
+		return optional(p.BankCode)
+	}
+	return nil
+}
+
+// BranchCode for IbanResponse
+func (r *IbanResponse) BranchCode() *string {
+	if p := r.parts(); p != nil {
+		return optional(p.BranchCode)
+	}
+	return nil
+}
+
+// AccountNumber for IbanResponse
+func (r *IbanResponse) AccountNumber() *string {
+	if p := r.parts(); p != nil {
+		return optional(p.AccountNumber)
+	}
+	return nil
+}
+
+// NationalCheckDigits for IbanResponse
+func (r *IbanResponse) NationalCheckDigits() *string {
+	if p := r.parts(); p != nil {
+		return optional(p.NationalCheckDigits)
+	}
+	return nil
+}
+
+// BankName for IbanResponse
+func (r *IbanResponse) BankName() *string {
+	if !r.i.IsIBAN() {
+		return nil
+	}
+	if bank, ok := bankdir.LookupIBAN(r.i.Text); ok {
+		return optional(bank.Name)
+	}
+	return nil
+}
+
+// BIC for IbanResponse, the one given by the owner or else the directory's
+func (r *IbanResponse) BIC() *string {
+	if r.i.BIC != "" {
+		return &r.i.BIC
+	}
+	if !r.i.IsIBAN() {
+		return nil
+	}
+	if bank, ok := bankdir.LookupIBAN(r.i.Text); ok {
+		return optional(bank.BIC)
+	}
+	return nil
+}
+
+// Payto for IbanResponse, the RFC 8905 URI with the holder as receiver and
+// an optional amount in payto notation such as "EUR:12.50"
+func (r *IbanResponse) Payto(args paytoArgs) (*string, error) {
+	if !r.i.IsIBAN() || !iso13616.IsValid(r.i.Text) {
+		return nil, nil
+	}
+	uri := payto.URI{IBAN: r.i.Text}
+	if bic := r.BIC(); bic != nil {
+		uri.BIC = *bic
+	}
+	if owner, ok := r.owner(); ok {
+		uri.ReceiverName = r.i.Holder(owner)
+	}
+	if args.Amount != nil && *args.Amount != "" {
+		var err error
+		if uri.Currency, uri.Amount, err = payto.ParseAmount(*args.Amount); err != nil {
+			return nil, err
+		}
+	}
+	if args.Message != nil {
+		uri.Message = *args.Message
+	}
+	s := uri.String()
+	return &s, nil
+}
+
+// owner loads the user or group the IBAN belongs to
+func (r *IbanResponse) owner() (model.Owner, bool) {
+	if r.i.OwnerType == model.OwnerGroup {
+		var group model.Group
+		err := config.DB.First(&group, r.i.OwnerID).Error
+		return &group, err == nil
+	}
+	var user model.User
+	err := config.DB.First(&user, r.i.OwnerID).Error
+	return &user, err == nil
+}
+
+type paytoArgs struct {
+	Amount  *string
+	Message *string
+}
+
+// Currency for IbanResponse, the currency of the account
+func (r *IbanResponse) Currency() *string {
+	return optional(r.i.Currency)
+}
+
+// HolderName for IbanResponse, null when the account is in the owner's name
+func (r *IbanResponse) HolderName() *string {
+	return optional(r.i.HolderName)
+}
+
+// AccountType for IbanResponse
+func (r *IbanResponse) AccountType() *string {
+	return optional(r.i.AccountType)
+}
+
+// PresetAmounts for IbanResponse
+func (r *IbanResponse) PresetAmounts() []string {
+	return handler.PresetAmounts(*r.i)
+}
+
 // Description for IbanResponse
 func (r *IbanResponse) Description() *string {
 	return &r.i.Description
@@ -59,3 +256,55 @@ func (r *IbanResponse) CreatedAt() string {
 func (r *IbanResponse) UpdatedAt() string {
 	return r.i.UpdatedAt.String()
 }
+
+// ibanPresets applies the optional preset amounts and their currency of
+// ibanNew and ibanUpdate; an empty list removes the presets
+func ibanPresets(iban *model.Iban, amounts *[]string, currency *string) error {
+	if currency != nil {
+		iban.Currency = *currency
+	}
+	if amounts != nil {
+		cents := make([]int64, 0, len(*amounts))
+		for _, amount := range *amounts {
+			value, err := handler.ParseAmount(amount)
+			if err != nil || value == 0 {
+				return fmt.Errorf("preset amount %q must be a positive number with at most two decimals", amount)
+			}
+			cents = append(cents, value)
+		}
+		iban.SetPresetAmounts(cents)
+	}
+	return iban.CheckPresets()
+}
+
+// ibanAccount applies the optional holder name and account type of ibanNew
+// and ibanUpdate; an empty string removes them
+func ibanAccount(iban *model.Iban, holderName, accountType *string) error {
+	if holderName != nil {
+		iban.HolderName = *holderName
+	}
+	if accountType != nil {
+		iban.AccountType = *accountType
+	}
+	return iban.CheckAccount()
+}
+
+// optional maps an empty string to a null GraphQL value
+func optional(s string) *string {
+	if s == "" {
+		return nil
+	}
+	return &s
+}
+
+// bicWarnings flags a BIC that the bank directory disagrees with
+func bicWarnings(iban *model.Iban) []string {
+	warnings := []string{}
+	if iban.BIC == "" {
+		return warnings
+	}
+	if bank, ok := bankdir.LookupIBAN(iban.Text); ok && bank.BIC != "" && !iso9362.SameInstitution(iban.BIC, bank.BIC) {
+		warnings = append(warnings, fmt.Sprintf("BIC %s differs from %s registered for %s", iban.BIC, bank.BIC, bank.Name))
+	}
+	return warnings
+}
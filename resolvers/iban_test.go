@@ -2,9 +2,13 @@ package resolvers
 
 import (
 	"context"
+	"strconv"
 	"strings"
 	"testing"
 
+	graphql "github.com/graph-gophers/graphql-go"
+
+	"github.com/tapsilat/iban.im/model"
 	"gorm.io/gorm"
 )
 
@@ -20,7 +24,7 @@ func TestIbanNew(t *testing.T) {
 		{
 			name: "Successful IBAN creation",
 			args: IbanNewMutationArgs{
-				Text:      "TR320010009999901234567890",
+				Text:      strPtr("TR320010009999901234567890"),
 				Handle:    "myiban",
 				Password:  "",
 				IsPrivate: false,
@@ -35,7 +39,7 @@ func TestIbanNew(t *testing.T) {
 		{
 			name: "IBAN with description",
 			args: IbanNewMutationArgs{
-				Text:        "TR320010009999901234567890",
+				Text:        strPtr("TR320010009999901234567890"),
 				Handle:      "myiban",
 				Description: strPtr("My Bank Account"),
 				Password:    "",
@@ -51,7 +55,7 @@ func TestIbanNew(t *testing.T) {
 		{
 			name: "Private IBAN with password",
 			args: IbanNewMutationArgs{
-				Text:      "TR320010009999901234567890",
+				Text:      strPtr("TR320010009999901234567890"),
 				Handle:    "privateiban",
 				Password:  "secret123",
 				IsPrivate: true,
@@ -66,7 +70,7 @@ func TestIbanNew(t *testing.T) {
 		{
 			name: "Duplicate handle",
 			args: IbanNewMutationArgs{
-				Text:      "TR420010009999901234567891",
+				Text:      strPtr("TR420010009999901234567891"),
 				Handle:    "existinghandle",
 				Password:  "",
 				IsPrivate: false,
@@ -83,7 +87,7 @@ func TestIbanNew(t *testing.T) {
 		{
 			name: "Not authenticated",
 			args: IbanNewMutationArgs{
-				Text:      "TR320010009999901234567890",
+				Text:      strPtr("TR320010009999901234567890"),
 				Handle:    "myiban",
 				Password:  "",
 				IsPrivate: false,
@@ -95,7 +99,7 @@ func TestIbanNew(t *testing.T) {
 		{
 			name: "Empty IBAN text",
 			args: IbanNewMutationArgs{
-				Text:      "",
+				Text:      strPtr(""),
 				Handle:    "myiban",
 				Password:  "",
 				IsPrivate: false,
@@ -111,7 +115,7 @@ func TestIbanNew(t *testing.T) {
 		{
 			name: "Empty handle",
 			args: IbanNewMutationArgs{
-				Text:      "TR320010009999901234567890",
+				Text:      strPtr("TR320010009999901234567890"),
 				Handle:    "",
 				Password:  "",
 				IsPrivate: false,
@@ -127,7 +131,7 @@ func TestIbanNew(t *testing.T) {
 		{
 			name: "Private IBAN without password",
 			args: IbanNewMutationArgs{
-				Text:      "TR320010009999901234567890",
+				Text:      strPtr("TR320010009999901234567890"),
 				Handle:    "privateiban",
 				Password:  "",
 				IsPrivate: true,
@@ -140,10 +144,105 @@ func TestIbanNew(t *testing.T) {
 			expectSuccess: false,
 			expectError:   "you have to provide password",
 		},
+		{
+			name: "Invalid IBAN length",
+			args: IbanNewMutationArgs{
+				Text:      strPtr("TR3200100099999012345678"),
+				Handle:    "myiban",
+				Password:  "",
+				IsPrivate: false,
+			},
+			setupDB: func(db *gorm.DB) *uint {
+				user := createTestUser(t, db, "test@example.com", "pass", "testuser", "Test", "User")
+				return &user.UserID
+			},
+			withContext:   true,
+			expectSuccess: false,
+			expectError:   "invalid IBAN length for TR: expected 26 characters, got 24",
+		},
+		{
+			name: "Invalid IBAN checksum",
+			args: IbanNewMutationArgs{
+				Text:      strPtr("TR420010009999901234567891"),
+				Handle:    "myiban",
+				Password:  "",
+				IsPrivate: false,
+			},
+			setupDB: func(db *gorm.DB) *uint {
+				user := createTestUser(t, db, "test@example.com", "pass", "testuser", "Test", "User")
+				return &user.UserID
+			},
+			withContext:   true,
+			expectSuccess: false,
+			expectError:   "IBAN checksum does not match",
+		},
+		{
+			name: "IBAN with BIC",
+			args: IbanNewMutationArgs{
+				Text:      strPtr("TR320010009999901234567890"),
+				BIC:       strPtr("adabtris"),
+				Handle:    "myiban",
+				Password:  "",
+				IsPrivate: false,
+			},
+			setupDB: func(db *gorm.DB) *uint {
+				user := createTestUser(t, db, "test@example.com", "pass", "testuser", "Test", "User")
+				return &user.UserID
+			},
+			withContext:   true,
+			expectSuccess: true,
+		},
+		{
+			name: "BIC from another country",
+			args: IbanNewMutationArgs{
+				Text:      strPtr("TR320010009999901234567890"),
+				BIC:       strPtr("COBADEFFXXX"),
+				Handle:    "myiban",
+				Password:  "",
+				IsPrivate: false,
+			},
+			setupDB: func(db *gorm.DB) *uint {
+				user := createTestUser(t, db, "test@example.com", "pass", "testuser", "Test", "User")
+				return &user.UserID
+			},
+			withContext:   true,
+			expectSuccess: false,
+			expectError:   "BIC country does not match: BIC is from DE, IBAN from TR",
+		},
+		{
+			name: "IBAN composed from account details",
+			args: IbanNewMutationArgs{
+				Country:  strPtr("TR"),
+				BankCode: strPtr("00061"),
+				Account:  strPtr("0519786457841326"),
+				Handle:   "composed",
+			},
+			setupDB: func(db *gorm.DB) *uint {
+				user := createTestUser(t, db, "test@example.com", "pass", "testuser", "Test", "User")
+				return &user.UserID
+			},
+			withContext:   true,
+			expectSuccess: true,
+		},
+		{
+			name: "Invalid account details",
+			args: IbanNewMutationArgs{
+				Country: strPtr("DE"),
+				Account: strPtr("532013000"),
+				Handle:  "composed",
+			},
+			setupDB: func(db *gorm.DB) *uint {
+				user := createTestUser(t, db, "test@example.com", "pass", "testuser", "Test", "User")
+				return &user.UserID
+			},
+			withContext:   true,
+			expectSuccess: false,
+			expectError:   "bank code is required for DE",
+		},
 		{
 			name: "Handle case insensitivity",
 			args: IbanNewMutationArgs{
-				Text:      "TR420010009999901234567891",
+				Text:      strPtr("TR050010009999901234567891"),
 				Handle:    "MyIBAN",
 				Password:  "",
 				IsPrivate: false,
@@ -198,8 +297,8 @@ func TestIbanNew(t *testing.T) {
 			}
 
 			if tt.expectSuccess && resp.Iban != nil {
-				if resp.Iban.Text() != tt.args.Text {
-					t.Errorf("IBAN text = %s, want %s", resp.Iban.Text(), tt.args.Text)
+				if tt.args.Text != nil && resp.Iban.Text() != *tt.args.Text {
+					t.Errorf("IBAN text = %s, want %s", resp.Iban.Text(), *tt.args.Text)
 				}
 				// Handle should be lowercased by the resolver
 				expectedHandle := strings.ToLower(tt.args.Handle)
@@ -323,7 +422,462 @@ func TestGetMyIbans(t *testing.T) {
 	}
 }
 
+func TestComposeIban(t *testing.T) {
+	resolver := &Resolvers{}
+
+	resp, err := resolver.ComposeIban(context.Background(), ComposeIbanQueryArgs{
+		Country:    "GB",
+		BankCode:   "WEST",
+		BranchCode: strPtr("123456"),
+		Account:    "98765432",
+	})
+	if err != nil {
+		t.Fatalf("ComposeIban returned unexpected error: %v", err)
+	}
+	if !resp.Ok() || *resp.Iban != "GB82WEST12345698765432" {
+		t.Errorf("ComposeIban() = %v, %v", resp.Ok(), resp.Error())
+	}
+	if *resp.PrintFormat() != "GB82 WEST 1234 5698 7654 32" {
+		t.Errorf("PrintFormat() = %s", *resp.PrintFormat())
+	}
+
+	resp, _ = resolver.ComposeIban(context.Background(), ComposeIbanQueryArgs{Country: "XX", BankCode: "1", Account: "1"})
+	if resp.Ok() || resp.Error() == nil || *resp.Error() != "unknown IBAN country code: XX" {
+		t.Errorf("Expected unknown country error, got %v", resp.Error())
+	}
+}
+
+func TestSuggestIbanCorrections(t *testing.T) {
+	resolver := &Resolvers{}
+
+	resp, err := resolver.SuggestIbanCorrections(context.Background(), SuggestIbanCorrectionsQueryArgs{Text: "TR05 0010 0099 9990 1234 5678 19"})
+	if err != nil {
+		t.Fatalf("SuggestIbanCorrections returned unexpected error: %v", err)
+	}
+	if resp.Ok() || resp.Error() == nil || *resp.Error() != "IBAN checksum does not match" {
+		t.Errorf("Expected checksum error, got %v", resp.Error())
+	}
+	var found *IbanSuggestionResponse
+	for _, s := range resp.Suggestions {
+		if s.Iban() == "TR050010009999901234567891" {
+			found = s
+		}
+	}
+	if found == nil {
+		t.Fatalf("Expected TR050010009999901234567891 among %d suggestions", len(resp.Suggestions))
+	}
+	if got := found.PrintFormat(); got != "TR05 0010 0099 9990 1234 5678 91" {
+		t.Errorf("PrintFormat() = %s", got)
+	}
+	if got := found.Reason(); got != "swapped characters at positions 25 and 26" {
+		t.Errorf("Reason() = %s", got)
+	}
+
+	resp, _ = resolver.SuggestIbanCorrections(context.Background(), SuggestIbanCorrectionsQueryArgs{Text: "TR050010009999901234567891"})
+	if !resp.Ok() || len(resp.Suggestions) != 0 {
+		t.Errorf("Expected no suggestions for a valid IBAN, got %v, %d", resp.Ok(), len(resp.Suggestions))
+	}
+}
+
+func TestIbanNewSuggestions(t *testing.T) {
+	resolver, db, cleanup := setupTestResolverWithDB(t)
+	defer cleanup()
+	user := createTestUser(t, db, "test@example.com", "pass", "testuser", "Test", "User")
+
+	resp, err := resolver.IbanNew(contextWithUserID(int(user.UserID)), IbanNewMutationArgs{
+		Text:   strPtr("TR05OO1OOO9999901234567891"),
+		Handle: "myiban",
+	})
+	if err != nil {
+		t.Fatalf("IbanNew returned unexpected error: %v", err)
+	}
+	if resp.Ok() {
+		t.Fatal("Expected IbanNew to reject the IBAN")
+	}
+	if len(resp.Suggestions) == 0 || resp.Suggestions[0].Iban() != "TR050010009999901234567891" {
+		t.Errorf("Expected TR050010009999901234567891 as first suggestion, got %d suggestions", len(resp.Suggestions))
+	}
+}
+
+func TestIbanNewBICWarnings(t *testing.T) {
+	tests := []struct {
+		name     string
+		bic      string
+		warnings []string
+	}{
+		{name: "Matches directory", bic: "COBADEFF", warnings: []string{}},
+		{name: "Branch of the same bank", bic: "COBADEFF370", warnings: []string{}},
+		{name: "Differs from directory", bic: "DEUTDEFFXXX", warnings: []string{"BIC DEUTDEFFXXX differs from COBADEFFXXX registered for Commerzbank"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			resolver, db, cleanup := setupTestResolverWithDB(t)
+			defer cleanup()
+			user := createTestUser(t, db, "test@example.com", "pass", "testuser", "Test", "User")
+
+			resp, err := resolver.IbanNew(contextWithUserID(int(user.UserID)), IbanNewMutationArgs{
+				Text:   strPtr("DE89370400440532013000"),
+				BIC:    strPtr(tt.bic),
+				Handle: "myiban",
+			})
+			if err != nil || !resp.Ok() {
+				t.Fatalf("IbanNew failed: %v, %v", err, resp.Error())
+			}
+			if got := resp.Iban.BIC(); got == nil || *got != tt.bic {
+				t.Errorf("BIC() = %v, want %s", got, tt.bic)
+			}
+			if strings.Join(resp.Warnings, "|") != strings.Join(tt.warnings, "|") {
+				t.Errorf("Warnings = %v, want %v", resp.Warnings, tt.warnings)
+			}
+		})
+	}
+}
+
 // Helper function
 func strPtr(s string) *string {
 	return &s
 }
+
+func TestIbanResponseParts(t *testing.T) {
+	resp := &IbanResponse{i: &model.Iban{Text: "GB82WEST12345698765432"}}
+
+	if got := resp.BankCode(); got == nil || *got != "WEST" {
+		t.Errorf("BankCode() = %v, want WEST", got)
+	}
+	if got := resp.BranchCode(); got == nil || *got != "123456" {
+		t.Errorf("BranchCode() = %v, want 123456", got)
+	}
+	if got := resp.AccountNumber(); got == nil || *got != "98765432" {
+		t.Errorf("AccountNumber() = %v, want 98765432", got)
+	}
+	if got := resp.NationalCheckDigits(); got != nil {
+		t.Errorf("NationalCheckDigits() = %v, want nil", *got)
+	}
+
+	invalid := &IbanResponse{i: &model.Iban{Text: "TR420010009999901234567891"}}
+	if got := invalid.BankCode(); got != nil {
+		t.Errorf("BankCode() for invalid IBAN = %v, want nil", *got)
+	}
+}
+
+func TestIbanNewPayto(t *testing.T) {
+	tests := []struct {
+		name        string
+		text        string
+		expectIban  string
+		expectBIC   string
+		expectError string
+	}{
+		{name: "IBAN only", text: "payto://iban/DE89370400440532013000?receiver-name=Test%20User", expectIban: "DE89370400440532013000", expectBIC: "COBADEFFXXX"},
+		{name: "With BIC", text: "payto://iban/COBADEFF370/DE89370400440532013000", expectIban: "DE89370400440532013000", expectBIC: "COBADEFF370"},
+		{name: "Other target type", text: "payto://bitcoin/1A1zP1eP5QGefi2DMPTfTL5SLmv7DivfNa", expectError: `unsupported payto target type, only iban is supported: "bitcoin"`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			resolver, db, cleanup := setupTestResolverWithDB(t)
+			defer cleanup()
+			user := createTestUser(t, db, "test@example.com", "pass", "testuser", "Test", "User")
+
+			resp, err := resolver.IbanNew(contextWithUserID(int(user.UserID)), IbanNewMutationArgs{
+				Text:   strPtr(tt.text),
+				Handle: "myiban",
+			})
+			if err != nil {
+				t.Fatalf("IbanNew returned unexpected error: %v", err)
+			}
+			if tt.expectError != "" {
+				if resp.Ok() || resp.Error() == nil || *resp.Error() != tt.expectError {
+					t.Errorf("Error() = %v, want %s", resp.Error(), tt.expectError)
+				}
+				return
+			}
+			if !resp.Ok() {
+				t.Fatalf("IbanNew failed: %v", *resp.Error())
+			}
+			if resp.Iban.Text() != tt.expectIban {
+				t.Errorf("Text() = %s, want %s", resp.Iban.Text(), tt.expectIban)
+			}
+			if got := resp.Iban.BIC(); got == nil || *got != tt.expectBIC {
+				t.Errorf("BIC() = %v, want %s", got, tt.expectBIC)
+			}
+		})
+	}
+}
+
+func TestIbanResponsePayto(t *testing.T) {
+	_, db, cleanup := setupTestResolverWithDB(t)
+	defer cleanup()
+	user := createTestUser(t, db, "test@example.com", "pass", "testuser", "Test", "User")
+	resp := &IbanResponse{i: &model.Iban{Text: "DE89370400440532013000", OwnerID: user.UserID}}
+
+	got, err := resp.Payto(paytoArgs{Amount: strPtr("EUR:12.5"), Message: strPtr("Dinner")})
+	want := "payto://iban/COBADEFFXXX/DE89370400440532013000?amount=EUR:12.50&receiver-name=Test%20User&message=Dinner"
+	if err != nil || got == nil || *got != want {
+		t.Errorf("Payto() = %v, %v, want %s", got, err, want)
+	}
+
+	if _, err := resp.Payto(paytoArgs{Amount: strPtr("12.50")}); err == nil {
+		t.Error("Payto() should reject an amount without currency")
+	}
+}
+
+func TestIbanNewPresetAmounts(t *testing.T) {
+	tests := []struct {
+		name          string
+		amounts       *[]string
+		currency      *string
+		expectAmounts string
+		expectError   string
+	}{
+		{name: "Presets", amounts: &[]string{"10", "2,50", "5.00", "5"}, currency: strPtr("eur"), expectAmounts: "2.50,5.00,10.00"},
+		{name: "Currency only", currency: strPtr("TRY"), expectAmounts: ""},
+		{name: "No presets", expectAmounts: ""},
+		{name: "Without currency", amounts: &[]string{"5"}, expectError: "you have to provide the currency of the preset amounts"},
+		{name: "Invalid amount", amounts: &[]string{"five"}, currency: strPtr("EUR"), expectError: `preset amount "five" must be a positive number with at most two decimals`},
+		{name: "Zero", amounts: &[]string{"0"}, currency: strPtr("EUR"), expectError: `preset amount "0" must be a positive number with at most two decimals`},
+		{name: "Too many", amounts: &[]string{"1", "2", "3", "4", "5", "6", "7"}, currency: strPtr("EUR"), expectError: "at most 6 preset amounts are allowed"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			resolver, db, cleanup := setupTestResolverWithDB(t)
+			defer cleanup()
+			user := createTestUser(t, db, "test@example.com", "pass", "testuser", "Test", "User")
+
+			resp, err := resolver.IbanNew(contextWithUserID(int(user.UserID)), IbanNewMutationArgs{
+				Text:          strPtr("DE89370400440532013000"),
+				Handle:        "tips",
+				PresetAmounts: tt.amounts,
+				Currency:      tt.currency,
+			})
+			if err != nil {
+				t.Fatalf("IbanNew returned unexpected error: %v", err)
+			}
+			if tt.expectError != "" {
+				if resp.Ok() || resp.Error() == nil || *resp.Error() != tt.expectError {
+					t.Errorf("Error() = %v, want %s", resp.Error(), tt.expectError)
+				}
+				return
+			}
+			if !resp.Ok() {
+				t.Fatalf("IbanNew failed: %v", *resp.Error())
+			}
+			if got := strings.Join(resp.Iban.PresetAmounts(), ","); got != tt.expectAmounts {
+				t.Errorf("PresetAmounts() = %s, want %s", got, tt.expectAmounts)
+			}
+			if tt.currency != nil && *resp.Iban.Currency() != strings.ToUpper(*tt.currency) {
+				t.Errorf("Currency() = %s, want %s", *resp.Iban.Currency(), strings.ToUpper(*tt.currency))
+			}
+		})
+	}
+}
+
+func TestIbanNewAccount(t *testing.T) {
+	tests := []struct {
+		name        string
+		holderName  *string
+		accountType *string
+		currency    *string
+		expectError string
+	}{
+		{name: "Company account", holderName: strPtr(" Acme GmbH "), accountType: strPtr("Business"), currency: strPtr("usd")},
+		{name: "Owner's account"},
+		{name: "Unknown account type", accountType: strPtr("brokerage"), expectError: "account type must be one of checking, savings, business"},
+		{name: "Unknown currency", currency: strPtr("ABC"), expectError: "unknown ISO 4217 currency ABC"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			resolver, db, cleanup := setupTestResolverWithDB(t)
+			defer cleanup()
+			user := createTestUser(t, db, "test@example.com", "pass", "testuser", "Test", "User")
+
+			resp, err := resolver.IbanNew(contextWithUserID(int(user.UserID)), IbanNewMutationArgs{
+				Text:        strPtr("DE89370400440532013000"),
+				Handle:      "company",
+				HolderName:  tt.holderName,
+				AccountType: tt.accountType,
+				Currency:    tt.currency,
+			})
+			if err != nil {
+				t.Fatalf("IbanNew returned unexpected error: %v", err)
+			}
+			if tt.expectError != "" {
+				if resp.Ok() || resp.Error() == nil || *resp.Error() != tt.expectError {
+					t.Errorf("Error() = %v, want %s", resp.Error(), tt.expectError)
+				}
+				return
+			}
+			if !resp.Ok() {
+				t.Fatalf("IbanNew failed: %v", *resp.Error())
+			}
+			if tt.holderName == nil {
+				if resp.Iban.HolderName() != nil || resp.Iban.AccountType() != nil {
+					t.Errorf("HolderName() = %v, AccountType() = %v, want null", resp.Iban.HolderName(), resp.Iban.AccountType())
+				}
+				return
+			}
+			if *resp.Iban.HolderName() != "Acme GmbH" || *resp.Iban.AccountType() != "business" || *resp.Iban.Currency() != "USD" {
+				t.Errorf("Got %s, %s, %s, want Acme GmbH, business, USD", *resp.Iban.HolderName(), *resp.Iban.AccountType(), *resp.Iban.Currency())
+			}
+			payto, err := resp.Iban.Payto(paytoArgs{})
+			if err != nil || !strings.Contains(*payto, "receiver-name=Acme%20GmbH") {
+				t.Errorf("Payto() = %v, %v, want Acme GmbH as receiver", payto, err)
+			}
+		})
+	}
+}
+
+func TestIbanNewPaymentMethods(t *testing.T) {
+	tests := []struct {
+		name        string
+		args        IbanNewMutationArgs
+		expectText  string
+		expectPrint string
+		expectURI   string
+		expectError string
+	}{
+		{
+			name:        "UK account",
+			args:        IbanNewMutationArgs{Method: strPtr("sortcode"), Text: strPtr("3141 5926"), RoutingCode: strPtr("20-00-00")},
+			expectText:  "31415926",
+			expectPrint: "20-00-00 31415926",
+		},
+		{
+			name:        "US account",
+			args:        IbanNewMutationArgs{Method: strPtr("ABA"), Text: strPtr("000123456789"), RoutingCode: strPtr("021000021")},
+			expectText:  "000123456789",
+			expectPrint: "021000021 000123456789",
+		},
+		{
+			name:        "Ethereum address",
+			args:        IbanNewMutationArgs{Method: strPtr("crypto"), Text: strPtr("0xfb6916095ca1df60bb79ce92ce3ea74c37c5d359")},
+			expectText:  "0xfB6916095ca1df60bB79Ce92cE3Ea74c37c5d359",
+			expectPrint: "0xfB6916095ca1df60bB79Ce92cE3Ea74c37c5d359",
+			expectURI:   "ethereum:0xfB6916095ca1df60bB79Ce92cE3Ea74c37c5d359",
+		},
+		{
+			name:        "Payment link",
+			args:        IbanNewMutationArgs{Method: strPtr("link"), Text: strPtr("paypal.me/testuser")},
+			expectText:  "https://paypal.me/testuser",
+			expectPrint: "https://paypal.me/testuser",
+			expectURI:   "https://paypal.me/testuser",
+		},
+		{
+			name:        "ABA checksum",
+			args:        IbanNewMutationArgs{Method: strPtr("aba"), Text: strPtr("000123456789"), RoutingCode: strPtr("021000022")},
+			expectError: "account checksum does not match: routing number 021000022",
+		},
+		{
+			name:        "Missing account",
+			args:        IbanNewMutationArgs{Method: strPtr("crypto")},
+			expectError: "you have to provide the account",
+		},
+		{
+			name:        "Unknown method",
+			args:        IbanNewMutationArgs{Method: strPtr("cheque"), Text: strPtr("42")},
+			expectError: `unknown payment method "cheque", expected one of iban, sortcode, aba, crypto, link`,
+		},
+		{
+			name:        "BIC for a link",
+			args:        IbanNewMutationArgs{Method: strPtr("link"), Text: strPtr("paypal.me/testuser"), BIC: strPtr("COBADEFFXXX")},
+			expectError: "a BIC can only be given for an IBAN",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			resolver, db, cleanup := setupTestResolverWithDB(t)
+			defer cleanup()
+			user := createTestUser(t, db, "test@example.com", "pass", "testuser", "Test", "User")
+			ctx := contextWithUserID(int(user.UserID))
+
+			tt.args.Handle = "pay"
+			resp, err := resolver.IbanNew(ctx, tt.args)
+			if err != nil {
+				t.Fatalf("IbanNew returned unexpected error: %v", err)
+			}
+			if tt.expectError != "" {
+				if resp.Ok() || resp.Error() == nil || *resp.Error() != tt.expectError {
+					t.Errorf("Error() = %v, want %s", resp.Error(), tt.expectError)
+				}
+				return
+			}
+			if !resp.Ok() {
+				t.Fatalf("IbanNew failed: %v", *resp.Error())
+			}
+			if resp.Iban.Text() != tt.expectText || resp.Iban.PrintFormat() != tt.expectPrint {
+				t.Errorf("Text() = %s, PrintFormat() = %s, want %s, %s", resp.Iban.Text(), resp.Iban.PrintFormat(), tt.expectText, tt.expectPrint)
+			}
+			if uri := resp.Iban.URI(); (uri == nil && tt.expectURI != "") || (uri != nil && *uri != tt.expectURI) {
+				t.Errorf("URI() = %v, want %s", uri, tt.expectURI)
+			}
+			if payto, _ := resp.Iban.Payto(paytoArgs{}); payto != nil {
+				t.Errorf("Payto() = %s, want null", *payto)
+			}
+
+			request, err := resolver.PaymentRequestNew(ctx, PaymentRequestNewMutationArgs{IbanID: resp.Iban.ID()})
+			if err != nil {
+				t.Fatalf("PaymentRequestNew returned unexpected error: %v", err)
+			}
+			if request.Ok() || *request.Error() != "payment requests can only be made for IBANs" {
+				t.Errorf("PaymentRequestNew Error() = %v, want it to refuse the payment method", request.Error())
+			}
+		})
+	}
+}
+
+func TestIbanUpdatePaymentMethods(t *testing.T) {
+	tests := []struct {
+		name        string
+		args        IbanUpdateMutationArgs
+		expectText  string
+		expectError string
+	}{
+		{
+			name:       "To a UK account",
+			args:       IbanUpdateMutationArgs{Method: strPtr("sortcode"), Text: "3141 5926", RoutingCode: strPtr("20 00 00")},
+			expectText: "31415926",
+		},
+		{
+			name:       "To a payment link",
+			args:       IbanUpdateMutationArgs{Method: strPtr("link"), Text: "paypal.me/testuser"},
+			expectText: "https://paypal.me/testuser",
+		},
+		{
+			name:        "BIC for a link",
+			args:        IbanUpdateMutationArgs{Method: strPtr("link"), Text: "paypal.me/testuser", BIC: strPtr("COBADEFFXXX")},
+			expectError: "a BIC can only be given for an IBAN",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			resolver, db, cleanup := setupTestResolverWithDB(t)
+			defer cleanup()
+			user := createTestUser(t, db, "test@example.com", "pass", "testuser", "Test", "User")
+			iban := createTestIban(t, db, user.UserID, "DE89370400440532013000", "pay", "", false)
+			db.Model(iban).Update("bic", "COBADEFFXXX")
+
+			tt.args.Id = graphql.ID(strconv.Itoa(int(iban.IbanID)))
+			tt.args.Handle = "pay"
+			resp, err := resolver.IbanUpdate(contextWithUserID(int(user.UserID)), tt.args)
+			if tt.expectError != "" {
+				if resp.Ok() || resp.Error() == nil || *resp.Error() != tt.expectError {
+					t.Errorf("Error() = %v, want %s", resp.Error(), tt.expectError)
+				}
+				return
+			}
+			if err != nil || !resp.Ok() {
+				t.Fatalf("IbanUpdate failed: %v", resp.Error())
+			}
+			var saved model.Iban
+			db.First(&saved, iban.IbanID)
+			if saved.Text != tt.expectText || saved.BIC != "" {
+				t.Errorf("Saved %s with BIC %q, want %s without BIC", saved.Text, saved.BIC, tt.expectText)
+			}
+		})
+	}
+}
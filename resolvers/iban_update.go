@@ -10,7 +10,9 @@ import (
 	"fmt"
 
 	"github.com/tapsilat/iban.im/handler"
+	"github.com/tapsilat/iban.im/iso13616"
 	"github.com/tapsilat/iban.im/model"
+	"github.com/tapsilat/iban.im/paymethod"
 )
 
 func (r *Resolvers) GetIbanById(id graphql.ID) model.Iban {
@@ -19,6 +21,18 @@ func (r *Resolvers) GetIbanById(id graphql.ID) model.Iban {
 	return iban
 }
 
+// canEditIban reports whether the user may change or delete the IBAN: their
+// own, or one of a group they are an owner or admin of
+func canEditIban(userID interface{}, iban model.Iban) bool {
+	if userID == nil {
+		return false
+	}
+	if iban.OwnerType == model.OwnerGroup {
+		return model.CanManageGroup(config.DB, iban.OwnerID, uint(userID.(int)))
+	}
+	return iban.OwnerID == uint(userID.(int))
+}
+
 // IbanUpdate mutation change profile
 func (r *Resolvers) IbanUpdate(ctx context.Context, args IbanUpdateMutationArgs) (response *IbanUpdateResponse, err error) {
 	response = &IbanUpdateResponse{}
@@ -34,7 +48,8 @@ func (r *Resolvers) IbanUpdate(ctx context.Context, args IbanUpdateMutationArgs)
 		}
 	}()
 
-	if userID := ctx.Value(handler.ContextKey("UserID")); userID == nil {
+	userID := ctx.Value(handler.ContextKey("UserID"))
+	if userID == nil {
 		err = fmt.Errorf("not authorized")
 		return
 	}
@@ -43,11 +58,38 @@ func (r *Resolvers) IbanUpdate(ctx context.Context, args IbanUpdateMutationArgs)
 		err = fmt.Errorf("iban is not exist")
 		return
 	}
+	if !canEditIban(userID, iban) {
+		err = fmt.Errorf("not authorized")
+		return
+	}
+
+	if args.Method != nil && *args.Method != "" {
+		iban.Method = strings.ToLower(strings.TrimSpace(*args.Method))
+	}
+	if args.RoutingCode != nil {
+		iban.RoutingCode = *args.RoutingCode
+	}
 
 	// Basic validations (replacing removed qor/validations callbacks)
-	if strings.TrimSpace(args.Text) == "" {
-		err = fmt.Errorf("you have to provide IBAN")
-		return
+	if iban.IsIBAN() {
+		if strings.TrimSpace(args.Text) == "" {
+			err = fmt.Errorf("you have to provide IBAN")
+			return
+		}
+		if err = iso13616.Validate(args.Text); err != nil {
+			response.Suggestions = suggestIbans(args.Text)
+			return
+		}
+	} else {
+		if strings.TrimSpace(args.Text) == "" {
+			err = fmt.Errorf("you have to provide the account")
+			return
+		}
+		var account paymethod.Account
+		if account, err = paymethod.Validate(iban.Method, paymethod.Account{Number: args.Text, Routing: iban.RoutingCode}); err != nil {
+			return
+		}
+		args.Text, iban.RoutingCode = account.Number, account.Routing
 	}
 	if strings.TrimSpace(args.Handle) == "" {
 		err = fmt.Errorf("you have to provide handle")
@@ -59,6 +101,21 @@ func (r *Resolvers) IbanUpdate(ctx context.Context, args IbanUpdateMutationArgs)
 	if args.Description != nil {
 		iban.Description = *args.Description
 	}
+	if args.BIC != nil {
+		iban.BIC = *args.BIC
+	} else if !iban.IsIBAN() {
+		// Left over from before the switch to another payment method
+		iban.BIC = ""
+	}
+	if err = iban.CheckBIC(); err != nil {
+		return
+	}
+	if err = ibanPresets(&iban, args.PresetAmounts, args.Currency); err != nil {
+		return
+	}
+	if err = ibanAccount(&iban, args.HolderName, args.AccountType); err != nil {
+		return
+	}
 
 	if args.IsPrivate && args.Password != "" {
 		iban.IsPrivate = true
@@ -69,24 +126,47 @@ func (r *Resolvers) IbanUpdate(ctx context.Context, args IbanUpdateMutationArgs)
 		iban.Password = ""
 	}
 
-	err = config.DB.Save(&iban).Error
+	// Changes to group IBANs wait for a second owner or admin; the response
+	// keeps the live IBAN until then
+	if iban.OwnerType == model.OwnerGroup {
+		if response.Change, err = stageIbanChange(userID, model.ChangeUpdate, iban); err == nil {
+			response.Warnings = bicWarnings(&iban)
+			iban = r.GetIbanById(args.Id)
+		}
+		return
+	}
+
+	if err = config.DB.Save(&iban).Error; err == nil {
+		response.Warnings = bicWarnings(&iban)
+	}
 	return
 }
 
 type IbanUpdateMutationArgs struct {
-	Text        string
-	Description *string
-	Password    string
-	Handle      string
-	Id          graphql.ID
-	IsPrivate   bool `json:"isPrivate"`
+	Method        *string
+	Text          string
+	RoutingCode   *string
+	BIC           *string
+	Description   *string
+	PresetAmounts *[]string
+	Currency      *string
+	HolderName    *string
+	AccountType   *string
+	Password      string
+	Handle        string
+	Id            graphql.ID
+	IsPrivate     bool `json:"isPrivate"`
 }
 
 // IbanUpdateResponse is the response type
 type IbanUpdateResponse struct {
-	Status bool
-	Msg    *string
-	Iban   *IbanResponse
+	Status      bool
+	Msg         *string
+	Iban        *IbanResponse
+	Suggestions []*IbanSuggestionResponse
+	Warnings    []string
+	// Change is the pending change of a group IBAN
+	Change *IbanChangeResponse
 }
 
 // Ok for IbanUpdateResponse
@@ -110,6 +190,6 @@ func (r *Resolvers) FindIbanByHandle(ibans []model.Iban, handle string) model.Ib
 func (r *Resolvers) FindIbanByOwner(userID int) []model.Iban {
 	var ibans []model.Iban
 	// Get all matched records
-	config.DB.Where("owner_id = ? AND is_private = false", userID).Find(&ibans)
+	config.DB.Where("owner_id = ? AND owner_type = ? AND is_private = false", userID, model.OwnerUser).Find(&ibans)
 	return ibans
 }
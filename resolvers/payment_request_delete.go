@@ -0,0 +1,49 @@
+package resolvers
+
+import (
+	"context"
+
+	graphql "github.com/graph-gophers/graphql-go"
+
+	"github.com/tapsilat/iban.im/config"
+	"github.com/tapsilat/iban.im/handler"
+)
+
+// PaymentRequestDelete mutation removes a payment request
+func (r *Resolvers) PaymentRequestDelete(ctx context.Context, args PaymentRequestDeleteMutationArgs) (response *PaymentRequestDeleteResponse, err error) {
+	response = &PaymentRequestDeleteResponse{}
+
+	defer func() {
+		if !reportError(&err, &response.Msg) {
+			response.Status = true
+		}
+	}()
+
+	request, err := findOwnPaymentRequest(ctx.Value(handler.ContextKey("UserID")), args.Id)
+	if err != nil {
+		return
+	}
+	err = config.DB.Delete(&request).Error
+	return
+}
+
+// args for delete mutation
+type PaymentRequestDeleteMutationArgs struct {
+	Id graphql.ID
+}
+
+// PaymentRequestDeleteResponse is the response type
+type PaymentRequestDeleteResponse struct {
+	Status bool
+	Msg    *string
+}
+
+// Ok for PaymentRequestDeleteResponse
+func (r *PaymentRequestDeleteResponse) Ok() bool {
+	return r.Status
+}
+
+// Error for PaymentRequestDeleteResponse
+func (r *PaymentRequestDeleteResponse) Error() *string {
+	return r.Msg
+}
@@ -0,0 +1,86 @@
+package resolvers
+
+import (
+	"context"
+	"fmt"
+
+	graphql "github.com/graph-gophers/graphql-go"
+
+	"github.com/tapsilat/iban.im/config"
+	"github.com/tapsilat/iban.im/handler"
+	"github.com/tapsilat/iban.im/model"
+)
+
+// PaymentRequestNew mutation creates a payment request for one of the
+// user's IBANs
+func (r *Resolvers) PaymentRequestNew(ctx context.Context, args PaymentRequestNewMutationArgs) (response *PaymentRequestNewResponse, err error) {
+	response = &PaymentRequestNewResponse{}
+	request := model.PaymentRequest{}
+
+	defer func() {
+		if reportError(&err, &response.Msg) {
+			return
+		}
+		response.Status = true
+		response.PaymentRequest = &PaymentRequestResponse{p: &request}
+	}()
+
+	userID := ctx.Value(handler.ContextKey("UserID"))
+	if userID == nil {
+		err = fmt.Errorf("not authorized")
+		return
+	}
+	iban := r.GetIbanById(args.IbanID)
+	if iban.IbanID == 0 {
+		err = fmt.Errorf("iban is not exist")
+		return
+	}
+	// Requests are listed per user, a group has no single owner for them
+	if iban.OwnerType == model.OwnerGroup {
+		err = fmt.Errorf("payment requests can only be made for your own IBANs")
+		return
+	}
+	if iban.OwnerID != uint(userID.(int)) {
+		err = fmt.Errorf("not authorized")
+		return
+	}
+	if !iban.IsIBAN() {
+		err = fmt.Errorf("payment requests can only be made for IBANs")
+		return
+	}
+
+	request.OwnerID = iban.OwnerID
+	request.IbanID = iban.IbanID
+	if err = paymentRequestFields(&request, args.Amount, args.Currency, args.Reference, args.Description, args.Payer, args.ExpiresAt); err != nil {
+		return
+	}
+	err = config.DB.Create(&request).Error
+	return
+}
+
+type PaymentRequestNewMutationArgs struct {
+	IbanID      graphql.ID
+	Amount      *string
+	Currency    *string
+	Reference   *string
+	Description *string
+	Payer       *string
+	ExpiresAt   *string
+}
+
+// PaymentRequestNewResponse is the response type
+type PaymentRequestNewResponse struct {
+	Status         bool
+	Msg            *string
+	PaymentRequest *PaymentRequestResponse
+}
+
+// Ok for PaymentRequestNewResponse
+func (r *PaymentRequestNewResponse) Ok() bool {
+	return r.Status
+}
+
+// Error for PaymentRequestNewResponse
+func (r *PaymentRequestNewResponse) Error() *string {
+	return r.Msg
+}
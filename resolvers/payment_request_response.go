@@ -0,0 +1,187 @@
+package resolvers
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	graphql "github.com/graph-gophers/graphql-go"
+
+	"github.com/tapsilat/iban.im/config"
+	"github.com/tapsilat/iban.im/handler"
+	"github.com/tapsilat/iban.im/iso11649"
+	"github.com/tapsilat/iban.im/model"
+)
+
+// PaymentRequestResponse is the payment request response type
+type PaymentRequestResponse struct {
+	p *model.PaymentRequest
+}
+
+// ID for PaymentRequestResponse
+func (r *PaymentRequestResponse) ID() graphql.ID {
+	return graphql.ID(strconv.Itoa(int(r.p.PaymentRequestID)))
+}
+
+// PublicID for PaymentRequestResponse
+func (r *PaymentRequestResponse) PublicID() string {
+	return r.p.PublicID
+}
+
+// IbanID for PaymentRequestResponse
+func (r *PaymentRequestResponse) IbanID() graphql.ID {
+	return graphql.ID(strconv.Itoa(int(r.p.IbanID)))
+}
+
+// Amount for PaymentRequestResponse, null when the payer chooses it
+func (r *PaymentRequestResponse) Amount() *string {
+	if r.p.Amount == 0 {
+		return nil
+	}
+	amount := handler.FormatAmount(r.p.Amount)
+	return &amount
+}
+
+// Currency for PaymentRequestResponse
+func (r *PaymentRequestResponse) Currency() *string {
+	return optional(r.p.Currency)
+}
+
+// Reference for PaymentRequestResponse
+func (r *PaymentRequestResponse) Reference() *string {
+	return optional(r.p.Reference)
+}
+
+// PrintReference for PaymentRequestResponse
+func (r *PaymentRequestResponse) PrintReference() *string {
+	return optional(iso11649.PrintFormat(r.p.Reference))
+}
+
+// Description for PaymentRequestResponse
+func (r *PaymentRequestResponse) Description() *string {
+	return optional(r.p.Description)
+}
+
+// Payer for PaymentRequestResponse
+func (r *PaymentRequestResponse) Payer() *string {
+	return optional(r.p.Payer)
+}
+
+// ExpiresAt for PaymentRequestResponse
+func (r *PaymentRequestResponse) ExpiresAt() *string {
+	if r.p.ExpiresAt == nil {
+		return nil
+	}
+	expiresAt := r.p.ExpiresAt.UTC().Format(time.RFC3339)
+	return &expiresAt
+}
+
+// Status for PaymentRequestResponse
+func (r *PaymentRequestResponse) Status() string {
+	return r.p.Status
+}
+
+// Expired for PaymentRequestResponse
+func (r *PaymentRequestResponse) Expired() bool {
+	return r.p.Expired(time.Now())
+}
+
+// URL for PaymentRequestResponse, the path of the public page
+func (r *PaymentRequestResponse) URL() string {
+	var iban model.Iban
+	var user model.User
+	config.DB.First(&iban, r.p.IbanID)
+	config.DB.First(&user, iban.OwnerID)
+	return fmt.Sprintf("/%s/%s/r/%s", user.Handle, iban.Handle, r.p.PublicID)
+}
+
+// CreatedAt for PaymentRequestResponse
+func (r *PaymentRequestResponse) CreatedAt() string {
+	return r.p.CreatedAt.String()
+}
+
+// UpdatedAt for PaymentRequestResponse
+func (r *PaymentRequestResponse) UpdatedAt() string {
+	return r.p.UpdatedAt.String()
+}
+
+// findOwnPaymentRequest loads a payment request of the user in ctx
+func findOwnPaymentRequest(userID interface{}, id graphql.ID) (model.PaymentRequest, error) {
+	var request model.PaymentRequest
+	if userID == nil {
+		return request, fmt.Errorf("not authorized")
+	}
+	if err := config.DB.Where("payment_request_id = ?", id).First(&request).Error; err != nil {
+		return request, fmt.Errorf("payment request is not exist")
+	}
+	if request.OwnerID != uint(userID.(int)) {
+		return request, fmt.Errorf("not authorized")
+	}
+	return request, nil
+}
+
+// creditorReference takes an RF creditor reference as is and turns anything
+// else, such as an invoice number, into one
+func creditorReference(s string) (string, error) {
+	if strings.HasPrefix(iso11649.Normalize(s), "RF") {
+		if err := iso11649.Validate(s); err != nil {
+			return "", err
+		}
+		return iso11649.Normalize(s), nil
+	}
+	return iso11649.Generate(s)
+}
+
+// parseExpiry reads an RFC 3339 time or a date, which expires at the end of
+// that day (UTC)
+func parseExpiry(s string) (*time.Time, error) {
+	expiresAt, err := time.Parse(time.RFC3339, s)
+	if err != nil {
+		day, dayErr := time.Parse(time.DateOnly, s)
+		if dayErr != nil {
+			return nil, fmt.Errorf("expiry must be a date (2006-01-02) or an RFC 3339 time")
+		}
+		expiresAt = day.AddDate(0, 0, 1)
+	}
+	if !expiresAt.After(time.Now()) {
+		return nil, fmt.Errorf("expiry must be in the future")
+	}
+	return &expiresAt, nil
+}
+
+// paymentRequestFields applies the optional fields shared by
+// paymentRequestNew and paymentRequestUpdate; empty strings clear them
+func paymentRequestFields(request *model.PaymentRequest, amount, currency, reference, description, payer, expiresAt *string) (err error) {
+	if amount != nil {
+		if request.Amount, err = handler.ParseAmount(*amount); err != nil {
+			return
+		}
+	}
+	if currency != nil {
+		request.Currency = *currency
+	}
+	if reference != nil {
+		request.Reference = ""
+		if strings.TrimSpace(*reference) != "" {
+			if request.Reference, err = creditorReference(*reference); err != nil {
+				return
+			}
+		}
+	}
+	if description != nil {
+		request.Description = *description
+	}
+	if payer != nil {
+		request.Payer = strings.TrimSpace(*payer)
+	}
+	if expiresAt != nil {
+		request.ExpiresAt = nil
+		if *expiresAt != "" {
+			if request.ExpiresAt, err = parseExpiry(*expiresAt); err != nil {
+				return
+			}
+		}
+	}
+	return request.Check()
+}
@@ -0,0 +1,248 @@
+package resolvers
+
+import (
+	"context"
+	"strconv"
+	"testing"
+	"time"
+
+	graphql "github.com/graph-gophers/graphql-go"
+
+	"github.com/tapsilat/iban.im/model"
+)
+
+func TestPaymentRequestNew(t *testing.T) {
+	tomorrow := time.Now().AddDate(0, 0, 1).Format(time.DateOnly)
+	tests := []struct {
+		name          string
+		args          PaymentRequestNewMutationArgs
+		otherOwner    bool
+		withContext   bool
+		expectSuccess bool
+		expectError   string
+	}{
+		{
+			name:          "Amount and invoice number",
+			args:          PaymentRequestNewMutationArgs{Amount: strPtr("12.50"), Currency: strPtr("eur"), Reference: strPtr("INV20240001"), ExpiresAt: strPtr(tomorrow)},
+			withContext:   true,
+			expectSuccess: true,
+		},
+		{
+			name:          "Open amount",
+			args:          PaymentRequestNewMutationArgs{Description: strPtr("Dinner")},
+			withContext:   true,
+			expectSuccess: true,
+		},
+		{
+			name:        "Without context",
+			args:        PaymentRequestNewMutationArgs{},
+			expectError: "not authorized",
+		},
+		{
+			name:        "Someone else's IBAN",
+			args:        PaymentRequestNewMutationArgs{},
+			otherOwner:  true,
+			withContext: true,
+			expectError: "not authorized",
+		},
+		{
+			name:        "Amount without currency",
+			args:        PaymentRequestNewMutationArgs{Amount: strPtr("12.50")},
+			withContext: true,
+			expectError: "you have to provide the currency of the amount",
+		},
+		{
+			name:        "Invalid currency",
+			args:        PaymentRequestNewMutationArgs{Amount: strPtr("12.50"), Currency: strPtr("EURO")},
+			withContext: true,
+			expectError: "currency must be a three letter ISO 4217 code",
+		},
+		{
+			name:        "Invalid reference",
+			args:        PaymentRequestNewMutationArgs{Reference: strPtr("RF19539007547034")},
+			withContext: true,
+			expectError: "creditor reference checksum does not match",
+		},
+		{
+			name:        "Expiry in the past",
+			args:        PaymentRequestNewMutationArgs{ExpiresAt: strPtr("2020-01-01")},
+			withContext: true,
+			expectError: "expiry must be in the future",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			resolver, db, cleanup := setupTestResolverWithDB(t)
+			defer cleanup()
+
+			user := createTestUser(t, db, "test@example.com", "pass", "testuser", "Test", "User")
+			owner := user
+			if tt.otherOwner {
+				owner = createTestUser(t, db, "other@example.com", "pass", "other", "Other", "User")
+			}
+			iban := createTestIban(t, db, owner.UserID, "DE89370400440532013000", "rent", "", false)
+			tt.args.IbanID = graphql.ID(strconv.Itoa(int(iban.IbanID)))
+
+			ctx := context.Background()
+			if tt.withContext {
+				ctx = contextWithUserID(int(user.UserID))
+			}
+			resp, err := resolver.PaymentRequestNew(ctx, tt.args)
+			if err != nil {
+				t.Fatalf("PaymentRequestNew returned unexpected error: %v", err)
+			}
+			if resp.Ok() != tt.expectSuccess {
+				t.Errorf("Ok() = %v, want %v (error %v)", resp.Ok(), tt.expectSuccess, resp.Error())
+			}
+			if tt.expectError != "" && (resp.Error() == nil || *resp.Error() != tt.expectError) {
+				t.Errorf("Error() = %v, want %s", resp.Error(), tt.expectError)
+			}
+			if tt.expectSuccess {
+				request := resp.PaymentRequest
+				if request.Status() != model.PaymentRequestOpen || request.Expired() {
+					t.Errorf("Status() = %s, Expired() = %v, want open", request.Status(), request.Expired())
+				}
+				if want := "/testuser/rent/r/" + request.PublicID(); request.URL() != want {
+					t.Errorf("URL() = %s, want %s", request.URL(), want)
+				}
+			}
+		})
+	}
+}
+
+func TestPaymentRequestNewFields(t *testing.T) {
+	resolver, db, cleanup := setupTestResolverWithDB(t)
+	defer cleanup()
+
+	user := createTestUser(t, db, "test@example.com", "pass", "testuser", "Test", "User")
+	iban := createTestIban(t, db, user.UserID, "DE89370400440532013000", "rent", "", false)
+	resp, _ := resolver.PaymentRequestNew(contextWithUserID(int(user.UserID)), PaymentRequestNewMutationArgs{
+		IbanID:    graphql.ID(strconv.Itoa(int(iban.IbanID))),
+		Amount:    strPtr("12.5"),
+		Currency:  strPtr("eur"),
+		Reference: strPtr("INV20240001"),
+		Payer:     strPtr(" Erika Musterfrau "),
+		ExpiresAt: strPtr("2099-12-31"),
+	})
+	if !resp.Ok() {
+		t.Fatalf("PaymentRequestNew failed: %v", *resp.Error())
+	}
+
+	request := resp.PaymentRequest
+	if *request.Amount() != "12.50" || *request.Currency() != "EUR" {
+		t.Errorf("Amount() = %s %s, want 12.50 EUR", *request.Amount(), *request.Currency())
+	}
+	if *request.Reference() != "RF17INV20240001" || *request.PrintReference() != "RF17 INV2 0240 001" {
+		t.Errorf("Reference() = %s, PrintReference() = %s", *request.Reference(), *request.PrintReference())
+	}
+	if *request.Payer() != "Erika Musterfrau" {
+		t.Errorf("Payer() = %q, want Erika Musterfrau", *request.Payer())
+	}
+	if *request.ExpiresAt() != "2100-01-01T00:00:00Z" {
+		t.Errorf("ExpiresAt() = %s, want the end of 2099-12-31", *request.ExpiresAt())
+	}
+	if len(request.PublicID()) != 16 {
+		t.Errorf("PublicID() = %s, want 16 hex digits", request.PublicID())
+	}
+}
+
+func TestPaymentRequestUpdateAndDelete(t *testing.T) {
+	resolver, db, cleanup := setupTestResolverWithDB(t)
+	defer cleanup()
+
+	user := createTestUser(t, db, "test@example.com", "pass", "testuser", "Test", "User")
+	other := createTestUser(t, db, "other@example.com", "pass", "other", "Other", "User")
+	iban := createTestIban(t, db, user.UserID, "DE89370400440532013000", "rent", "", false)
+	ctx := contextWithUserID(int(user.UserID))
+	created, _ := resolver.PaymentRequestNew(ctx, PaymentRequestNewMutationArgs{
+		IbanID:      graphql.ID(strconv.Itoa(int(iban.IbanID))),
+		Amount:      strPtr("40"),
+		Currency:    strPtr("EUR"),
+		Reference:   strPtr("INV1"),
+		Description: strPtr("Rent"),
+	})
+	id := created.PaymentRequest.ID()
+
+	resp, _ := resolver.PaymentRequestUpdate(contextWithUserID(int(other.UserID)), PaymentRequestUpdateMutationArgs{Id: id, Status: strPtr("paid")})
+	if resp.Ok() || *resp.Error() != "not authorized" {
+		t.Errorf("Update by someone else: Ok() = %v, Error() = %v", resp.Ok(), resp.Error())
+	}
+	resp, _ = resolver.PaymentRequestUpdate(ctx, PaymentRequestUpdateMutationArgs{Id: id, Status: strPtr("done")})
+	if resp.Ok() || *resp.Error() != "status must be one of open, paid or cancelled" {
+		t.Errorf("Update to unknown status: Ok() = %v, Error() = %v", resp.Ok(), resp.Error())
+	}
+
+	resp, _ = resolver.PaymentRequestUpdate(ctx, PaymentRequestUpdateMutationArgs{Id: id, Status: strPtr("paid"), Reference: strPtr("")})
+	if !resp.Ok() {
+		t.Fatalf("PaymentRequestUpdate failed: %v", *resp.Error())
+	}
+	if resp.PaymentRequest.Status() != model.PaymentRequestPaid || resp.PaymentRequest.Reference() != nil {
+		t.Errorf("Status() = %s, Reference() = %v, want paid without reference", resp.PaymentRequest.Status(), resp.PaymentRequest.Reference())
+	}
+	if *resp.PaymentRequest.Description() != "Rent" || *resp.PaymentRequest.Amount() != "40.00" {
+		t.Errorf("Fields not given must be kept, got %s %s", *resp.PaymentRequest.Description(), *resp.PaymentRequest.Amount())
+	}
+
+	deleted, _ := resolver.PaymentRequestDelete(contextWithUserID(int(other.UserID)), PaymentRequestDeleteMutationArgs{Id: id})
+	if deleted.Ok() {
+		t.Errorf("Delete by someone else succeeded")
+	}
+	deleted, _ = resolver.PaymentRequestDelete(ctx, PaymentRequestDeleteMutationArgs{Id: id})
+	if !deleted.Ok() {
+		t.Fatalf("PaymentRequestDelete failed: %v", *deleted.Error())
+	}
+	got, _ := resolver.GetPaymentRequest(ctx, GetPaymentRequestQueryArgs{Id: id})
+	if got.Ok() || *got.Error() != "payment request is not exist" {
+		t.Errorf("GetPaymentRequest after delete: Ok() = %v, Error() = %v", got.Ok(), got.Error())
+	}
+}
+
+func TestGetMyPaymentRequests(t *testing.T) {
+	resolver, db, cleanup := setupTestResolverWithDB(t)
+	defer cleanup()
+
+	user := createTestUser(t, db, "test@example.com", "pass", "testuser", "Test", "User")
+	other := createTestUser(t, db, "other@example.com", "pass", "other", "Other", "User")
+	rent := createTestIban(t, db, user.UserID, "DE89370400440532013000", "rent", "", false)
+	savings := createTestIban(t, db, user.UserID, "TR320010009999901234567890", "savings", "", false)
+	foreign := createTestIban(t, db, other.UserID, "DE89370400440532013000", "other", "", false)
+	for _, request := range []model.PaymentRequest{
+		{OwnerID: user.UserID, IbanID: rent.IbanID},
+		{OwnerID: user.UserID, IbanID: rent.IbanID, Status: model.PaymentRequestPaid},
+		{OwnerID: user.UserID, IbanID: savings.IbanID},
+		{OwnerID: other.UserID, IbanID: foreign.IbanID},
+	} {
+		if err := db.Create(&request).Error; err != nil {
+			t.Fatalf("Failed to create payment request: %v", err)
+		}
+	}
+
+	rentID := graphql.ID(strconv.Itoa(int(rent.IbanID)))
+	tests := []struct {
+		name   string
+		args   GetMyPaymentRequestsQueryArgs
+		expect int
+	}{
+		{name: "All", args: GetMyPaymentRequestsQueryArgs{}, expect: 3},
+		{name: "One IBAN", args: GetMyPaymentRequestsQueryArgs{IbanID: &rentID}, expect: 2},
+		{name: "Open on one IBAN", args: GetMyPaymentRequestsQueryArgs{IbanID: &rentID, Status: strPtr("open")}, expect: 1},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			resp, err := resolver.GetMyPaymentRequests(contextWithUserID(int(user.UserID)), tt.args)
+			if err != nil || !resp.Ok() {
+				t.Fatalf("GetMyPaymentRequests failed: %v %v", err, resp.Error())
+			}
+			if len(resp.PaymentRequests) != tt.expect {
+				t.Errorf("got %d payment requests, want %d", len(resp.PaymentRequests), tt.expect)
+			}
+		})
+	}
+
+	resp, _ := resolver.GetMyPaymentRequests(context.Background(), GetMyPaymentRequestsQueryArgs{})
+	if resp.Ok() || resp.PaymentRequests == nil {
+		t.Errorf("GetMyPaymentRequests without context: Ok() = %v", resp.Ok())
+	}
+}
@@ -0,0 +1,66 @@
+package resolvers
+
+import (
+	"context"
+
+	graphql "github.com/graph-gophers/graphql-go"
+
+	"github.com/tapsilat/iban.im/config"
+	"github.com/tapsilat/iban.im/handler"
+	"github.com/tapsilat/iban.im/model"
+)
+
+// PaymentRequestUpdate mutation changes a payment request, including its
+// status
+func (r *Resolvers) PaymentRequestUpdate(ctx context.Context, args PaymentRequestUpdateMutationArgs) (response *PaymentRequestUpdateResponse, err error) {
+	response = &PaymentRequestUpdateResponse{}
+	var request model.PaymentRequest
+
+	defer func() {
+		if reportError(&err, &response.Msg) {
+			return
+		}
+		response.Status = true
+		response.PaymentRequest = &PaymentRequestResponse{p: &request}
+	}()
+
+	if request, err = findOwnPaymentRequest(ctx.Value(handler.ContextKey("UserID")), args.Id); err != nil {
+		return
+	}
+	if args.Status != nil {
+		request.Status = *args.Status
+	}
+	if err = paymentRequestFields(&request, args.Amount, args.Currency, args.Reference, args.Description, args.Payer, args.ExpiresAt); err != nil {
+		return
+	}
+	err = config.DB.Save(&request).Error
+	return
+}
+
+type PaymentRequestUpdateMutationArgs struct {
+	Id          graphql.ID
+	Amount      *string
+	Currency    *string
+	Reference   *string
+	Description *string
+	Payer       *string
+	ExpiresAt   *string
+	Status      *string
+}
+
+// PaymentRequestUpdateResponse is the response type
+type PaymentRequestUpdateResponse struct {
+	Status         bool
+	Msg            *string
+	PaymentRequest *PaymentRequestResponse
+}
+
+// Ok for PaymentRequestUpdateResponse
+func (r *PaymentRequestUpdateResponse) Ok() bool {
+	return r.Status
+}
+
+// Error for PaymentRequestUpdateResponse
+func (r *PaymentRequestUpdateResponse) Error() *string {
+	return r.Msg
+}
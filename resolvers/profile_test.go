@@ -2,6 +2,7 @@ package resolvers
 
 import (
 	"context"
+	"slices"
 	"strings"
 	"testing"
 
@@ -101,6 +102,35 @@ func TestChangeProfile(t *testing.T) {
 			withContext:   true,
 			expectSuccess: true,
 		},
+		{
+			name: "Update address",
+			args: changeProfileMutationArgs{
+				Street:         strPtr("Rue du Lac"),
+				BuildingNumber: strPtr("1268"),
+				PostalCode:     strPtr("2501"),
+				Town:           strPtr("Biel"),
+				Country:        strPtr("ch"),
+			},
+			setupDB: func(db *gorm.DB) *uint {
+				user := createTestUser(t, db, "test@example.com", "pass", "testuser", "Test", "User")
+				return &user.UserID
+			},
+			withContext:   true,
+			expectSuccess: true,
+		},
+		{
+			name: "Invalid country",
+			args: changeProfileMutationArgs{
+				Country: strPtr("Switzerland"),
+			},
+			setupDB: func(db *gorm.DB) *uint {
+				user := createTestUser(t, db, "test@example.com", "pass", "testuser", "Test", "User")
+				return &user.UserID
+			},
+			withContext:   true,
+			expectSuccess: false,
+			expectError:   "Country must be a two letter ISO 3166 code",
+		},
 	}
 
 	for _, tt := range tests {
@@ -154,6 +184,9 @@ func TestChangeProfile(t *testing.T) {
 						t.Errorf("User handle = %s, want %s", resp.User.Handle(), expectedHandle)
 					}
 				}
+				if tt.args.Country != nil && *resp.User.Country() != strings.ToUpper(*tt.args.Country) {
+					t.Errorf("User country = %s, want %s", *resp.User.Country(), strings.ToUpper(*tt.args.Country))
+				}
 			}
 		})
 	}
@@ -283,3 +316,55 @@ func TestDeleteProfile(t *testing.T) {
 		})
 	}
 }
+
+func TestGetProfileCurrency(t *testing.T) {
+	tests := []struct {
+		name          string
+		currency      *string
+		expectHandles string
+		expectError   string
+	}{
+		{name: "All accounts", expectHandles: "euro,lira,plain"},
+		{name: "Empty filter", currency: strPtr(""), expectHandles: "euro,lira,plain"},
+		{name: "Euro", currency: strPtr("eur"), expectHandles: "euro"},
+		{name: "No account in currency", currency: strPtr("USD"), expectError: "no iban in USD"},
+		{name: "Unknown currency", currency: strPtr("ABC"), expectError: "unknown ISO 4217 currency ABC"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			resolver, db, cleanup := setupTestResolverWithDB(t)
+			defer cleanup()
+			user := createTestUser(t, db, "test@example.com", "pass", "testuser", "Test", "User")
+			for handle, currency := range map[string]string{"euro": "EUR", "lira": "TRY", "plain": ""} {
+				iban := createTestIban(t, db, user.UserID, "DE89370400440532013000", handle, "", false)
+				if err := db.Model(iban).Update("currency", currency).Error; err != nil {
+					t.Fatalf("Failed to set currency: %v", err)
+				}
+			}
+			createTestIban(t, db, user.UserID, "DE89370400440532013000", "hidden", "secret", true)
+
+			resp, err := resolver.GetProfile(context.Background(), ProfileQueryArgs{Username: "testuser", Currency: tt.currency})
+			if tt.expectError != "" {
+				if err == nil || resp.Ok() || resp.Error() == nil || *resp.Error() != tt.expectError {
+					t.Errorf("Error() = %v, want %s", resp.Error(), tt.expectError)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("GetProfile returned unexpected error: %v", err)
+			}
+			if !resp.Ok() {
+				t.Fatalf("GetProfile failed: %v", *resp.Error())
+			}
+			handles := []string{}
+			for _, iban := range *resp.Iban {
+				handles = append(handles, iban.Handle())
+			}
+			slices.Sort(handles)
+			if got := strings.Join(handles, ","); got != tt.expectHandles {
+				t.Errorf("Handles = %s, want %s", got, tt.expectHandles)
+			}
+		})
+	}
+}
@@ -0,0 +1,14 @@
+package resolvers
+
+// reportError moves err into msg, so that the resolver answers with its
+// response type instead of a GraphQL error, and reports whether there was
+// one. It is meant for the deferred function of resolvers with named results.
+func reportError(err *error, msg **string) bool {
+	if *err == nil {
+		return false
+	}
+	s := (*err).Error()
+	*msg = &s
+	*err = nil
+	return true
+}
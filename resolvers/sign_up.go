@@ -11,6 +11,11 @@ func (r *Resolvers) SignUp(args signUpMutationArgs) (*SignUpResponse, error) {
 
 	newUser := model.User{Email: args.Email, Password: args.Password, FirstName: args.FirstName, LastName: args.LastName, Handle: args.Handle}
 
+	if model.ReservedHandle(args.Handle) {
+		msg := "Handle is reserved"
+		return &SignUpResponse{Status: false, Msg: &msg, User: nil}, nil
+	}
+
 	var existing model.User
 	err := config.DB.Where("email = ? or handle = ?", args.Email, args.Handle).First(&existing).Error
 	if err == nil {
@@ -0,0 +1,98 @@
+package resolvers
+
+import (
+	"context"
+	"fmt"
+
+	graphql "github.com/graph-gophers/graphql-go"
+	"gorm.io/gorm"
+
+	"github.com/tapsilat/iban.im/config"
+	"github.com/tapsilat/iban.im/handler"
+	"github.com/tapsilat/iban.im/model"
+)
+
+// StatementEntryResolve mutation settles an entry of the review list: with
+// a payment request it marks that request as paid by the entry, without one
+// it dismisses the entry
+func (r *Resolvers) StatementEntryResolve(ctx context.Context, args StatementEntryResolveMutationArgs) (response *StatementEntryResolveResponse, err error) {
+	response = &StatementEntryResolveResponse{}
+	var entry model.StatementEntry
+
+	defer func() {
+		if reportError(&err, &response.Msg) {
+			return
+		}
+		response.Status = true
+		response.Entry = &StatementEntryResponse{e: &entry}
+	}()
+
+	userID := ctx.Value(handler.ContextKey("UserID"))
+	if userID == nil {
+		err = fmt.Errorf("not authorized")
+		return
+	}
+	if err = config.DB.Where("statement_entry_id = ?", args.Id).First(&entry).Error; err != nil {
+		err = fmt.Errorf("statement entry is not exist")
+		return
+	}
+	if entry.OwnerID != uint(userID.(int)) {
+		err = fmt.Errorf("not authorized")
+		return
+	}
+	if entry.Status == model.StatementEntryMatched {
+		err = fmt.Errorf("statement entry is already matched")
+		return
+	}
+
+	if args.PaymentRequestID == nil {
+		entry.Status = model.StatementEntryDismissed
+		err = config.DB.Save(&entry).Error
+		return
+	}
+	request, err := findOwnPaymentRequest(userID, *args.PaymentRequestID)
+	if err != nil {
+		return
+	}
+	if request.IbanID != entry.IbanID {
+		err = fmt.Errorf("payment request is for another IBAN")
+		return
+	}
+	if request.Status != model.PaymentRequestOpen {
+		err = fmt.Errorf("payment request is %s", request.Status)
+		return
+	}
+	err = config.DB.Transaction(func(tx *gorm.DB) error {
+		request.Status = model.PaymentRequestPaid
+		if err := tx.Save(&request).Error; err != nil {
+			return err
+		}
+		entry.Status = model.StatementEntryMatched
+		entry.PaymentRequestID = &request.PaymentRequestID
+		entry.Note = ""
+		return tx.Save(&entry).Error
+	})
+	return
+}
+
+type StatementEntryResolveMutationArgs struct {
+	Id               graphql.ID
+	PaymentRequestID *graphql.ID
+}
+
+// StatementEntryResolveResponse is the response type
+type StatementEntryResolveResponse struct {
+	Status bool
+	Msg    *string
+	Entry  *StatementEntryResponse
+}
+
+// Ok for StatementEntryResolveResponse
+func (r *StatementEntryResolveResponse) Ok() bool {
+	return r.Status
+}
+
+// Error for StatementEntryResolveResponse
+func (r *StatementEntryResolveResponse) Error() *string {
+	return r.Msg
+}
@@ -0,0 +1,94 @@
+package resolvers
+
+import (
+	"strconv"
+	"time"
+
+	graphql "github.com/graph-gophers/graphql-go"
+
+	"github.com/tapsilat/iban.im/config"
+	"github.com/tapsilat/iban.im/handler"
+	"github.com/tapsilat/iban.im/model"
+)
+
+// StatementEntryResponse is the statement entry response type
+type StatementEntryResponse struct {
+	e *model.StatementEntry
+}
+
+// ID for StatementEntryResponse
+func (r *StatementEntryResponse) ID() graphql.ID {
+	return graphql.ID(strconv.Itoa(int(r.e.StatementEntryID)))
+}
+
+// IbanID for StatementEntryResponse
+func (r *StatementEntryResponse) IbanID() graphql.ID {
+	return graphql.ID(strconv.Itoa(int(r.e.IbanID)))
+}
+
+// BookingDate for StatementEntryResponse
+func (r *StatementEntryResponse) BookingDate() string {
+	return r.e.BookingDate.Format(time.DateOnly)
+}
+
+// Amount for StatementEntryResponse
+func (r *StatementEntryResponse) Amount() string {
+	return handler.FormatAmount(r.e.Amount)
+}
+
+// Currency for StatementEntryResponse
+func (r *StatementEntryResponse) Currency() string {
+	return r.e.Currency
+}
+
+// Reference for StatementEntryResponse
+func (r *StatementEntryResponse) Reference() *string {
+	return optional(r.e.Reference)
+}
+
+// Remittance for StatementEntryResponse
+func (r *StatementEntryResponse) Remittance() *string {
+	return optional(r.e.Remittance)
+}
+
+// RemitterName for StatementEntryResponse
+func (r *StatementEntryResponse) RemitterName() *string {
+	return optional(r.e.RemitterName)
+}
+
+// RemitterIban for StatementEntryResponse
+func (r *StatementEntryResponse) RemitterIban() *string {
+	return optional(r.e.RemitterIBAN)
+}
+
+// BankReference for StatementEntryResponse
+func (r *StatementEntryResponse) BankReference() *string {
+	return optional(r.e.BankReference)
+}
+
+// Status for StatementEntryResponse
+func (r *StatementEntryResponse) Status() string {
+	return r.e.Status
+}
+
+// Note for StatementEntryResponse
+func (r *StatementEntryResponse) Note() *string {
+	return optional(r.e.Note)
+}
+
+// PaymentRequest for StatementEntryResponse, the request the entry paid
+func (r *StatementEntryResponse) PaymentRequest() *PaymentRequestResponse {
+	if r.e.PaymentRequestID == nil {
+		return nil
+	}
+	var request model.PaymentRequest
+	if err := config.DB.First(&request, *r.e.PaymentRequestID).Error; err != nil {
+		return nil
+	}
+	return &PaymentRequestResponse{p: &request}
+}
+
+// CreatedAt for StatementEntryResponse
+func (r *StatementEntryResponse) CreatedAt() string {
+	return r.e.CreatedAt.String()
+}
@@ -0,0 +1,206 @@
+package resolvers
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"unicode"
+
+	graphql "github.com/graph-gophers/graphql-go"
+	"gorm.io/gorm"
+
+	"github.com/tapsilat/iban.im/config"
+	"github.com/tapsilat/iban.im/handler"
+	"github.com/tapsilat/iban.im/iso13616"
+	"github.com/tapsilat/iban.im/model"
+	"github.com/tapsilat/iban.im/statement"
+)
+
+// StatementImport mutation reads a CAMT.053 or MT940 statement of one of the
+// user's IBANs, marks the open payment requests it pays as paid and keeps
+// the other incoming payments for review. Entries imported before are
+// skipped, so overlapping statements can be imported.
+func (r *Resolvers) StatementImport(ctx context.Context, args StatementImportMutationArgs) (response *StatementImportResponse, err error) {
+	response = &StatementImportResponse{Entries: []*StatementEntryResponse{}}
+
+	defer func() {
+		if reportError(&err, &response.Msg) {
+			// Nothing was imported
+			*response = StatementImportResponse{Msg: response.Msg, Entries: []*StatementEntryResponse{}}
+			return
+		}
+		response.Status = true
+	}()
+
+	userID := ctx.Value(handler.ContextKey("UserID"))
+	if userID == nil {
+		err = fmt.Errorf("not authorized")
+		return
+	}
+	iban := r.GetIbanById(args.IbanID)
+	if iban.IbanID == 0 {
+		err = fmt.Errorf("iban is not exist")
+		return
+	}
+	// Only the payment requests of users can be reconciled
+	if iban.OwnerType == model.OwnerGroup {
+		err = fmt.Errorf("statements can only be imported for your own IBANs")
+		return
+	}
+	if iban.OwnerID != uint(userID.(int)) {
+		err = fmt.Errorf("not authorized")
+		return
+	}
+	if !iban.IsIBAN() {
+		err = fmt.Errorf("statements can only be imported for IBANs")
+		return
+	}
+
+	stmt, err := statement.Parse([]byte(args.Statement))
+	if err != nil {
+		return
+	}
+	if iso13616.IsValid(stmt.Account) && iso13616.Normalize(stmt.Account) != iso13616.Normalize(iban.Text) {
+		err = fmt.Errorf("statement is for %s, not for this IBAN", iso13616.PrintFormat(stmt.Account))
+		return
+	}
+
+	err = config.DB.Transaction(func(tx *gorm.DB) error {
+		var requests []model.PaymentRequest
+		if err := tx.Where("iban_id = ? AND status = ?", iban.IbanID, model.PaymentRequestOpen).Order("created_at").Find(&requests).Error; err != nil {
+			return err
+		}
+		for _, credit := range stmt.Credits() {
+			entry := model.StatementEntry{
+				OwnerID:       iban.OwnerID,
+				IbanID:        iban.IbanID,
+				BookingDate:   credit.BookingDate,
+				Amount:        credit.Amount,
+				Currency:      credit.Currency,
+				Reference:     credit.Reference,
+				Remittance:    credit.Remittance,
+				RemitterName:  credit.RemitterName,
+				RemitterIBAN:  credit.RemitterIBAN,
+				BankReference: credit.BankReference,
+			}
+			entry.Fingerprint = entry.ComputeFingerprint()
+			var count int64
+			tx.Model(&model.StatementEntry{}).Where("iban_id = ? AND fingerprint = ?", iban.IbanID, entry.Fingerprint).Count(&count)
+			if count > 0 {
+				response.Duplicates++
+				continue
+			}
+
+			match, note := matchPaymentRequest(requests, entry)
+			if match >= 0 {
+				request := requests[match]
+				request.Status = model.PaymentRequestPaid
+				if err := tx.Save(&request).Error; err != nil {
+					return err
+				}
+				requests = append(requests[:match], requests[match+1:]...)
+				entry.Status = model.StatementEntryMatched
+				entry.PaymentRequestID = &request.PaymentRequestID
+				response.Matched++
+			} else {
+				entry.Note = note
+				response.Unmatched++
+			}
+			if err := tx.Create(&entry).Error; err != nil {
+				return err
+			}
+			response.Entries = append(response.Entries, &StatementEntryResponse{e: &entry})
+		}
+		return nil
+	})
+	return
+}
+
+// matchPaymentRequest finds the open request an incoming payment pays and
+// returns its index, or -1 and why there is none. A creditor reference
+// decides on its own, provided the amount fits. Without one, the amount and
+// the remitter must match the payer of exactly one request.
+func matchPaymentRequest(requests []model.PaymentRequest, entry model.StatementEntry) (int, string) {
+	amountFits := func(request model.PaymentRequest) bool {
+		return request.Amount == 0 || request.Amount == entry.Amount && request.Currency == entry.Currency
+	}
+
+	if entry.Reference != "" {
+		for i, request := range requests {
+			if request.Reference != entry.Reference {
+				continue
+			}
+			if !amountFits(request) {
+				return -1, fmt.Sprintf("amount differs from the %s %s requested with reference %s", handler.FormatAmount(request.Amount), request.Currency, entry.Reference)
+			}
+			return i, ""
+		}
+		return -1, fmt.Sprintf("no open payment request with reference %s", entry.Reference)
+	}
+
+	match := -1
+	for i, request := range requests {
+		if request.Amount == 0 || !amountFits(request) || !samePayer(request.Payer, entry.RemitterName) {
+			continue
+		}
+		if match >= 0 {
+			return -1, "several open payment requests match amount and remitter"
+		}
+		match = i
+	}
+	if match < 0 {
+		return -1, "no open payment request matches amount and remitter"
+	}
+	return match, ""
+}
+
+// samePayer reports whether all words of the expected payer appear in the
+// remitter name of the statement, which banks often reorder and upper-case,
+// e.g. "MUSTERMANN, FRANZ"
+func samePayer(payer, remitter string) bool {
+	split := func(s string) []string {
+		return strings.FieldsFunc(strings.ToLower(s), func(r rune) bool {
+			return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+		})
+	}
+	words := split(payer)
+	if len(words) == 0 {
+		return false
+	}
+	remitterWords := split(remitter)
+	for _, word := range words {
+		found := false
+		for _, other := range remitterWords {
+			found = found || word == other
+		}
+		if !found {
+			return false
+		}
+	}
+	return true
+}
+
+type StatementImportMutationArgs struct {
+	IbanID    graphql.ID
+	Statement string
+}
+
+// StatementImportResponse is the response type
+type StatementImportResponse struct {
+	Status     bool
+	Msg        *string
+	Matched    int32
+	Unmatched  int32
+	Duplicates int32
+	Entries    []*StatementEntryResponse
+}
+
+// Ok for StatementImportResponse
+func (r *StatementImportResponse) Ok() bool {
+	return r.Status
+}
+
+// Error for StatementImportResponse
+func (r *StatementImportResponse) Error() *string {
+	return r.Msg
+}
@@ -0,0 +1,213 @@
+package resolvers
+
+import (
+	"context"
+	"strconv"
+	"strings"
+	"testing"
+
+	graphql "github.com/graph-gophers/graphql-go"
+
+	"github.com/tapsilat/iban.im/model"
+)
+
+const testStatement = `<?xml version="1.0" encoding="UTF-8"?>
+<Document xmlns="urn:iso:std:iso:20022:tech:xsd:camt.053.001.02">
+  <BkToCstmrStmt><Stmt>
+    <Acct><Id><IBAN>DE89370400440532013000</IBAN></Id><Ccy>EUR</Ccy></Acct>
+    <Ntry>
+      <Amt Ccy="EUR">12.50</Amt><CdtDbtInd>CRDT</CdtDbtInd><Sts>BOOK</Sts>
+      <BookgDt><Dt>2024-03-01</Dt></BookgDt><AcctSvcrRef>E1</AcctSvcrRef>
+      <NtryDtls><TxDtls><RmtInf><Strd><CdtrRefInf><Ref>RF17INV20240001</Ref></CdtrRefInf></Strd></RmtInf></TxDtls></NtryDtls>
+    </Ntry>
+    <Ntry>
+      <Amt Ccy="EUR">40.00</Amt><CdtDbtInd>CRDT</CdtDbtInd><Sts>BOOK</Sts>
+      <BookgDt><Dt>2024-03-01</Dt></BookgDt><AcctSvcrRef>E2</AcctSvcrRef>
+      <NtryDtls><TxDtls><RltdPties><Dbtr><Nm>MUSTERFRAU, ERIKA</Nm></Dbtr></RltdPties><RmtInf><Ustrd>Rent</Ustrd></RmtInf></TxDtls></NtryDtls>
+    </Ntry>
+    <Ntry>
+      <Amt Ccy="EUR">15.00</Amt><CdtDbtInd>CRDT</CdtDbtInd><Sts>BOOK</Sts>
+      <BookgDt><Dt>2024-03-02</Dt></BookgDt><AcctSvcrRef>E3</AcctSvcrRef>
+      <NtryDtls><TxDtls><RmtInf><Ustrd>Dinner RF18 5390 0754 7034</Ustrd></RmtInf></TxDtls></NtryDtls>
+    </Ntry>
+    <Ntry>
+      <Amt Ccy="EUR">7.00</Amt><CdtDbtInd>CRDT</CdtDbtInd><Sts>BOOK</Sts>
+      <BookgDt><Dt>2024-03-02</Dt></BookgDt><AcctSvcrRef>E4</AcctSvcrRef>
+      <NtryDtls><TxDtls><RmtInf><Ustrd>Gift</Ustrd></RmtInf></TxDtls></NtryDtls>
+    </Ntry>
+    <Ntry>
+      <Amt Ccy="EUR">3.00</Amt><CdtDbtInd>DBIT</CdtDbtInd><Sts>BOOK</Sts>
+      <BookgDt><Dt>2024-03-02</Dt></BookgDt>
+    </Ntry>
+  </Stmt></BkToCstmrStmt>
+</Document>`
+
+func TestStatementImport(t *testing.T) {
+	resolver, db, cleanup := setupTestResolverWithDB(t)
+	defer cleanup()
+
+	user := createTestUser(t, db, "test@example.com", "pass", "testuser", "Test", "User")
+	other := createTestUser(t, db, "other@example.com", "pass", "other", "Other", "User")
+	iban := createTestIban(t, db, user.UserID, "DE89370400440532013000", "rent", "", false)
+	otherIban := createTestIban(t, db, user.UserID, "TR320010009999901234567890", "savings", "", false)
+	invoice := model.PaymentRequest{OwnerID: user.UserID, IbanID: iban.IbanID, Amount: 1250, Currency: "EUR", Reference: "RF17INV20240001"}
+	rent := model.PaymentRequest{OwnerID: user.UserID, IbanID: iban.IbanID, Amount: 4000, Currency: "EUR", Payer: "Erika Musterfrau"}
+	dinner := model.PaymentRequest{OwnerID: user.UserID, IbanID: iban.IbanID, Amount: 2000, Currency: "EUR", Reference: "RF18539007547034"}
+	for _, request := range []*model.PaymentRequest{&invoice, &rent, &dinner} {
+		if err := db.Create(request).Error; err != nil {
+			t.Fatalf("Failed to create payment request: %v", err)
+		}
+	}
+
+	ctx := contextWithUserID(int(user.UserID))
+	ibanID := graphql.ID(strconv.Itoa(int(iban.IbanID)))
+	tests := []struct {
+		name          string
+		ctx           context.Context
+		args          StatementImportMutationArgs
+		expectSuccess bool
+		expectError   string
+		matched       int32
+		unmatched     int32
+		duplicates    int32
+	}{
+		{
+			name:        "Without context",
+			ctx:         context.Background(),
+			args:        StatementImportMutationArgs{IbanID: ibanID, Statement: testStatement},
+			expectError: "not authorized",
+		},
+		{
+			name:        "Someone else",
+			ctx:         contextWithUserID(int(other.UserID)),
+			args:        StatementImportMutationArgs{IbanID: ibanID, Statement: testStatement},
+			expectError: "not authorized",
+		},
+		{
+			name:        "Statement of another account",
+			ctx:         ctx,
+			args:        StatementImportMutationArgs{IbanID: graphql.ID(strconv.Itoa(int(otherIban.IbanID))), Statement: testStatement},
+			expectError: "statement is for DE89 3704 0044 0532 0130 00, not for this IBAN",
+		},
+		{
+			name:        "Not a statement",
+			ctx:         ctx,
+			args:        StatementImportMutationArgs{IbanID: ibanID, Statement: "iban,amount"},
+			expectError: "unknown statement format, expected CAMT.053 XML or MT940",
+		},
+		{
+			name:          "First import",
+			ctx:           ctx,
+			args:          StatementImportMutationArgs{IbanID: ibanID, Statement: testStatement},
+			expectSuccess: true,
+			matched:       2,
+			unmatched:     2,
+		},
+		{
+			name:          "Same statement again",
+			ctx:           ctx,
+			args:          StatementImportMutationArgs{IbanID: ibanID, Statement: testStatement},
+			expectSuccess: true,
+			duplicates:    4,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			resp, err := resolver.StatementImport(tt.ctx, tt.args)
+			if err != nil {
+				t.Fatalf("StatementImport returned unexpected error: %v", err)
+			}
+			if resp.Ok() != tt.expectSuccess {
+				t.Errorf("Ok() = %v, want %v (error %v)", resp.Ok(), tt.expectSuccess, resp.Error())
+			}
+			if tt.expectError != "" && (resp.Error() == nil || *resp.Error() != tt.expectError) {
+				t.Errorf("Error() = %v, want %s", resp.Error(), tt.expectError)
+			}
+			if resp.Matched != tt.matched || resp.Unmatched != tt.unmatched || resp.Duplicates != tt.duplicates {
+				t.Errorf("got %d matched, %d unmatched, %d duplicates, want %d, %d, %d", resp.Matched, resp.Unmatched, resp.Duplicates, tt.matched, tt.unmatched, tt.duplicates)
+			}
+		})
+	}
+
+	for _, request := range []*model.PaymentRequest{&invoice, &rent, &dinner} {
+		db.First(request, request.PaymentRequestID)
+	}
+	if invoice.Status != model.PaymentRequestPaid || rent.Status != model.PaymentRequestPaid || dinner.Status != model.PaymentRequestOpen {
+		t.Errorf("Statuses = %s, %s, %s, want paid, paid, open", invoice.Status, rent.Status, dinner.Status)
+	}
+
+	review, _ := resolver.GetMyStatementEntries(ctx, GetMyStatementEntriesQueryArgs{Status: strPtr(model.StatementEntryUnmatched)})
+	if !review.Ok() || len(review.Entries) != 2 {
+		t.Fatalf("GetMyStatementEntries() = %v with %d entries, want 2 unmatched", review.Ok(), len(review.Entries))
+	}
+	gift, partial := review.Entries[0], review.Entries[1]
+	if *partial.Reference() != "RF18539007547034" || !strings.HasPrefix(*partial.Note(), "amount differs") {
+		t.Errorf("First entry = %v, %v, want the partial payment of the dinner", partial.Reference(), partial.Note())
+	}
+	if gift.Amount() != "7.00" || *gift.Note() != "no open payment request matches amount and remitter" {
+		t.Errorf("Second entry = %s, %v, want the gift", gift.Amount(), gift.Note())
+	}
+
+	resolved, _ := resolver.StatementEntryResolve(contextWithUserID(int(other.UserID)), StatementEntryResolveMutationArgs{Id: partial.ID()})
+	if resolved.Ok() {
+		t.Errorf("StatementEntryResolve by someone else succeeded")
+	}
+	dinnerID := graphql.ID(strconv.Itoa(int(dinner.PaymentRequestID)))
+	resolved, _ = resolver.StatementEntryResolve(ctx, StatementEntryResolveMutationArgs{Id: partial.ID(), PaymentRequestID: &dinnerID})
+	if !resolved.Ok() || resolved.Entry.Status() != model.StatementEntryMatched || resolved.Entry.PaymentRequest().Status() != model.PaymentRequestPaid {
+		t.Errorf("StatementEntryResolve() = %v, %v, want matched to the paid dinner", resolved.Ok(), resolved.Error())
+	}
+	resolved, _ = resolver.StatementEntryResolve(ctx, StatementEntryResolveMutationArgs{Id: partial.ID()})
+	if resolved.Ok() || *resolved.Error() != "statement entry is already matched" {
+		t.Errorf("Resolving twice: %v, %v", resolved.Ok(), resolved.Error())
+	}
+	resolved, _ = resolver.StatementEntryResolve(ctx, StatementEntryResolveMutationArgs{Id: gift.ID(), PaymentRequestID: &dinnerID})
+	if resolved.Ok() || *resolved.Error() != "payment request is paid" {
+		t.Errorf("Resolving with a paid request: %v, %v", resolved.Ok(), resolved.Error())
+	}
+	resolved, _ = resolver.StatementEntryResolve(ctx, StatementEntryResolveMutationArgs{Id: gift.ID()})
+	if !resolved.Ok() || resolved.Entry.Status() != model.StatementEntryDismissed {
+		t.Errorf("Dismissing: %v, %v", resolved.Ok(), resolved.Error())
+	}
+
+	review, _ = resolver.GetMyStatementEntries(ctx, GetMyStatementEntriesQueryArgs{Status: strPtr(model.StatementEntryUnmatched)})
+	if len(review.Entries) != 0 {
+		t.Errorf("Review list has %d entries after resolving, want 0", len(review.Entries))
+	}
+}
+
+func TestMatchPaymentRequest(t *testing.T) {
+	requests := []model.PaymentRequest{
+		{Amount: 1250, Currency: "EUR", Reference: "RF17INV20240001"},
+		{Reference: "RF18539007547034"},
+		{Amount: 4000, Currency: "EUR", Payer: "Erika Musterfrau"},
+		{Amount: 5000, Currency: "EUR", Payer: "Franz"},
+		{Amount: 5000, Currency: "EUR", Payer: "Franz Mustermann"},
+		{Amount: 6000, Currency: "EUR"},
+	}
+	tests := []struct {
+		name  string
+		entry model.StatementEntry
+		want  int
+		note  string
+	}{
+		{name: "Reference and amount", entry: model.StatementEntry{Amount: 1250, Currency: "EUR", Reference: "RF17INV20240001"}, want: 0},
+		{name: "Reference in another currency", entry: model.StatementEntry{Amount: 1250, Currency: "CHF", Reference: "RF17INV20240001"}, want: -1, note: "amount differs from the 12.50 EUR requested with reference RF17INV20240001"},
+		{name: "Reference with open amount", entry: model.StatementEntry{Amount: 99, Currency: "EUR", Reference: "RF18539007547034"}, want: 1},
+		{name: "Unknown reference", entry: model.StatementEntry{Amount: 4000, Currency: "EUR", Reference: "RF741", RemitterName: "Erika Musterfrau"}, want: -1, note: "no open payment request with reference RF741"},
+		{name: "Amount and remitter", entry: model.StatementEntry{Amount: 4000, Currency: "EUR", RemitterName: "MUSTERFRAU, ERIKA"}, want: 2},
+		{name: "Amount and other remitter", entry: model.StatementEntry{Amount: 4000, Currency: "EUR", RemitterName: "Erika Beispiel"}, want: -1, note: "no open payment request matches amount and remitter"},
+		{name: "Several payers match", entry: model.StatementEntry{Amount: 5000, Currency: "EUR", RemitterName: "Franz Mustermann"}, want: -1, note: "several open payment requests match amount and remitter"},
+		{name: "Amount without payer", entry: model.StatementEntry{Amount: 6000, Currency: "EUR", RemitterName: "Anyone"}, want: -1, note: "no open payment request matches amount and remitter"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, note := matchPaymentRequest(requests, tt.entry)
+			if got != tt.want || note != tt.note {
+				t.Errorf("matchPaymentRequest() = %d, %q, want %d, %q", got, note, tt.want, tt.note)
+			}
+		})
+	}
+}
@@ -0,0 +1,66 @@
+package resolvers
+
+import (
+	"context"
+
+	"github.com/tapsilat/iban.im/iso13616"
+)
+
+// SuggestIbanCorrections query offers valid IBANs close to a mistyped one
+func (r *Resolvers) SuggestIbanCorrections(ctx context.Context, args SuggestIbanCorrectionsQueryArgs) (*SuggestIbanCorrectionsResponse, error) {
+	if err := iso13616.Validate(args.Text); err != nil {
+		msg := err.Error()
+		return &SuggestIbanCorrectionsResponse{Status: false, Msg: &msg, Suggestions: suggestIbans(args.Text)}, nil
+	}
+	return &SuggestIbanCorrectionsResponse{Status: true, Suggestions: []*IbanSuggestionResponse{}}, nil
+}
+
+// suggestIbans returns the correction candidates for text
+func suggestIbans(text string) []*IbanSuggestionResponse {
+	suggestions := []*IbanSuggestionResponse{}
+	for _, s := range iso13616.Suggest(text) {
+		suggestions = append(suggestions, &IbanSuggestionResponse{s: s})
+	}
+	return suggestions
+}
+
+type SuggestIbanCorrectionsQueryArgs struct {
+	Text string
+}
+
+// SuggestIbanCorrectionsResponse is the response type
+type SuggestIbanCorrectionsResponse struct {
+	Status      bool
+	Msg         *string
+	Suggestions []*IbanSuggestionResponse
+}
+
+// Ok for SuggestIbanCorrectionsResponse
+func (r *SuggestIbanCorrectionsResponse) Ok() bool {
+	return r.Status
+}
+
+// Error for SuggestIbanCorrectionsResponse
+func (r *SuggestIbanCorrectionsResponse) Error() *string {
+	return r.Msg
+}
+
+// IbanSuggestionResponse is a corrected IBAN candidate
+type IbanSuggestionResponse struct {
+	s iso13616.Suggestion
+}
+
+// Iban for IbanSuggestionResponse
+func (r *IbanSuggestionResponse) Iban() string {
+	return r.s.IBAN
+}
+
+// PrintFormat for IbanSuggestionResponse
+func (r *IbanSuggestionResponse) PrintFormat() string {
+	return iso13616.PrintFormat(r.s.IBAN)
+}
+
+// Reason for IbanSuggestionResponse
+func (r *IbanSuggestionResponse) Reason() string {
+	return r.s.Reason
+}
@@ -19,7 +19,7 @@ func setupTestDB(t *testing.T) *gorm.DB {
 	}
 
 	// Auto-migrate all models
-	if err := db.AutoMigrate(&model.User{}, &model.Iban{}, &model.Group{}); err != nil {
+	if err := db.AutoMigrate(&model.User{}, &model.Iban{}, &model.Group{}, &model.RetiredGroupHandle{}, &model.GroupMember{}, &model.IbanChange{}, &model.PaymentRequest{}, &model.StatementEntry{}); err != nil {
 		t.Fatalf("Failed to auto-migrate: %v", err)
 	}
 
@@ -56,6 +56,31 @@ func (r *UserResponse) Avatar() *string {
 	return &r.u.Avatar
 }
 
+// Street for UserResponse
+func (r *UserResponse) Street() *string {
+	return &r.u.Street
+}
+
+// BuildingNumber for UserResponse
+func (r *UserResponse) BuildingNumber() *string {
+	return &r.u.BuildingNumber
+}
+
+// PostalCode for UserResponse
+func (r *UserResponse) PostalCode() *string {
+	return &r.u.PostalCode
+}
+
+// Town for UserResponse
+func (r *UserResponse) Town() *string {
+	return &r.u.Town
+}
+
+// Country for UserResponse
+func (r *UserResponse) Country() *string {
+	return &r.u.Country
+}
+
 // CreatedAt for UserResponse
 func (r *UserResponse) CreatedAt() string {
 	return r.u.CreatedAt.String()
@@ -0,0 +1,140 @@
+package statement
+
+import (
+	"encoding/xml"
+	"fmt"
+	"strings"
+	"time"
+)
+
+// camtDocument is the part of a camt.053 document the import needs. The
+// element names are the same in all versions from 001.02 to 001.12, only
+// the party of debtors moved into a Pty element in 001.08.
+type camtDocument struct {
+	Statements []struct {
+		Account struct {
+			IBAN     string `xml:"Id>IBAN"`
+			Currency string `xml:"Ccy"`
+		} `xml:"Acct"`
+		Entries []camtEntry `xml:"Ntry"`
+	} `xml:"BkToCstmrStmt>Stmt"`
+}
+
+type camtEntry struct {
+	Amount      camtAmount `xml:"Amt"`
+	CreditDebit string     `xml:"CdtDbtInd"`
+	Reversal    bool       `xml:"RvslInd"`
+	Status      struct {
+		Text string `xml:",chardata"`
+		Code string `xml:"Cd"`
+	} `xml:"Sts"`
+	BookingDate   string `xml:"BookgDt>Dt"`
+	BookingTime   string `xml:"BookgDt>DtTm"`
+	BankReference string `xml:"AcctSvcrRef"`
+	Transactions  []struct {
+		Amount        camtAmount `xml:"Amt"`
+		TxAmount      camtAmount `xml:"AmtDtls>TxAmt>Amt"`
+		BankReference string     `xml:"Refs>AcctSvcrRef"`
+		Debtor        string     `xml:"RltdPties>Dbtr>Nm"`
+		DebtorParty   string     `xml:"RltdPties>Dbtr>Pty>Nm"`
+		DebtorIBAN    string     `xml:"RltdPties>DbtrAcct>Id>IBAN"`
+		Unstructured  []string   `xml:"RmtInf>Ustrd"`
+		Reference     string     `xml:"RmtInf>Strd>CdtrRefInf>Ref"`
+	} `xml:"NtryDtls>TxDtls"`
+}
+
+type camtAmount struct {
+	Currency string `xml:"Ccy,attr"`
+	Value    string `xml:",chardata"`
+}
+
+// ParseCAMT053 reads the booked entries of a camt.053 bank to customer
+// statement. Batch entries yield one entry per transaction.
+func ParseCAMT053(data []byte) (*Statement, error) {
+	var document camtDocument
+	if err := xml.Unmarshal(data, &document); err != nil {
+		return nil, fmt.Errorf("invalid CAMT.053 document: %w", err)
+	}
+	if len(document.Statements) == 0 {
+		return nil, fmt.Errorf("invalid CAMT.053 document: no statement found")
+	}
+
+	result := &Statement{}
+	for _, stmt := range document.Statements {
+		if result.Account == "" {
+			result.Account = stmt.Account.IBAN
+			result.Currency = stmt.Account.Currency
+		}
+		for _, ntry := range stmt.Entries {
+			if ntry.Reversal || !camtBooked(ntry) {
+				continue
+			}
+			date, err := camtDate(ntry)
+			if err != nil {
+				return nil, err
+			}
+			base := Entry{
+				BookingDate:   date,
+				Currency:      ntry.Amount.Currency,
+				Credit:        ntry.CreditDebit == "CRDT",
+				BankReference: ntry.BankReference,
+			}
+			if len(ntry.Transactions) == 0 {
+				if base.Amount, err = parseAmount(ntry.Amount.Value); err != nil {
+					return nil, err
+				}
+				base.finish(stmt.Account.Currency)
+				result.Entries = append(result.Entries, base)
+				continue
+			}
+			for _, tx := range ntry.Transactions {
+				entry := base
+				amount := tx.Amount
+				switch {
+				case amount.Value == "" && tx.TxAmount.Value != "":
+					amount = tx.TxAmount
+				case amount.Value == "" && len(ntry.Transactions) == 1:
+					amount = ntry.Amount
+				case amount.Value == "":
+					return nil, fmt.Errorf("batch entry %s lacks transaction amounts", ntry.BankReference)
+				}
+				if entry.Amount, err = parseAmount(amount.Value); err != nil {
+					return nil, err
+				}
+				if amount.Currency != "" {
+					entry.Currency = amount.Currency
+				}
+				if tx.BankReference != "" {
+					entry.BankReference = tx.BankReference
+				}
+				entry.RemitterName = tx.Debtor + tx.DebtorParty
+				entry.RemitterIBAN = tx.DebtorIBAN
+				entry.Remittance = strings.Join(tx.Unstructured, " ")
+				entry.Reference = tx.Reference
+				entry.finish(stmt.Account.Currency)
+				result.Entries = append(result.Entries, entry)
+			}
+		}
+	}
+	return result, nil
+}
+
+// camtBooked reports whether the entry is booked rather than pending; the
+// status is a plain code up to 001.06 and a Cd element from 001.07 on.
+func camtBooked(ntry camtEntry) bool {
+	status := strings.TrimSpace(ntry.Status.Code)
+	if status == "" {
+		status = strings.TrimSpace(ntry.Status.Text)
+	}
+	return status == "" || status == "BOOK"
+}
+
+func camtDate(ntry camtEntry) (time.Time, error) {
+	if ntry.BookingDate != "" {
+		return time.Parse(time.DateOnly, ntry.BookingDate)
+	}
+	if ntry.BookingTime != "" {
+		return time.Parse(time.RFC3339, ntry.BookingTime)
+	}
+	return time.Time{}, fmt.Errorf("entry %s has no booking date", ntry.BankReference)
+}
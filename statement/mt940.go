@@ -0,0 +1,182 @@
+package statement
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+	"time"
+)
+
+var (
+	mt940Tag = regexp.MustCompile(`^:([0-9]{2}[A-Z]?):`)
+	// mt940Line is field 61: value date, optional entry date, debit/credit
+	// mark, funds code, amount, transaction type, customer and bank reference
+	mt940Line = regexp.MustCompile(`^([0-9]{6})([0-9]{4})?(RC|RD|C|D)([A-Z])?([0-9]+,[0-9]*)([NSF][A-Z0-9]{3})([^/\n]*)(?://([^\n]*))?`)
+	// mt940Subfield splits the German "?20" style of field 86
+	mt940Subfield = regexp.MustCompile(`\?([0-9]{2})`)
+	// mt940Code splits the "/NAME/" style of field 86 used by Dutch and
+	// Belgian banks
+	mt940Code = regexp.MustCompile(`/(EREF|ORDP|NAME|IBAN|BIC|ADDR|REMI|CDTRREF|CDTRREFTP|CSID|MARF|PURP|ISDT|BENM|ULTC|ULTD|TRCD)/`)
+)
+
+// ParseMT940 reads the statement lines (field 61) of an MT940 file with
+// the information to the account owner (field 86) that follows each one.
+// Reversals are left out.
+func ParseMT940(data []byte) (*Statement, error) {
+	fields, err := mt940Fields(string(data))
+	if err != nil {
+		return nil, err
+	}
+
+	result := &Statement{}
+	var current *Entry
+	var reversal bool
+	flush := func() {
+		if current != nil && !reversal {
+			result.Entries = append(result.Entries, *current)
+		}
+		current = nil
+	}
+	for _, field := range fields {
+		switch field.tag {
+		case "25":
+			if result.Account == "" {
+				result.Account = strings.ReplaceAll(strings.TrimSpace(field.value), " ", "")
+			}
+		case "60F", "60M":
+			if len(field.value) >= 10 && result.Currency == "" {
+				result.Currency = field.value[7:10]
+			}
+		case "61":
+			flush()
+			entry, isReversal, err := mt940Entry(field.value)
+			if err != nil {
+				return nil, err
+			}
+			current, reversal = &entry, isReversal
+		case "86":
+			if current != nil {
+				mt940Information(current, field.value)
+			}
+		}
+	}
+	flush()
+
+	if result.Account == "" && len(result.Entries) == 0 {
+		return nil, ErrFormat
+	}
+	for i := range result.Entries {
+		result.Entries[i].finish(result.Currency)
+	}
+	return result, nil
+}
+
+type mt940Field struct {
+	tag   string
+	value string
+}
+
+// mt940Fields splits the message text into fields, keeping the line breaks
+// of continuation lines. SWIFT block headers and the "-" trailer are
+// skipped.
+func mt940Fields(text string) ([]mt940Field, error) {
+	var fields []mt940Field
+	for _, line := range strings.Split(strings.ReplaceAll(text, "\r\n", "\n"), "\n") {
+		if m := mt940Tag.FindStringSubmatch(line); m != nil {
+			fields = append(fields, mt940Field{tag: m[1], value: line[len(m[0]):]})
+			continue
+		}
+		trimmed := strings.TrimSpace(line)
+		if trimmed == "" || strings.HasPrefix(trimmed, "{") || strings.HasPrefix(trimmed, "-") {
+			continue
+		}
+		if len(fields) == 0 {
+			return nil, fmt.Errorf("invalid MT940 file: text before the first field")
+		}
+		fields[len(fields)-1].value += "\n" + line
+	}
+	return fields, nil
+}
+
+// mt940Entry reads field 61. The booking date is the entry date where
+// given, which may fall into the year before or after the value date.
+func mt940Entry(value string) (Entry, bool, error) {
+	m := mt940Line.FindStringSubmatch(value)
+	if m == nil {
+		return Entry{}, false, fmt.Errorf("invalid MT940 statement line %q", strings.SplitN(value, "\n", 2)[0])
+	}
+	date, err := time.Parse("060102", m[1])
+	if err != nil {
+		return Entry{}, false, fmt.Errorf("invalid MT940 value date %s", m[1])
+	}
+	if m[2] != "" {
+		booking, err := time.Parse("20060102", date.Format("2006")+m[2])
+		if err != nil {
+			return Entry{}, false, fmt.Errorf("invalid MT940 entry date %s", m[2])
+		}
+		switch {
+		case booking.Sub(date) > 180*24*time.Hour:
+			booking = booking.AddDate(-1, 0, 0)
+		case date.Sub(booking) > 180*24*time.Hour:
+			booking = booking.AddDate(1, 0, 0)
+		}
+		date = booking
+	}
+	amount, err := parseAmount(m[5])
+	if err != nil {
+		return Entry{}, false, err
+	}
+	return Entry{
+		BookingDate:   date,
+		Amount:        amount,
+		Credit:        m[3] == "C",
+		BankReference: strings.TrimSpace(m[8]),
+	}, strings.HasPrefix(m[3], "R"), nil
+}
+
+// mt940Information reads the remitter and the remittance text from field
+// 86, which banks structure in one of two ways or not at all.
+func mt940Information(entry *Entry, value string) {
+	joined := strings.ReplaceAll(value, "\n", "")
+	switch {
+	case mt940Subfield.MatchString(joined):
+		matches := mt940Subfield.FindAllStringSubmatchIndex(joined, -1)
+		for i, match := range matches {
+			end := len(joined)
+			if i+1 < len(matches) {
+				end = matches[i+1][0]
+			}
+			text := joined[match[1]:end]
+			switch code := joined[match[2]:match[3]]; {
+			case code >= "20" && code <= "29", code >= "60" && code <= "63":
+				entry.Remittance += text
+			case code == "31":
+				entry.RemitterIBAN = text
+			case code == "32", code == "33":
+				entry.RemitterName += text
+			}
+		}
+	case mt940Code.MatchString(joined):
+		matches := mt940Code.FindAllStringSubmatchIndex(joined, -1)
+		for i, match := range matches {
+			end := len(joined)
+			if i+1 < len(matches) {
+				end = matches[i+1][0]
+			}
+			text := strings.Trim(joined[match[1]:end], "/")
+			switch joined[match[2]:match[3]] {
+			case "NAME":
+				entry.RemitterName = text
+			case "IBAN":
+				entry.RemitterIBAN = text
+			case "REMI":
+				text = strings.TrimPrefix(text, "USTD//")
+				entry.Remittance = strings.TrimPrefix(text, "STRD/CUR/")
+			case "CDTRREF":
+				entry.Reference = text
+			}
+		}
+	default:
+		entry.Remittance = strings.ReplaceAll(value, "\n", " ")
+	}
+}
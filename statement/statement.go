@@ -0,0 +1,93 @@
+// Package statement reads the credit entries of bank statements in the
+// ISO 20022 CAMT.053 (XML) and SWIFT MT940 formats, so that incoming
+// payments can be matched to payment requests.
+package statement
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/tapsilat/iban.im/cents"
+	"github.com/tapsilat/iban.im/iso11649"
+)
+
+// ErrFormat is returned for files that are neither CAMT.053 nor MT940.
+var ErrFormat = errors.New("unknown statement format, expected CAMT.053 XML or MT940")
+
+// Statement is the account and the booked entries of one or more
+// statements in a file.
+type Statement struct {
+	// IBAN of the account; MT940 files may name it by bank code and
+	// account number instead.
+	Account  string
+	Currency string
+	Entries  []Entry
+}
+
+// Entry is a booked transaction.
+type Entry struct {
+	BookingDate time.Time
+	// Amount in cents, always positive; Credit tells the direction.
+	Amount   int64
+	Currency string
+	Credit   bool
+	// Reference is the ISO 11649 creditor reference given by the payer,
+	// either structured or found in the remittance text.
+	Reference    string
+	Remittance   string
+	RemitterName string
+	RemitterIBAN string
+	// BankReference is the bank's own reference of the entry, if any.
+	BankReference string
+}
+
+// Parse detects the format of data and reads it.
+func Parse(data []byte) (*Statement, error) {
+	trimmed := bytes.TrimSpace(bytes.TrimPrefix(data, []byte("\ufeff")))
+	switch {
+	case bytes.HasPrefix(trimmed, []byte("<")):
+		return ParseCAMT053(trimmed)
+	case bytes.Contains(trimmed, []byte(":61:")) || bytes.Contains(trimmed, []byte(":20:")):
+		return ParseMT940(trimmed)
+	}
+	return nil, ErrFormat
+}
+
+// Credits returns the incoming payments of the statement.
+func (s *Statement) Credits() []Entry {
+	var credits []Entry
+	for _, entry := range s.Entries {
+		if entry.Credit {
+			credits = append(credits, entry)
+		}
+	}
+	return credits
+}
+
+// finish fills in the currency of the statement and the creditor reference
+// from the remittance text where the bank did not report them separately.
+func (e *Entry) finish(currency string) {
+	if e.Currency == "" {
+		e.Currency = currency
+	}
+	e.Remittance = strings.Join(strings.Fields(e.Remittance), " ")
+	e.RemitterName = strings.Join(strings.Fields(e.RemitterName), " ")
+	if e.Reference != "" && iso11649.IsValid(e.Reference) {
+		e.Reference = iso11649.Normalize(e.Reference)
+	} else {
+		e.Reference = iso11649.Find(e.Remittance)
+	}
+}
+
+// parseAmount reads a decimal amount with a point (CAMT) or comma (MT940)
+// as decimal separator into cents.
+func parseAmount(s string) (int64, error) {
+	amount, err := cents.Parse(s)
+	if err != nil {
+		return 0, fmt.Errorf("invalid amount %s: %w", s, err)
+	}
+	return amount, nil
+}
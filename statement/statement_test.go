@@ -0,0 +1,183 @@
+package statement
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+const camt053 = `<?xml version="1.0" encoding="UTF-8"?>
+<Document xmlns="urn:iso:std:iso:20022:tech:xsd:camt.053.001.08">
+  <BkToCstmrStmt>
+    <Stmt>
+      <Acct><Id><IBAN>DE89370400440532013000</IBAN></Id><Ccy>EUR</Ccy></Acct>
+      <Ntry>
+        <Amt Ccy="EUR">12.50</Amt>
+        <CdtDbtInd>CRDT</CdtDbtInd>
+        <Sts><Cd>BOOK</Cd></Sts>
+        <BookgDt><Dt>2024-03-01</Dt></BookgDt>
+        <AcctSvcrRef>2024030100001</AcctSvcrRef>
+        <NtryDtls><TxDtls>
+          <RltdPties>
+            <Dbtr><Pty><Nm>Franz Mustermann</Nm></Pty></Dbtr>
+            <DbtrAcct><Id><IBAN>DE02120300000000202051</IBAN></Id></DbtrAcct>
+          </RltdPties>
+          <RmtInf><Strd><CdtrRefInf><Ref>RF17INV20240001</Ref></CdtrRefInf></Strd></RmtInf>
+        </TxDtls></NtryDtls>
+      </Ntry>
+      <Ntry>
+        <Amt Ccy="EUR">60.00</Amt>
+        <CdtDbtInd>CRDT</CdtDbtInd>
+        <Sts><Cd>BOOK</Cd></Sts>
+        <BookgDt><DtTm>2024-03-02T10:00:00+01:00</DtTm></BookgDt>
+        <NtryDtls>
+          <TxDtls>
+            <Refs><AcctSvcrRef>B1</AcctSvcrRef></Refs>
+            <AmtDtls><TxAmt><Amt Ccy="EUR">20.00</Amt></TxAmt></AmtDtls>
+            <RltdPties><Dbtr><Pty><Nm>Erika Musterfrau</Nm></Pty></Dbtr></RltdPties>
+            <RmtInf><Ustrd>Dinner RF18 5390 0754</Ustrd><Ustrd>7034</Ustrd></RmtInf>
+          </TxDtls>
+          <TxDtls>
+            <Refs><AcctSvcrRef>B2</AcctSvcrRef></Refs>
+            <Amt Ccy="EUR">40.00</Amt>
+            <RmtInf><Ustrd>Rent March</Ustrd></RmtInf>
+          </TxDtls>
+        </NtryDtls>
+      </Ntry>
+      <Ntry>
+        <Amt Ccy="EUR">5.00</Amt>
+        <CdtDbtInd>DBIT</CdtDbtInd>
+        <Sts><Cd>BOOK</Cd></Sts>
+        <BookgDt><Dt>2024-03-02</Dt></BookgDt>
+      </Ntry>
+      <Ntry>
+        <Amt Ccy="EUR">7.00</Amt>
+        <CdtDbtInd>CRDT</CdtDbtInd>
+        <Sts><Cd>PDNG</Cd></Sts>
+        <BookgDt><Dt>2024-03-03</Dt></BookgDt>
+      </Ntry>
+    </Stmt>
+  </BkToCstmrStmt>
+</Document>`
+
+const mt940 = `{1:F01COBADEFFAXXX0000000000}{2:O940}{4:
+:20:STARTUMSE
+:25:37040044/0532013000
+:28C:00001/001
+:60F:C240301EUR1000,00
+:61:2403010301CR12,50NTRFNONREF//2024030100001
+:86:166?00SEPA-GUTSCHRIFT?109310?20EREF+NOTPROVIDED?21SVWZ+RF17INV2024
+0001?22 Invoice?31DE02120300000000202051?32Franz Mustermann
+:61:2403020302DR5,00NMSCNONREF
+:86:Card payment
+:61:2403020302RC3,00NTRFNONREF
+:86:Reversal
+:62F:C240302EUR1007,50
+-}`
+
+const mt940Codes = `:20:940S240301
+:25:NL91ABNA0417164300 EUR
+:28C:1/1
+:60F:C231229EUR0,00
+:61:2312291230C40,NTRFEREF//00000001
+/TRCD/00100/
+:86:/EREF/NOTPROVIDED//ORDP//NAME/Erika Musterfrau/ADDR/Amsterdam/
+/IBAN/NL02ABNA0123456789//REMI/USTD//Rent december/
+:61:240101C1,00NTRFNONREF
+:86:Interest for
+December
+:62F:C240101EUR41,00`
+
+func TestParseCAMT053(t *testing.T) {
+	stmt, err := Parse([]byte("\ufeff" + camt053))
+	if err != nil {
+		t.Fatalf("Parse() returned unexpected error: %v", err)
+	}
+	if stmt.Account != "DE89370400440532013000" || stmt.Currency != "EUR" {
+		t.Errorf("Account = %s %s", stmt.Account, stmt.Currency)
+	}
+
+	want := []Entry{
+		{BookingDate: date("2024-03-01"), Amount: 1250, Currency: "EUR", Credit: true, Reference: "RF17INV20240001", RemitterName: "Franz Mustermann", RemitterIBAN: "DE02120300000000202051", BankReference: "2024030100001"},
+		{BookingDate: time.Date(2024, 3, 2, 10, 0, 0, 0, time.FixedZone("", 3600)), Amount: 2000, Currency: "EUR", Credit: true, Reference: "RF18539007547034", Remittance: "Dinner RF18 5390 0754 7034", RemitterName: "Erika Musterfrau", BankReference: "B1"},
+		{BookingDate: time.Date(2024, 3, 2, 10, 0, 0, 0, time.FixedZone("", 3600)), Amount: 4000, Currency: "EUR", Credit: true, Remittance: "Rent March", BankReference: "B2"},
+		{BookingDate: date("2024-03-02"), Amount: 500, Currency: "EUR"},
+	}
+	checkEntries(t, stmt.Entries, want)
+	if got := len(stmt.Credits()); got != 3 {
+		t.Errorf("Credits() has %d entries, want 3", got)
+	}
+}
+
+func TestParseMT940(t *testing.T) {
+	stmt, err := Parse([]byte(mt940))
+	if err != nil {
+		t.Fatalf("Parse() returned unexpected error: %v", err)
+	}
+	if stmt.Account != "37040044/0532013000" || stmt.Currency != "EUR" {
+		t.Errorf("Account = %s %s", stmt.Account, stmt.Currency)
+	}
+	checkEntries(t, stmt.Entries, []Entry{
+		{BookingDate: date("2024-03-01"), Amount: 1250, Currency: "EUR", Credit: true, Reference: "RF17INV20240001", Remittance: "EREF+NOTPROVIDEDSVWZ+RF17INV20240001 Invoice", RemitterName: "Franz Mustermann", RemitterIBAN: "DE02120300000000202051", BankReference: "2024030100001"},
+		{BookingDate: date("2024-03-02"), Amount: 500, Currency: "EUR", Remittance: "Card payment"},
+	})
+}
+
+func TestParseMT940Codes(t *testing.T) {
+	stmt, err := ParseMT940([]byte(mt940Codes))
+	if err != nil {
+		t.Fatalf("ParseMT940() returned unexpected error: %v", err)
+	}
+	if stmt.Account != "NL91ABNA0417164300EUR" {
+		t.Errorf("Account = %s", stmt.Account)
+	}
+	checkEntries(t, stmt.Entries, []Entry{
+		{BookingDate: date("2023-12-30"), Amount: 4000, Currency: "EUR", Credit: true, Remittance: "Rent december", RemitterName: "Erika Musterfrau", RemitterIBAN: "NL02ABNA0123456789", BankReference: "00000001"},
+		{BookingDate: date("2024-01-01"), Amount: 100, Currency: "EUR", Credit: true, Remittance: "Interest for December"},
+	})
+}
+
+func TestParseErrors(t *testing.T) {
+	tests := []struct {
+		name  string
+		input string
+	}{
+		{name: "Empty", input: ""},
+		{name: "CSV", input: "iban,amount\nDE89370400440532013000,12.50"},
+		{name: "Other XML", input: "<Document><CstmrCdtTrfInitn/></Document>"},
+		{name: "Broken statement line", input: ":20:X\n:25:DE89370400440532013000\n:61:yesterday 12 EUR"},
+		{name: "Three decimals", input: ":20:X\n:61:240301C12,505NTRFNONREF"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := Parse([]byte(tt.input)); err == nil {
+				t.Errorf("Parse() succeeded, want error")
+			}
+		})
+	}
+	if _, err := Parse([]byte("hello")); !errors.Is(err, ErrFormat) {
+		t.Errorf("Parse() error = %v, want %v", err, ErrFormat)
+	}
+}
+
+func checkEntries(t *testing.T, got, want []Entry) {
+	t.Helper()
+	if len(got) != len(want) {
+		t.Fatalf("got %d entries, want %d: %+v", len(got), len(want), got)
+	}
+	for i := range want {
+		if !got[i].BookingDate.Equal(want[i].BookingDate) {
+			t.Errorf("entry %d: BookingDate = %s, want %s", i, got[i].BookingDate, want[i].BookingDate)
+		}
+		got[i].BookingDate, want[i].BookingDate = time.Time{}, time.Time{}
+		if got[i] != want[i] {
+			t.Errorf("entry %d = %+v, want %+v", i, got[i], want[i])
+		}
+	}
+}
+
+func date(s string) time.Time {
+	d, _ := time.Parse(time.DateOnly, s)
+	return d
+}
@@ -0,0 +1,241 @@
+// Package swissqr builds Swiss QR-bills for CH and LI IBANs: the payload of
+// the Swiss QR code as specified by the SIX implementation guidelines
+// (version 2.x) and a printable payment part with receipt.
+package swissqr
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/tapsilat/iban.im/iso11649"
+	"github.com/tapsilat/iban.im/iso13616"
+)
+
+// Errors returned by Payload.
+var (
+	ErrCountry   = errors.New("QR-bills are only available for CH and LI IBANs")
+	ErrAmount    = errors.New("amount must be between 0.01 and 999999999.99")
+	ErrCurrency  = errors.New("QR-bills are only available in CHF and EUR")
+	ErrAddress   = errors.New("the creditor needs a name, postal code, town and country")
+	ErrReference = errors.New("invalid QR-bill reference")
+	ErrField     = errors.New("QR-bill field too long")
+)
+
+// MaxAmount is the largest amount of a QR-bill, in cents.
+const MaxAmount = 99999999999
+
+// Reference types.
+const (
+	ReferenceQRR  = "QRR"
+	ReferenceSCOR = "SCOR"
+	ReferenceNone = "NON"
+)
+
+// Address is a structured postal address.
+type Address struct {
+	Name           string
+	Street         string
+	BuildingNumber string
+	PostalCode     string
+	Town           string
+	// Country is the ISO 3166 two letter code.
+	Country string
+}
+
+// empty reports whether no field of a is set.
+func (a Address) empty() bool {
+	return a == Address{}
+}
+
+// lines renders the seven address elements of the payload.
+func (a Address) lines() []string {
+	if a.empty() {
+		return make([]string, 7)
+	}
+	return []string{"S", a.Name, a.Street, a.BuildingNumber, a.PostalCode, a.Town, strings.ToUpper(a.Country)}
+}
+
+// check validates a structured address.
+func (a Address) check(label string) error {
+	if strings.TrimSpace(a.Name) == "" || strings.TrimSpace(a.PostalCode) == "" || strings.TrimSpace(a.Town) == "" || len(a.Country) != 2 {
+		return ErrAddress
+	}
+	return fields(
+		field{label + " name", a.Name, 70},
+		field{label + " street", a.Street, 70},
+		field{label + " building number", a.BuildingNumber, 16},
+		field{label + " postal code", a.PostalCode, 16},
+		field{label + " town", a.Town, 35},
+	)
+}
+
+// Bill holds the details of a QR-bill.
+type Bill struct {
+	IBAN     string
+	Creditor Address
+	// Amount in cents, 0 leaves the amount to the payer.
+	Amount int64
+	// Currency is CHF or EUR, CHF when empty.
+	Currency string
+	// Debtor is optional; the payer fills it in when empty.
+	Debtor Address
+	// Reference is a 27 digit QR reference for QR-IBANs, or an ISO 11649
+	// creditor reference for other IBANs.
+	Reference string
+	// Message is the unstructured remittance information.
+	Message string
+}
+
+// IsQRIBAN reports whether iban is a QR-IBAN, whose institution identifier
+// lies in the range 30000 to 31999 reserved for payments with a QR
+// reference.
+func IsQRIBAN(iban string) bool {
+	code := iso13616.Normalize(iban)
+	if len(code) < 9 {
+		return false
+	}
+	iid := code[4:9]
+	return iid >= "30000" && iid <= "31999"
+}
+
+// ReferenceType returns QRR, SCOR or NON for the bill's reference.
+func (b Bill) ReferenceType() string {
+	switch ref := normalizeReference(b.Reference); {
+	case ref == "":
+		return ReferenceNone
+	case strings.HasPrefix(ref, "RF"):
+		return ReferenceSCOR
+	default:
+		return ReferenceQRR
+	}
+}
+
+// currency returns the bill currency, defaulting to CHF.
+func (b Bill) currency() string {
+	if b.Currency == "" {
+		return "CHF"
+	}
+	return strings.ToUpper(b.Currency)
+}
+
+// Payload renders the content of the Swiss QR code, version 0200 with
+// UTF-8 text.
+func (b Bill) Payload() (string, error) {
+	iban := iso13616.Normalize(b.IBAN)
+	if err := iso13616.Validate(iban); err != nil {
+		return "", err
+	}
+	if cc := iso13616.CountryCode(iban); cc != "CH" && cc != "LI" {
+		return "", ErrCountry
+	}
+	if err := b.Creditor.check("creditor"); err != nil {
+		return "", err
+	}
+	if !b.Debtor.empty() {
+		if err := b.Debtor.check("debtor"); err != nil {
+			return "", err
+		}
+	}
+	if b.Amount < 0 || b.Amount > MaxAmount {
+		return "", ErrAmount
+	}
+	if b.currency() != "CHF" && b.currency() != "EUR" {
+		return "", ErrCurrency
+	}
+	ref := normalizeReference(b.Reference)
+	if err := checkReference(iban, ref); err != nil {
+		return "", err
+	}
+	if err := fields(field{"message", b.Message, 140}); err != nil {
+		return "", err
+	}
+
+	amount := ""
+	if b.Amount > 0 {
+		amount = fmt.Sprintf("%d.%02d", b.Amount/100, b.Amount%100)
+	}
+	lines := []string{"SPC", "0200", "1", iban}
+	lines = append(lines, b.Creditor.lines()...)
+	// The ultimate creditor is reserved for future use and stays empty
+	lines = append(lines, make([]string, 7)...)
+	lines = append(lines, amount, b.currency())
+	lines = append(lines, b.Debtor.lines()...)
+	lines = append(lines, b.ReferenceType(), ref, b.Message, "EPD")
+	payload := strings.Join(lines, "\n")
+	if utf8.RuneCountInString(payload) > 997 {
+		return "", fmt.Errorf("%w: the QR code holds at most 997 characters", ErrField)
+	}
+	return payload, nil
+}
+
+// checkReference enforces QR references for QR-IBANs and creditor or no
+// references for all other IBANs.
+func checkReference(iban, ref string) error {
+	qrIBAN := IsQRIBAN(iban)
+	switch {
+	case qrIBAN && ref == "":
+		return fmt.Errorf("%w: QR-IBANs need a QR reference", ErrReference)
+	case qrIBAN && strings.HasPrefix(ref, "RF"):
+		return fmt.Errorf("%w: QR-IBANs need a QR reference, not a creditor reference", ErrReference)
+	case qrIBAN:
+		return ValidateQRR(ref)
+	case ref == "":
+		return nil
+	case !strings.HasPrefix(ref, "RF"):
+		return fmt.Errorf("%w: QR references are only allowed with QR-IBANs", ErrReference)
+	}
+	if err := iso11649.Validate(ref); err != nil {
+		return fmt.Errorf("%w: %v", ErrReference, err)
+	}
+	return nil
+}
+
+// ValidateQRR checks a 27 digit QR reference and its modulo 10 recursive
+// check digit.
+func ValidateQRR(s string) error {
+	ref := normalizeReference(s)
+	if len(ref) != 27 {
+		return fmt.Errorf("%w: QR references have 27 digits, got %d", ErrReference, len(ref))
+	}
+	for i := 0; i < len(ref); i++ {
+		if ref[i] < '0' || ref[i] > '9' {
+			return fmt.Errorf("%w: QR references may only contain digits", ErrReference)
+		}
+	}
+	if ref[26] != qrrCheckDigit(ref[:26]) {
+		return fmt.Errorf("%w: QR reference check digit does not match", ErrReference)
+	}
+	return nil
+}
+
+// qrrCheckDigit computes the modulo 10 recursive check digit of digits.
+func qrrCheckDigit(digits string) byte {
+	table := [10]int{0, 9, 4, 6, 8, 2, 7, 1, 3, 5}
+	carry := 0
+	for i := 0; i < len(digits); i++ {
+		carry = table[(carry+int(digits[i]-'0'))%10]
+	}
+	return byte('0' + (10-carry)%10)
+}
+
+// normalizeReference strips spaces and upper-cases s.
+func normalizeReference(s string) string {
+	return iso11649.Normalize(s)
+}
+
+// field is a text element with its maximum length.
+type field struct {
+	label, value string
+	max          int
+}
+
+func fields(list ...field) error {
+	for _, f := range list {
+		if utf8.RuneCountInString(f.value) > f.max {
+			return fmt.Errorf("%w: %s must be at most %d characters", ErrField, f.label, f.max)
+		}
+	}
+	return nil
+}
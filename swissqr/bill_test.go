@@ -0,0 +1,127 @@
+package swissqr
+
+import (
+	"bytes"
+	"errors"
+	"strings"
+	"testing"
+)
+
+var creditor = Address{Name: "Robert Schneider AG", Street: "Rue du Lac", BuildingNumber: "1268", PostalCode: "2501", Town: "Biel", Country: "CH"}
+
+func TestPayload(t *testing.T) {
+	bill := Bill{
+		IBAN:      "CH44 3199 9123 0008 8901 2",
+		Creditor:  creditor,
+		Amount:    194975,
+		Debtor:    Address{Name: "Pia-Maria Rutschmann-Schnyder", Street: "Grosse Marktgasse", BuildingNumber: "28", PostalCode: "9400", Town: "Rorschach", Country: "CH"},
+		Reference: "21 00000 00003 13947 14300 09017",
+		Message:   "Auftrag vom 15.06.2020",
+	}
+	want := strings.Join([]string{
+		"SPC", "0200", "1", "CH4431999123000889012",
+		"S", "Robert Schneider AG", "Rue du Lac", "1268", "2501", "Biel", "CH",
+		"", "", "", "", "", "", "",
+		"1949.75", "CHF",
+		"S", "Pia-Maria Rutschmann-Schnyder", "Grosse Marktgasse", "28", "9400", "Rorschach", "CH",
+		"QRR", "210000000003139471430009017", "Auftrag vom 15.06.2020", "EPD",
+	}, "\n")
+
+	got, err := bill.Payload()
+	if err != nil {
+		t.Fatalf("Payload() returned unexpected error: %v", err)
+	}
+	if got != want {
+		t.Errorf("Payload() = %q, want %q", got, want)
+	}
+}
+
+func TestPayloadReferences(t *testing.T) {
+	tests := []struct {
+		name     string
+		iban     string
+		ref      string
+		currency string
+		refType  string
+		wantErr  error
+	}{
+		{name: "QR-IBAN with QR reference", iban: "CH4431999123000889012", ref: "210000000003139471430009017", refType: ReferenceQRR},
+		{name: "IBAN with creditor reference", iban: "CH9300762011623852957", ref: "RF18539007547034", refType: ReferenceSCOR},
+		{name: "IBAN without reference", iban: "CH9300762011623852957", refType: ReferenceNone},
+		{name: "EUR bill", iban: "CH9300762011623852957", currency: "eur", refType: ReferenceNone},
+		{name: "QR-IBAN without reference", iban: "CH4431999123000889012", wantErr: ErrReference},
+		{name: "QR-IBAN with creditor reference", iban: "CH4431999123000889012", ref: "RF18539007547034", wantErr: ErrReference},
+		{name: "QR reference on an IBAN", iban: "CH9300762011623852957", ref: "210000000003139471430009017", wantErr: ErrReference},
+		{name: "Wrong QR check digit", iban: "CH4431999123000889012", ref: "210000000003139471430009018", wantErr: ErrReference},
+		{name: "Wrong creditor reference", iban: "CH9300762011623852957", ref: "RF19539007547034", wantErr: ErrReference},
+		{name: "Other currency", iban: "CH9300762011623852957", currency: "USD", wantErr: ErrCurrency},
+		{name: "Not a Swiss IBAN", iban: "DE89370400440532013000", wantErr: ErrCountry},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			bill := Bill{IBAN: tt.iban, Creditor: creditor, Reference: tt.ref, Currency: tt.currency}
+			payload, err := bill.Payload()
+			if tt.wantErr != nil {
+				if !errors.Is(err, tt.wantErr) {
+					t.Errorf("Payload() error = %v, want %v", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Payload() returned unexpected error: %v", err)
+			}
+			if !strings.Contains(payload, "\n"+tt.refType+"\n") {
+				t.Errorf("Payload() = %q, want reference type %s", payload, tt.refType)
+			}
+		})
+	}
+}
+
+func TestPayloadAddress(t *testing.T) {
+	bill := Bill{IBAN: "CH9300762011623852957", Creditor: Address{Name: "Robert Schneider AG"}}
+	if _, err := bill.Payload(); !errors.Is(err, ErrAddress) {
+		t.Errorf("Payload() error = %v, want %v", err, ErrAddress)
+	}
+}
+
+func TestIsQRIBAN(t *testing.T) {
+	tests := []struct {
+		iban string
+		want bool
+	}{
+		{iban: "CH4431999123000889012", want: true},
+		{iban: "CH44 3000 0123 0008 8901 2", want: true},
+		{iban: "CH9300762011623852957", want: false},
+		{iban: "CH93", want: false},
+	}
+	for _, tt := range tests {
+		if got := IsQRIBAN(tt.iban); got != tt.want {
+			t.Errorf("IsQRIBAN(%s) = %v, want %v", tt.iban, got, tt.want)
+		}
+	}
+}
+
+func TestPDF(t *testing.T) {
+	bill := Bill{IBAN: "CH4431999123000889012", Creditor: creditor, Amount: 194975, Reference: "210000000003139471430009017", Message: "Auftrag vom 15.06.2020"}
+	var out bytes.Buffer
+	if err := bill.PDF(&out); err != nil {
+		t.Fatalf("PDF() returned unexpected error: %v", err)
+	}
+	if !bytes.HasPrefix(out.Bytes(), []byte("%PDF-")) {
+		t.Error("PDF() did not write a PDF document")
+	}
+
+	if err := (Bill{IBAN: "CH4431999123000889012", Creditor: creditor}).PDF(&out); err == nil {
+		t.Error("PDF() should reject a QR-IBAN bill without reference")
+	}
+}
+
+func TestFormatAmount(t *testing.T) {
+	tests := map[int64]string{5: "0.05", 194975: "1 949.75", 99999999999: "999 999 999.99", 100000: "1 000.00"}
+	for cents, want := range tests {
+		if got := formatAmount(cents); got != want {
+			t.Errorf("formatAmount(%d) = %s, want %s", cents, got, want)
+		}
+	}
+}
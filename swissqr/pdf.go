@@ -0,0 +1,227 @@
+package swissqr
+
+import (
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/jung-kurt/gofpdf"
+	"github.com/tapsilat/iban.im/iso11649"
+	"github.com/tapsilat/iban.im/iso13616"
+	"github.com/tapsilat/iban.im/qrimage"
+)
+
+// Dimensions of the payment part with receipt in mm, as laid down by the
+// SIX style guide.
+const (
+	pageWidth    = 210
+	pageHeight   = 105
+	receiptWidth = 62
+	margin       = 5
+	qrSide       = 46
+	qrTop        = 17
+	amountTop    = 68
+	columnLeft   = 118
+)
+
+// PDF writes the QR-bill as a printable A6 landscape payment part with the
+// receipt on its left.
+func (b Bill) PDF(w io.Writer) error {
+	payload, err := b.Payload()
+	if err != nil {
+		return err
+	}
+	modules, err := qrimage.Modules(payload)
+	if err != nil {
+		return err
+	}
+
+	pdf := gofpdf.NewCustom(&gofpdf.InitType{
+		OrientationStr: "P",
+		UnitStr:        "mm",
+		Size:           gofpdf.SizeType{Wd: pageWidth, Ht: pageHeight},
+	})
+	pdf.SetMargins(0, 0, 0)
+	pdf.SetAutoPageBreak(false, 0)
+	pdf.AddPage()
+	text := pdf.UnicodeTranslatorFromDescriptor("")
+
+	// Perforation lines
+	pdf.SetLineWidth(0.2)
+	pdf.SetDashPattern([]float64{1, 1}, 0)
+	pdf.Line(0, 0, pageWidth, 0)
+	pdf.Line(receiptWidth, 0, receiptWidth, pageHeight)
+	pdf.SetDashPattern(nil, 0)
+
+	creditor := append([]string{iso13616.PrintFormat(b.IBAN)}, b.Creditor.display()...)
+	ref := b.printReference()
+
+	// Receipt
+	r := &column{pdf: pdf, text: text, x: margin, y: margin, width: receiptWidth - 2*margin, headingSize: 6, valueSize: 8}
+	r.title("Receipt")
+	r.y = 12
+	r.section("Account / Payable to", creditor...)
+	if ref != "" {
+		r.section("Reference", ref)
+	}
+	if b.Debtor.empty() {
+		r.heading("Payable by (name/address)")
+		corners(pdf, r.x, r.y, 52, 20)
+	} else {
+		r.section("Payable by", b.Debtor.display()...)
+	}
+	amountBlock(pdf, text, margin, amountTop, 6, 8, b.currency(), b.Amount, 27, 30, 10)
+	pdf.SetFont("Helvetica", "B", 6)
+	pdf.SetXY(margin, 82)
+	pdf.CellFormat(receiptWidth-2*margin, 3, text("Acceptance point"), "", 0, "R", false, 0, "")
+
+	// Payment part
+	p := &column{pdf: pdf, text: text, x: receiptWidth + margin, y: margin, width: 51, headingSize: 8, valueSize: 10}
+	p.title("Payment part")
+	drawQR(pdf, modules, receiptWidth+margin, qrTop)
+	amountBlock(pdf, text, receiptWidth+margin, amountTop, 8, 10, b.currency(), b.Amount, 78, 40, 15)
+
+	info := &column{pdf: pdf, text: text, x: columnLeft, y: margin, width: pageWidth - columnLeft - margin, headingSize: 8, valueSize: 10}
+	info.section("Account / Payable to", creditor...)
+	if ref != "" {
+		info.section("Reference", ref)
+	}
+	if b.Message != "" {
+		info.section("Additional information", b.Message)
+	}
+	if b.Debtor.empty() {
+		info.heading("Payable by (name/address)")
+		corners(pdf, info.x, info.y, 65, 25)
+	} else {
+		info.section("Payable by", b.Debtor.display()...)
+	}
+
+	return pdf.Output(w)
+}
+
+// display renders the address as printed on the bill.
+func (a Address) display() []string {
+	lines := []string{a.Name}
+	if street := strings.TrimSpace(a.Street + " " + a.BuildingNumber); street != "" {
+		lines = append(lines, street)
+	}
+	return append(lines, strings.TrimSpace(a.PostalCode+" "+a.Town))
+}
+
+// printReference groups QR references as 2 + 5 x 5 digits and creditor
+// references in blocks of four.
+func (b Bill) printReference() string {
+	ref := normalizeReference(b.Reference)
+	switch b.ReferenceType() {
+	case ReferenceQRR:
+		parts := []string{ref[:2]}
+		for i := 2; i < len(ref); i += 5 {
+			parts = append(parts, ref[i:min(i+5, len(ref))])
+		}
+		return strings.Join(parts, " ")
+	case ReferenceSCOR:
+		return iso11649.PrintFormat(ref)
+	}
+	return ""
+}
+
+// column writes headings and values top down.
+type column struct {
+	pdf                    *gofpdf.Fpdf
+	text                   func(string) string
+	x, y, width            float64
+	headingSize, valueSize float64
+}
+
+func (c *column) title(s string) {
+	c.pdf.SetFont("Helvetica", "B", 11)
+	c.pdf.SetXY(c.x, c.y)
+	c.pdf.CellFormat(c.width, 5, c.text(s), "", 0, "L", false, 0, "")
+}
+
+func (c *column) heading(s string) {
+	c.pdf.SetFont("Helvetica", "B", c.headingSize)
+	c.pdf.SetXY(c.x, c.y)
+	c.pdf.CellFormat(c.width, c.headingSize*0.45, c.text(s), "", 0, "L", false, 0, "")
+	c.y += c.headingSize * 0.45
+}
+
+func (c *column) section(heading string, values ...string) {
+	c.heading(heading)
+	c.pdf.SetFont("Helvetica", "", c.valueSize)
+	for _, v := range values {
+		c.pdf.SetXY(c.x, c.y)
+		c.pdf.MultiCell(c.width, c.valueSize*0.4, c.text(v), "", "L", false)
+		c.y = c.pdf.GetY()
+	}
+	c.y += c.valueSize * 0.35
+}
+
+// amountBlock writes currency and amount, or a box for the payer to fill in
+// the amount.
+func amountBlock(pdf *gofpdf.Fpdf, text func(string) string, x, y, headingSize, valueSize float64, currency string, amount int64, boxX, boxW, boxH float64) {
+	pdf.SetFont("Helvetica", "B", headingSize)
+	pdf.Text(x, y+2, text("Currency"))
+	pdf.Text(x+15, y+2, text("Amount"))
+	pdf.SetFont("Helvetica", "", valueSize)
+	pdf.Text(x, y+6, currency)
+	if amount > 0 {
+		pdf.Text(x+15, y+6, formatAmount(amount))
+	} else {
+		corners(pdf, boxX, y+3, boxW, boxH)
+	}
+}
+
+// formatAmount renders cents with a space as thousands separator, as in
+// "1 949.75".
+func formatAmount(cents int64) string {
+	units := fmt.Sprintf("%d", cents/100)
+	var groups []string
+	for len(units) > 3 {
+		groups = append([]string{units[len(units)-3:]}, groups...)
+		units = units[:len(units)-3]
+	}
+	groups = append([]string{units}, groups...)
+	return fmt.Sprintf("%s.%02d", strings.Join(groups, " "), cents%100)
+}
+
+// corners draws the corner marks of a field left blank for the payer.
+func corners(pdf *gofpdf.Fpdf, x, y, w, h float64) {
+	const arm = 3
+	pdf.SetLineWidth(0.3)
+	pdf.Line(x, y, x+arm, y)
+	pdf.Line(x, y, x, y+arm)
+	pdf.Line(x+w-arm, y, x+w, y)
+	pdf.Line(x+w, y, x+w, y+arm)
+	pdf.Line(x, y+h-arm, x, y+h)
+	pdf.Line(x, y+h, x+arm, y+h)
+	pdf.Line(x+w-arm, y+h, x+w, y+h)
+	pdf.Line(x+w, y+h-arm, x+w, y+h)
+}
+
+// drawQR draws the code as vector modules with the Swiss cross on top.
+func drawQR(pdf *gofpdf.Fpdf, modules [][]bool, x, y float64) {
+	size := qrSide / float64(len(modules))
+	pdf.SetFillColor(0, 0, 0)
+	for row, line := range modules {
+		for col := 0; col < len(line); col++ {
+			if !line[col] {
+				continue
+			}
+			// One rectangle per run of dark modules avoids hairline gaps
+			start := col
+			for col < len(line) && line[col] {
+				col++
+			}
+			pdf.Rect(x+float64(start)*size, y+float64(row)*size, float64(col-start)*size, size, "F")
+		}
+	}
+	for _, r := range qrimage.SwissCross(x+qrSide/2, y+qrSide/2, 7) {
+		if r.Dark {
+			pdf.SetFillColor(0, 0, 0)
+		} else {
+			pdf.SetFillColor(255, 255, 255)
+		}
+		pdf.Rect(r.X, r.Y, r.W, r.H, "F")
+	}
+}
@@ -0,0 +1,49 @@
+package main
+
+import (
+	"fmt"
+	"log"
+
+	"github.com/tapsilat/iban.im/config"
+	"github.com/tapsilat/iban.im/iso13616"
+	"github.com/tapsilat/iban.im/model"
+	"github.com/tapsilat/iban.im/paymethod"
+)
+
+// Backfill: rewrite every stored IBAN in electronic format (upper-case, no
+// spaces or separators). New and updated rows are normalized by the model.
+func main() {
+	cfg, err := config.GetConfig()
+	if err != nil {
+		log.Fatalf("Failed to load config: %v", err)
+	}
+
+	// Initialize database
+	config.InitDB(cfg)
+
+	// Other payment methods are normalized by their own rules
+	var ibans []model.Iban
+	if err := config.DB.Where("method = ?", paymethod.IBAN).Find(&ibans).Error; err != nil {
+		log.Fatalf("Failed to load IBANs: %v", err)
+	}
+
+	updated, invalid := 0, 0
+	for _, iban := range ibans {
+		normalized := iso13616.Normalize(iban.Text)
+		if err := iso13616.Validate(normalized); err != nil {
+			invalid++
+			log.Printf("IBAN %d (%s) is not valid: %v", iban.IbanID, iban.Handle, err)
+		}
+		if normalized == iban.Text {
+			continue
+		}
+		// UpdateColumn skips hooks and keeps UpdatedAt untouched
+		if err := config.DB.Model(&model.Iban{}).Where("iban_id = ?", iban.IbanID).UpdateColumn("text", normalized).Error; err != nil {
+			log.Printf("Failed to update IBAN %d: %v", iban.IbanID, err)
+			continue
+		}
+		updated++
+	}
+
+	fmt.Printf("Checked %d IBANs, normalized %d, %d invalid\n", len(ibans), updated, invalid)
+}
@@ -0,0 +1,212 @@
+package main
+
+import (
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"log"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/tapsilat/iban.im/iso13616"
+)
+
+// Import the SWIFT IBAN registry (the tab separated IBAN_Registry.txt or a
+// CSV export) into the rules file used by the iso13616 package:
+//
+//	go run ./tools/registry -in IBAN_Registry.txt -version 2025-07-01
+func main() {
+	in := flag.String("in", "", "SWIFT IBAN registry (.txt or .csv)")
+	out := flag.String("out", "iso13616/registry.json", "rules file to write")
+	version := flag.String("version", time.Now().Format("2006-01-02"), "version stored in the rules file")
+	flag.Parse()
+
+	if *in == "" {
+		flag.Usage()
+		os.Exit(2)
+	}
+
+	data, err := os.ReadFile(*in)
+	if err != nil {
+		log.Fatalf("Failed to read registry: %v", err)
+	}
+	entries, err := parseRegistry(data)
+	if err != nil {
+		log.Fatalf("Failed to parse registry: %v", err)
+	}
+
+	// Account and check digit positions are not part of the registry, so
+	// layouts curated in the current rules file are kept when still valid
+	previous := map[string]iso13616.Country{}
+	if current, err := os.ReadFile(*out); err == nil {
+		if reg, err := iso13616.ParseRegistry(current); err == nil {
+			for _, c := range reg.Countries {
+				previous[c.Code] = c
+			}
+		}
+	}
+
+	reg := &iso13616.Registry{
+		Version: *version,
+		Source:  "SWIFT IBAN Registry (" + filepath.Base(*in) + ")",
+	}
+	for _, e := range entries {
+		c, err := e.country()
+		if err != nil {
+			log.Fatalf("Failed to import %s: %v", e["iban prefix country code (iso 3166)"], err)
+		}
+		if p, ok := previous[c.Code]; ok && p.Layout != "" && iso13616.StructureLength(p.Layout)+4 == c.Length {
+			c.Layout = p.Layout
+		}
+		reg.Countries = append(reg.Countries, c)
+	}
+	sort.Slice(reg.Countries, func(i, j int) bool { return reg.Countries[i].Code < reg.Countries[j].Code })
+
+	output, err := json.MarshalIndent(reg, "", "  ")
+	if err != nil {
+		log.Fatalf("Failed to encode rules: %v", err)
+	}
+	// Make sure the application will accept what we write
+	if _, err := iso13616.ParseRegistry(output); err != nil {
+		log.Fatalf("Generated rules are not valid: %v", err)
+	}
+	if err := os.WriteFile(*out, append(output, '\n'), 0o644); err != nil {
+		log.Fatalf("Failed to write rules: %v", err)
+	}
+
+	fmt.Printf("Wrote %d countries to %s (version %s)\n", len(reg.Countries), *out, reg.Version)
+}
+
+// entry maps normalized registry data element names to the values of one
+// country.
+type entry map[string]string
+
+// parseRegistry reads the registry in either orientation: the official text
+// file lists data elements as rows and countries as columns, CSV exports
+// usually have one country per row.
+func parseRegistry(data []byte) ([]entry, error) {
+	data = bytes.TrimPrefix(data, []byte("\xef\xbb\xbf"))
+	reader := csv.NewReader(bytes.NewReader(data))
+	reader.FieldsPerRecord = -1
+	reader.LazyQuotes = true
+	if line, _, _ := bytes.Cut(data, []byte("\n")); bytes.Count(line, []byte("\t")) > bytes.Count(line, []byte(",")) {
+		reader.Comma = '\t'
+	}
+	records, err := reader.ReadAll()
+	if err != nil {
+		return nil, err
+	}
+
+	const codeLabel = "iban prefix country code (iso 3166)"
+	var entries []entry
+	for _, row := range records {
+		if len(row) > 0 && label(row[0]) == codeLabel {
+			// Countries as columns
+			for col := 1; col < len(row); col++ {
+				e := entry{}
+				for _, r := range records {
+					if len(r) > col && len(r) > 0 {
+						e[label(r[0])] = strings.TrimSpace(r[col])
+					}
+				}
+				if e[codeLabel] != "" {
+					entries = append(entries, e)
+				}
+			}
+			return entries, nil
+		}
+	}
+
+	// Countries as rows below a header
+	for i, row := range records {
+		header := -1
+		for j, cell := range row {
+			if label(cell) == codeLabel {
+				header = j
+			}
+		}
+		if header < 0 {
+			continue
+		}
+		for _, r := range records[i+1:] {
+			e := entry{}
+			for j, cell := range r {
+				if j < len(row) {
+					e[label(row[j])] = strings.TrimSpace(cell)
+				}
+			}
+			if e[codeLabel] != "" {
+				entries = append(entries, e)
+			}
+		}
+		return entries, nil
+	}
+	return nil, fmt.Errorf("no %q data element found", codeLabel)
+}
+
+// country converts a registry entry into IBAN rules.
+func (e entry) country() (iso13616.Country, error) {
+	c := iso13616.Country{
+		Code: strings.ToUpper(e["iban prefix country code (iso 3166)"]),
+		Name: e["name of country"],
+		BBAN: strings.ReplaceAll(e["bban structure"], " ", ""),
+		SEPA: strings.EqualFold(e["sepa country"], "yes"),
+	}
+	length, err := strconv.Atoi(strings.TrimSpace(e["iban length"]))
+	if err != nil {
+		return c, fmt.Errorf("invalid IBAN length %q", e["iban length"])
+	}
+	c.Length = length
+	if iso13616.StructureLength(c.BBAN)+4 != c.Length {
+		return c, fmt.Errorf("BBAN structure %q does not match length %d", c.BBAN, c.Length)
+	}
+
+	// Everything that is neither bank nor branch identifier is taken as
+	// account number until a curated layout says otherwise
+	roles := []byte(strings.Repeat("a", c.Length-4))
+	mark(roles, e["bank identifier position within the bban"], 'b')
+	mark(roles, e["branch identifier position within the bban"], 's')
+	c.Layout = compress(roles)
+	return c, nil
+}
+
+// mark assigns role to the 1-based inclusive range such as "1-4".
+func mark(roles []byte, positions string, role byte) {
+	from, to, ok := strings.Cut(strings.TrimSpace(positions), "-")
+	if !ok {
+		return
+	}
+	start, err1 := strconv.Atoi(strings.TrimSpace(from))
+	end, err2 := strconv.Atoi(strings.TrimSpace(to))
+	if err1 != nil || err2 != nil || start < 1 || end > len(roles) || start > end {
+		return
+	}
+	for i := start - 1; i < end; i++ {
+		roles[i] = role
+	}
+}
+
+// compress turns per position roles into layout notation, "bbbbaa" -> "4b2a".
+func compress(roles []byte) string {
+	var b strings.Builder
+	for i := 0; i < len(roles); {
+		j := i
+		for j < len(roles) && roles[j] == roles[i] {
+			j++
+		}
+		fmt.Fprintf(&b, "%d%c", j-i, roles[i])
+		i = j
+	}
+	return b.String()
+}
+
+// label normalizes a data element name for lookups.
+func label(s string) string {
+	return strings.ToLower(strings.Join(strings.Fields(s), " "))
+}
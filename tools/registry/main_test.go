@@ -0,0 +1,81 @@
+package main
+
+import (
+	"strings"
+	"testing"
+)
+
+const registryText = "Data element\tExample\tExample\n" +
+	"Name of country\tGermany\tTurkey\n" +
+	"IBAN prefix country code (ISO 3166)\tDE\tTR\n" +
+	"SEPA country\tYes\tNo\n" +
+	"SEPA country also includes\tN/A\tN/A\n" +
+	"BBAN structure \t8!n10!n\t5!n1!n16!c\n" +
+	"Bank identifier position within the BBAN\t1-8\t1-5\n" +
+	"Branch identifier position within the BBAN\tN/A\tN/A\n" +
+	"IBAN length\t22\t26\n"
+
+const registryCSV = "Name of country,IBAN prefix country code (ISO 3166),SEPA country,BBAN structure,Bank identifier position within the BBAN,Branch identifier position within the BBAN,IBAN length\n" +
+	"United Kingdom,GB,Yes,4!a6!n8!n,1-4,5-10,22\n"
+
+func TestParseRegistryText(t *testing.T) {
+	entries, err := parseRegistry([]byte("\xef\xbb\xbf" + registryText))
+	if err != nil {
+		t.Fatalf("parseRegistry() returned unexpected error: %v", err)
+	}
+	if len(entries) != 2 {
+		t.Fatalf("Expected 2 countries, got %d", len(entries))
+	}
+
+	de, err := entries[0].country()
+	if err != nil {
+		t.Fatalf("country() returned unexpected error: %v", err)
+	}
+	if de.Code != "DE" || de.Name != "Germany" || de.Length != 22 || de.BBAN != "8!n10!n" || !de.SEPA {
+		t.Errorf("Unexpected DE rules: %+v", de)
+	}
+	if de.Layout != "8b10a" {
+		t.Errorf("DE layout = %s, want 8b10a", de.Layout)
+	}
+
+	tr, err := entries[1].country()
+	if err != nil {
+		t.Fatalf("country() returned unexpected error: %v", err)
+	}
+	if tr.Code != "TR" || tr.SEPA || tr.Length != 26 || tr.Layout != "5b17a" {
+		t.Errorf("Unexpected TR rules: %+v", tr)
+	}
+}
+
+func TestParseRegistryCSV(t *testing.T) {
+	entries, err := parseRegistry([]byte(registryCSV))
+	if err != nil {
+		t.Fatalf("parseRegistry() returned unexpected error: %v", err)
+	}
+	if len(entries) != 1 {
+		t.Fatalf("Expected 1 country, got %d", len(entries))
+	}
+	gb, err := entries[0].country()
+	if err != nil {
+		t.Fatalf("country() returned unexpected error: %v", err)
+	}
+	if gb.Code != "GB" || gb.Layout != "4b6s8a" || !gb.SEPA {
+		t.Errorf("Unexpected GB rules: %+v", gb)
+	}
+}
+
+func TestParseRegistryLengthMismatch(t *testing.T) {
+	entries, err := parseRegistry([]byte(strings.Replace(registryCSV, ",22\n", ",24\n", 1)))
+	if err != nil {
+		t.Fatalf("parseRegistry() returned unexpected error: %v", err)
+	}
+	if _, err := entries[0].country(); err == nil {
+		t.Error("Expected error for BBAN structure not matching IBAN length")
+	}
+}
+
+func TestParseRegistryWithoutCountryCodes(t *testing.T) {
+	if _, err := parseRegistry([]byte("a,b\n1,2\n")); err == nil {
+		t.Error("Expected error for file without country codes")
+	}
+}
@@ -51,7 +51,7 @@ func main() {
 			IsPrivate:   false,
 		},
 		{
-			Text:        "TR420010009999901234567891",
+			Text:        "TR050010009999901234567891",
 			Description: "Savings account",
 			Handle:      "savings",
 			OwnerID:     user.UserID,
@@ -60,7 +60,7 @@ func main() {
 			IsPrivate:   false,
 		},
 		{
-			Text:        "TR520010009999901234567892",
+			Text:        "TR750010009999901234567892",
 			Description: "Private account",
 			Handle:      "private",
 			Password:    "secret123",
@@ -3,21 +3,25 @@ import axios from 'axios'
 import router from './router'
 
 const queryIbanUpdate = `
-                    mutation ($id: ID!, $text: String!, $password: String!, $handle: String!, $isPrivate: Boolean!) {
-                        ibanUpdate(id: $id, text: $text, password: $password, handle: $handle isPrivate: $isPrivate) {
+                    mutation ($id: ID!, $method: String, $text: String!, $routingCode: String, $bic: String, $presetAmounts: [String!], $currency: String, $holderName: String, $accountType: String, $password: String!, $handle: String!, $isPrivate: Boolean!) {
+                        ibanUpdate(id: $id, method: $method, text: $text, routingCode: $routingCode, bic: $bic, presetAmounts: $presetAmounts, currency: $currency, holderName: $holderName, accountType: $accountType, password: $password, handle: $handle isPrivate: $isPrivate) {
                             ok,
                             error,
-                            iban {id}
+                            iban {id},
+                            suggestions {iban, printFormat, reason},
+                            warnings
                         }
                     }
                 `;
 
 const queryIbanCreate = `
-                    mutation ($text: String!, $password: String!, $handle: String!, $isPrivate: Boolean!) {
-                        ibanNew(text: $text, password: $password, handle: $handle isPrivate: $isPrivate) {
+                    mutation ($method: String, $text: String!, $routingCode: String, $bic: String, $presetAmounts: [String!], $currency: String, $holderName: String, $accountType: String, $password: String!, $handle: String!, $isPrivate: Boolean!) {
+                        ibanNew(method: $method, text: $text, routingCode: $routingCode, bic: $bic, presetAmounts: $presetAmounts, currency: $currency, holderName: $holderName, accountType: $accountType, password: $password, handle: $handle isPrivate: $isPrivate) {
                             ok,
                             error,
-                            iban {id}
+                            iban {id},
+                            suggestions {iban, printFormat, reason},
+                            warnings
                         }
                     }
                 `;
@@ -150,7 +154,7 @@ export default createStore({
             commit('SET_IS_LOADED', false);
             axios.post('/graph',{
                 query: `{
-                 getMyIbans{ok,error,iban{id,handle,text,isPrivate}}
+                 getMyIbans{ok,error,iban{id,handle,method,text,routingCode,bic,currency,holderName,accountType,presetAmounts,isPrivate}}
                 }`,
             }).then(({data}) => {
                 //console.log('data');
@@ -168,7 +172,7 @@ export default createStore({
             const response = await axios.post('/graph',{
                 query: `{
                       getMyProfile {
-                        user {id,firstName,lastName,handle,bio,email},
+                        user {id,firstName,lastName,handle,bio,email,street,buildingNumber,postalCode,town,country},
                         ok,
                         error
                       }
@@ -190,7 +194,7 @@ export default createStore({
             axios.post('/graph',{
                 query: `{
                       getMyProfile {
-                        user {id,firstName,lastName,handle,bio,email},
+                        user {id,firstName,lastName,handle,bio,email,street,buildingNumber,postalCode,town,country},
                         ok,
                         error
                       }
@@ -236,8 +240,8 @@ export default createStore({
         fetchSingleProfile({commit},variables) {
             commit('SET_IS_LOADED', false);
             axios.post('/graph',{
-                query: `query GetProfile($username: String!) {
-                    getProfile(username: $username) {
+                query: `query GetProfile($username: String!, $currency: String) {
+                    getProfile(username: $username, currency: $currency) {
                         ok,
                         error,
                         user {
@@ -251,6 +255,8 @@ export default createStore({
                         iban {
                             id,
                             text,
+                            currency,
+                            accountType,
                             isPrivate,
                             handle
                         }
@@ -293,6 +299,25 @@ export default createStore({
                 commit('SET_IS_LOADED', true);
             });
         },
+        suggestIbanCorrections(_, text) {
+            return axios.post('/graph', {
+                query: `query SuggestIbanCorrections($text: String!) {
+                    suggestIbanCorrections(text: $text) {
+                        ok,
+                        error,
+                        suggestions {iban, printFormat, reason}
+                    }
+                }`,
+                variables: {
+                    text
+                }
+            }).then(({data}) => {
+                if(data.errors) {
+                    return null;
+                }
+                return data.data.suggestIbanCorrections;
+            });
+        },
         changePassword({commit},credentials) {
             //console.log(credentials);
             commit('SET_IS_LOADED', false);
@@ -320,8 +345,8 @@ export default createStore({
             commit('SET_IS_LOADED', false);
             axios.post('/graph', {
                 query: `
-                    mutation ($bio: String!, $handle: String!) {
-                        changeProfile(bio: $bio, handle: $handle) {
+                    mutation ($bio: String!, $handle: String!, $street: String, $buildingNumber: String, $postalCode: String, $town: String, $country: String) {
+                        changeProfile(bio: $bio, handle: $handle, street: $street, buildingNumber: $buildingNumber, postalCode: $postalCode, town: $town, country: $country) {
                             ok,
                             error
                         }
@@ -333,6 +358,10 @@ export default createStore({
                     commit('SET_ERROR', data.errors[0].message);
                     return;
                 }
+                if(!data.data.changeProfile.ok){
+                    commit('SET_ERROR', data.data.changeProfile.error);
+                    return;
+                }
                 //console.log(data)
             }).finally(() => {
                 commit('SET_IS_LOADED', true);